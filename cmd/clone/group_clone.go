@@ -1,7 +1,7 @@
 package clone
 
 import (
-	"bytes"
+	"context"
 	"encoding/csv"
 	"errors"
 	"fmt"
@@ -12,15 +12,15 @@ import (
 	"github.com/vbauerster/mpb/v7"
 	"github.com/vbauerster/mpb/v7/decor"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
-	"sync"
+	"wildfire/cmd/dashboard"
 	"wildfire/pkg"
-	"wildfire/pkg/project_repository"
+	"wildfire/wildfire"
 )
 
 var someProjects bool
+var tui bool
 
 type UserInput interface {
 	PickBool(msg string) (bool, error)
@@ -68,30 +68,50 @@ func (s SurveyUserInput) PickMultiple(msg string, options []string) ([]string, e
 }
 
 type pullGroupExecutor struct {
-	projectService pkg.ProjectService
-	groupService   pkg.GroupService
-	repoService    project_repository.ProjectRepositoryService
-	userInput      UserInput
+	client    *wildfire.Client
+	userInput UserInput
+	// cmd records the commands run in the clones in the history, see pkg.RecordRun.
+	cmd *cobra.Command
+	// context selects the projects to clone, see pkg.SelectProjects.
+	context context.Context
+	// history records the outcome of cloning every project.
+	history *pkg.HistoryEntry
+	// checkpoint records the projects which have been cloned and the commands run in them, so an interrupted run
+	// can be resumed.
+	checkpoint *pkg.Checkpoint
+	// cloneFailed is set when some projects could not be cloned. The completed clones and the checkpoint are kept,
+	// so the failed projects can be cloned again when the run is resumed.
+	cloneFailed bool
 }
 
 func (executor *pullGroupExecutor) Execute(groupName string, path string, partialClone bool) error {
-	group := executor.groupService.GetGroup(groupName)
-	if group == nil {
+	group, err := executor.client.GetGroup(executor.context, groupName)
+	if errors.Is(err, wildfire.ErrNotFound) {
 		return emoji.Errorf("Group '%s' does not exist in configuration.", groupName)
 	}
+	if err != nil {
+		return err
+	}
 
-	if partialClone == true {
-		projects, err := executor.pickProjectsFromGroup(group)
+	projects, err := executor.client.GetGroupProjects(executor.context, groupName)
+	if err != nil {
+		return err
+	}
+	projects = pkg.SelectProjects(executor.context, projects)
+	resumed := pkg.GetCheckpoint(executor.context) != nil
+
+	if partialClone == true && resumed == false {
+		selectedProjects, err := executor.pickProjects(projects)
 
 		if err != nil {
 			return err
 		}
 
-		_, _ = emoji.Println(":star: Selected: ", strings.Join(projects, ", "))
-		*group = projects
+		_, _ = emoji.Println(":star: Selected: ", strings.Join(selectedProjects, ", "))
+		projects = selectedProjects
 	}
 
-	if _, err := os.Stat(path); !os.IsNotExist(err) {
+	if _, err := os.Stat(path); !os.IsNotExist(err) && resumed == false {
 		remove, err := executor.userInput.PickBool("Folder already exists. Clear path and try again?")
 		if err != nil {
 			return err
@@ -107,27 +127,33 @@ func (executor *pullGroupExecutor) Execute(groupName string, path string, partia
 		}
 	}
 
-	if err := executor.cloneGroupProjects(group, path); err != nil {
-		if err := executor.clearPath(path); err != nil {
-			err = fmt.Errorf("%s\n%s", err.Error(), err.Error())
-		}
+	step := executor.checkpoint.Step("clone", "", projects)
+	projects = step.Projects
+	executor.history.Projects = append(executor.history.Projects, executor.checkpoint.Completed(step)...)
 
+	if err := executor.cloneGroupProjects(groupName, step, path); err != nil {
 		return err
 	}
 
 	fmt.Println(emoji.Sprintf(":ocean: Projects have been cloned to '%s'", path))
 
+	for _, step := range executor.checkpoint.Remaining() {
+		emoji.Printf(":repeat: Resuming '%s' in %d projects\n", step.Command, len(executor.checkpoint.Incomplete(step)))
+		if _, err := executor.executeCommand(groupName, step); err != nil {
+			return err
+		}
+	}
+
+	actions := []string{"Run command", "Clear clones and Exit", "Exit"}
+	defaultCommandAction := fmt.Sprintf("Run default command '%s'", group.Command)
+	if group.Command != "" {
+		actions = append([]string{defaultCommandAction}, actions...)
+	}
+
 	var repoActionScope string
 
 	for repoActionScope != "Exit" {
-		action, err := executor.userInput.PickOne(
-			"Do you wish to take any further action?",
-			[]string{
-				"Run command",
-				"Clear clones and Exit",
-				"Exit",
-			},
-		)
+		action, err := executor.userInput.PickOne("Do you wish to take any further action?", actions)
 
 		if err == terminal.InterruptErr {
 			return nil
@@ -136,8 +162,13 @@ func (executor *pullGroupExecutor) Execute(groupName string, path string, partia
 			return err
 		}
 
-		if action == "Run command" {
-			if err := executor.runCommand(*group, path); err != nil && err != terminal.InterruptErr {
+		if action == "Run command" || action == defaultCommandAction {
+			command := ""
+			if action == defaultCommandAction {
+				command = group.Command
+			}
+
+			if err := executor.runCommand(groupName, projects, command); err != nil && err != terminal.InterruptErr {
 				return err
 			}
 
@@ -149,6 +180,9 @@ func (executor *pullGroupExecutor) Execute(groupName string, path string, partia
 			if err := executor.clearPath(path); err != nil {
 				return err
 			}
+			if err := executor.client.DeleteWorkspace(executor.context, groupName); err != nil {
+				return err
+			}
 
 			break
 		}
@@ -161,50 +195,99 @@ func (executor *pullGroupExecutor) Execute(groupName string, path string, partia
 	return nil
 }
 
-func (executor *pullGroupExecutor) pickProjectsFromGroup(group *pkg.GroupConfig) ([]string, error) {
-	projects, err := executor.userInput.PickMultiple("Select projects:", *group)
+// plan writes the projects which would be cloned and sets the workspace, without cloning them.
+func (executor *pullGroupExecutor) plan(cmd *cobra.Command, groupName string, path string, partialClone bool) error {
+	group, err := executor.client.GetGroup(executor.context, groupName)
+	if err != nil {
+		return err
+	}
+
+	projects, err := executor.client.GetGroupProjects(executor.context, groupName)
+	if err != nil {
+		return err
+	}
+	projects = pkg.SelectProjects(executor.context, projects)
+
+	if partialClone {
+		if projects, err = executor.pickProjects(projects); err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		emoji.Fprintf(cmd.ErrOrStderr(), ":warning: Folder '%s' already exists, you would be asked to clear it.\n", path)
+	}
+
+	var changes []pkg.PlannedChange
+	for _, projectName := range projects {
+		clone := pkg.Clone{Project: projectName, Path: filepath.FromSlash(fmt.Sprintf("%s/%s", path, projectName))}
+		var project *pkg.ProjectConfig
+		if found, err := executor.client.GetProject(executor.context, projectName); err == nil {
+			project = &found
+		}
+
+		changes = append(changes, pkg.PlanClone(clone, project, group.Branch))
+	}
+
+	emoji.Fprintln(cmd.ErrOrStderr(), ":memo: Dry run, no project has been cloned:")
+	if err := pkg.WritePlan(cmd.OutOrStdout(), changes); err != nil {
+		return err
+	}
+
+	return executor.client.SetWorkspace(executor.context, groupName, pkg.WorkspaceConfig{
+		Path:     path,
+		Group:    groupName,
+		Projects: projects,
+	})
+}
+
+func (executor *pullGroupExecutor) pickProjects(projects []string) ([]string, error) {
+	selectedProjects, err := executor.userInput.PickMultiple("Select projects:", projects)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return projects, nil
+	return selectedProjects, nil
 }
 
-func (executor *pullGroupExecutor) cloneGroupProjects(
-	group *pkg.GroupConfig,
-	pullPath string,
-) error {
-	errString := ""
-	var wg sync.WaitGroup
-
-	p := mpb.New(mpb.WithWaitGroup(&wg), mpb.WithWidth(50))
-	cloningBar := executor.createBarForGroup("Cloning repositories:", p, group)
-	wg.Add(len(*group))
-
-	for _, projectName := range *group {
-		go func(projectName string) {
-			project := executor.projectService.GetProject(projectName)
-			if err := executor.repoService.PullProject(
-				filepath.FromSlash(fmt.Sprintf("%s/%s", pullPath, projectName)),
-				project,
-			); err != nil {
-				errString = fmt.Sprintf(
-					"%s\nFailed to clone project '%s'. Error: %s",
-					errString,
-					projectName,
-					err.Error(),
-				)
-			}
+// cloneGroupProjects clones the projects which have not completed the step with wildfire.Client.Clone, which adds
+// them to the workspace of the group.
+func (executor *pullGroupExecutor) cloneGroupProjects(groupName string, step *pkg.CheckpointStep, pullPath string) error {
+	projects := executor.checkpoint.Incomplete(step)
+	if len(projects) == 0 {
+		return nil
+	}
+
+	p := mpb.New(mpb.WithWidth(50))
+	cloningBar := executor.createBarForGroup("Cloning repositories:", p, projects)
+
+	ctx := wildfire.WithProgress(executor.context, func(event wildfire.ProjectEvent) {
+		result := executor.history.AddProject(event.Clone, event.HistoryStatus(), "", event.Err)
+		_ = executor.checkpoint.Complete(step, result)
+		cloningBar.Increment()
+	})
+
+	results, err := executor.client.Clone(ctx, groupName, wildfire.CloneOptions{Path: pullPath, Projects: projects})
+	if err != nil {
+		cloningBar.Abort(true)
+		p.Wait()
 
-			cloningBar.Increment()
-			wg.Done()
-		}(projectName)
+		return err
 	}
 	p.Wait()
 
-	if errString != "" {
-		return errors.New(strings.Trim(errString, "\n"))
+	var failures []string
+	for _, result := range results {
+		if result.Err != nil {
+			failures = append(failures, fmt.Sprintf("Failed to clone project '%s'. Error: %s", result.Project, result.Err))
+		}
+	}
+
+	if len(failures) != 0 {
+		executor.cloneFailed = true
+
+		return errors.New(strings.Join(failures, "\n"))
 	}
 
 	return nil
@@ -214,9 +297,9 @@ func (executor *pullGroupExecutor) clearPath(path string) error {
 	return os.RemoveAll(filepath.FromSlash(path))
 }
 
-func (executor *pullGroupExecutor) createBarForGroup(name string, p *mpb.Progress, group *pkg.GroupConfig) *mpb.Bar {
+func (executor *pullGroupExecutor) createBarForGroup(name string, p *mpb.Progress, projects []string) *mpb.Bar {
 	return p.Add(
-		int64(len(*group)),
+		int64(len(projects)),
 		mpb.NewBarFiller(mpb.BarStyle().Lbound("[").Filler("=").Tip(">").Padding(" ").Rbound("]")),
 		mpb.PrependDecorators(
 			decor.Name(name, decor.WC{W: len(name) + 1, C: decor.DidentRight}),
@@ -226,27 +309,70 @@ func (executor *pullGroupExecutor) createBarForGroup(name string, p *mpb.Progres
 	)
 }
 
-func (executor *pullGroupExecutor) runCommand(group pkg.GroupConfig, path string) error {
+func (executor *pullGroupExecutor) runCommand(groupName string, projects []string, actionString string) error {
 	scope, err := executor.userInput.PickOne("Select scope:", []string{"All", "Select projects"})
 	if err != nil {
 		return err
 	}
 
 	if scope == "Select projects" {
-		selectedProjects, err := executor.userInput.PickMultiple("Select clones:", group)
+		selectedProjects, err := executor.userInput.PickMultiple("Select clones:", projects)
 		if err != nil {
 			return err
 		}
 
 		emoji.Println("Selected: ", strings.Join(selectedProjects, ", "))
-		group = selectedProjects
+		projects = selectedProjects
+	}
+
+	if actionString == "" {
+		actionString, err = executor.userInput.PlainInput("Command:")
+		if err != nil {
+			return err
+		}
+	}
+
+	results, err := executor.executeCommand(groupName, executor.checkpoint.Step("command", actionString, projects))
+	if err != nil {
+		return err
 	}
 
-	actionString, err := executor.userInput.PlainInput("Command:")
+	checkResults, err := executor.userInput.PickBool("Do you want to see the output?")
 	if err != nil {
 		return err
 	}
+	if checkResults == true {
+		keys := []string{}
+		for _, result := range results {
+			keys = append(keys, result.Project)
+		}
+		keys = append(keys, "Done")
 
+		var action string
+		for action != "Done" {
+			action, err = executor.userInput.PickOne("Select project", keys)
+			if err != nil {
+				return err
+			}
+			if action == "Done" {
+				break
+			}
+
+			for _, result := range results {
+				if result.Project == action {
+					fmt.Println("Printing output of last command for project:", action)
+					fmt.Println(result.Output)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// executeCommand runs the command of the step with wildfire.Client.Exec in the clones of the workspace of the group
+// which have not completed it, and records the run in the history.
+func (executor *pullGroupExecutor) executeCommand(groupName string, step *pkg.CheckpointStep) ([]pkg.ExecResult, error) {
 	action, actionArgs, err := func(actionString string) (string, []string, error) {
 		r := csv.NewReader(strings.NewReader(actionString))
 		r.Comma = ' ' // space
@@ -257,71 +383,58 @@ func (executor *pullGroupExecutor) runCommand(group pkg.GroupConfig, path string
 		}
 
 		return parts[0], parts[1:], nil
-	}(actionString)
-
-	var wg sync.WaitGroup
-	results := sync.Map{}
-	var errors []error
-
-	p := mpb.New(mpb.WithWaitGroup(&wg), mpb.WithWidth(50))
-	executionProgressBar := executor.createBarForGroup("Running command:", p, &group)
-	wg.Add(len(group))
+	}(step.Command)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, project := range group {
-		go func(project string) {
-			defer wg.Done()
+	projects := executor.checkpoint.Incomplete(step)
+	if len(projects) == 0 {
+		return nil, nil
+	}
 
-			command := exec.Command(action, actionArgs...)
-			command.Dir = filepath.FromSlash(fmt.Sprintf("%s/%s", path, project))
-			var buf bytes.Buffer
-			command.Stdout = &buf
+	entry := pkg.NewHistoryEntry("exec", groupName)
+	entry.Group = groupName
+	entry.Command = step.Command
+	entry.Projects = append(entry.Projects, executor.checkpoint.Completed(step)...)
+	defer pkg.RecordRun(executor.cmd, entry)
 
-			err := command.Run()
-			if err != nil {
-				errors = append(errors, err)
-			}
+	p := mpb.New(mpb.WithWidth(50))
+	executionProgressBar := executor.createBarForGroup("Running command:", p, projects)
 
-			fmt.Println(fmt.Sprintf("Project '%s' is done.", project))
+	ctx := wildfire.WithProgress(pkg.WithProjectSelection(executor.context, projects), func(event wildfire.ProjectEvent) {
+		message := ""
+		if event.Status == wildfire.ProjectStatusSkipped {
+			message = event.Output
+		}
 
-			results.Store(project, buf.String())
-			executionProgressBar.Increment()
-		}(project)
-	}
+		project := entry.AddProject(event.Clone, event.HistoryStatus(), message, event.Err)
+		_ = executor.checkpoint.Complete(step, project)
 
-	p.Wait()
+		fmt.Println(fmt.Sprintf("Project '%s' is done.", event.Project))
+		executionProgressBar.Increment()
+	})
 
-	checkResults, err := executor.userInput.PickBool("Do you want to see the output?")
+	results, err := executor.client.Exec(ctx, groupName, nil, action, actionArgs...)
 	if err != nil {
-		return err
-	}
-	if checkResults == true {
-		keys := []string{}
-		results.Range(func(key, _ interface{}) bool {
-			keys = append(keys, strings.ToLower(key.(string)))
-			return true
-		})
-		keys = append(keys, "Done")
-
-		var action string
-		for action != "Done" {
-			action, err = executor.userInput.PickOne("Select project", keys)
-			if action == "Done" {
-				break
-			}
+		executionProgressBar.Abort(true)
+		p.Wait()
 
-			fmt.Println("Printing output of last command for project:", action)
-			fmt.Println(results.Load(action))
-		}
+		return nil, err
 	}
+	p.Wait()
 
-	return nil
+	return results, nil
 }
 
 func NewPullGroupCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "group <group name> [path]",
 		Short: "Pull group projects from their repositories",
-		Long:  `Pull the projects stored in the specified group in the current directory or a specified directory.`,
+		Long: `Pull the projects stored in the specified group in the current directory or a specified directory.
+
+When no directory is specified the default path of the group is used if it is set. Projects are cloned on the
+default branch of the group if it is set.`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
 				return errors.New("invalid number of arguments provided")
@@ -330,42 +443,79 @@ func NewPullGroupCmd() *cobra.Command {
 			return nil
 		},
 		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-			projectService := pkg.NewProjectService(config)
-			groupService := pkg.NewGroupService(config)
-			projectRepoService := project_repository.NewProjectRepositoryService(
-				&projectService,
-				&groupService,
-				project_repository.NewCloner(nil),
-			)
+			if tui {
+				update, err := dashboard.OpenGroupDashboard(cmd, config, args[0], args[1:], true)
 
-			var input SurveyUserInput
-			executor := &pullGroupExecutor{
-				projectService: projectService,
-				groupService:   groupService,
-				repoService:    projectRepoService,
-				userInput:      input,
+				return config, update, err
 			}
 
+			client := wildfire.NewClientFromConfig(config)
+
 			groupName := args[0]
+			stored, err := client.GetGroup(cmd.Context(), groupName)
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, emoji.Errorf("Group '%s' does not exist in configuration.", groupName)
+			}
+			if err != nil {
+				return config, false, err
+			}
+
+			group, err := config.ResolveGroup(groupName, &stored)
+			if err != nil {
+				return config, false, err
+			}
+
+			var input SurveyUserInput
+			executor := &pullGroupExecutor{
+				client:    client,
+				userInput: input,
+				cmd:       cmd,
+				context:   cmd.Context(),
+				history:   pkg.NewHistoryEntry("clone", groupName),
+			}
+			executor.history.Group = groupName
 
 			var pullPath string
 
 			if len(args) > 1 {
 				pullPath = filepath.FromSlash(fmt.Sprintf("%s/%s", args[1], groupName))
+			} else if group.Path != "" {
+				pullPath = filepath.Join(group.Path, groupName)
 			} else {
 				currentWD, _ := os.Getwd()
 				pullPath = filepath.FromSlash(fmt.Sprintf("%s/%s", currentWD, groupName))
 			}
 
-			err := executor.Execute(groupName, pullPath, someProjects)
+			if pkg.IsDryRun(cmd.Context()) {
+				return config, true, executor.plan(cmd, groupName, pullPath, someProjects)
+			}
+
+			executor.checkpoint = pkg.StartCheckpoint(cmd.Context(), executor.history, pullPath)
+			err = executor.Execute(groupName, pullPath, someProjects)
+			if executor.cloneFailed == false {
+				_ = executor.checkpoint.Finish()
+			} else if executor.checkpoint != nil {
+				emoji.Fprintf(cmd.ErrOrStderr(), ":repeat: Clone the failed projects with 'wildfire resume %s'\n", executor.checkpoint.ID)
+			}
+			if len(executor.history.Projects) != 0 {
+				pkg.RecordRun(cmd, executor.history)
+			}
+
+			// The workspace holds the projects which have been cloned, it is saved even though some clones failed.
+			if err != nil && executor.cloneFailed {
+				if err := pkg.SaveCommandConfig(cmd, config); err != nil {
+					return config, false, err
+				}
+			}
 
-			return config, false, err
+			return config, true, err
 		}),
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
 
 	cmd.Flags().BoolVarP(&someProjects, "some", "s", false, "Only clone some projects from group")
+	cmd.Flags().BoolVar(&tui, "tui", false, "Clone the group in the interactive dashboard")
 
 	return cmd
 }
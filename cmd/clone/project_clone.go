@@ -9,13 +9,18 @@ import (
 	"path/filepath"
 	"wildfire/pkg"
 	"wildfire/pkg/project_repository"
+	"wildfire/wildfire"
 )
 
 func NewPullProjectCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "project <project name> [path]",
 		Short: "Pull group projects from their repositories",
-		Long:  `Pull the projects stored in the specified group in the current directory or a specified directory.`,
+		Long: `Pull the project in the current directory or a specified directory.
+
+The clone is saved as the workspace 'project:<project name>', so it does not clash with the workspace of a group
+with the same name.
+`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
 				return errors.New("invalid number of arguments provided")
@@ -24,16 +29,9 @@ func NewPullProjectCmd() *cobra.Command {
 			return nil
 		},
 		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-			projectService := pkg.NewProjectService(config)
-			groupService := pkg.NewGroupService(config)
-			projectRepoService := project_repository.NewProjectRepositoryService(
-				&projectService,
-				&groupService,
-				project_repository.NewCloner(os.Stdout),
-			)
 			projectName := args[0]
 
-			project := projectService.GetProject(projectName)
+			project := pkg.NewProjectService(config).GetProject(projectName)
 
 			if project == nil {
 				return config, false, emoji.Errorf("Project '%s' does not exist in configuration.", projectName)
@@ -48,17 +46,45 @@ func NewPullProjectCmd() *cobra.Command {
 				pullPath = filepath.FromSlash(fmt.Sprintf("%s/%s", currentWD, args[0]))
 			}
 
-			err := projectRepoService.PullProject(
-				filepath.FromSlash(fmt.Sprintf("%s/%s", pullPath, projectName)),
-				project,
-			)
+			if pkg.IsDryRun(cmd.Context()) {
+				clone := pkg.Clone{Project: projectName, Path: filepath.Join(pullPath, projectName)}
+
+				emoji.Fprintln(cmd.ErrOrStderr(), ":memo: Dry run, the project has not been cloned:")
+				if err := pkg.WritePlan(cmd.OutOrStdout(), []pkg.PlannedChange{pkg.PlanClone(clone, project, "")}); err != nil {
+					return config, false, err
+				}
+
+				pkg.NewWorkspaceService(config).SetWorkspace(pkg.ProjectWorkspaceName(projectName), &pkg.WorkspaceConfig{
+					Path:     pullPath,
+					Projects: []string{projectName},
+				})
 
+				return config, true, nil
+			}
+
+			client := wildfire.NewClientFromConfig(config)
+			client.NewCloner = func(branch string) project_repository.Cloner {
+				return &project_repository.GitCloner{Output: cmd.OutOrStdout(), Branch: branch}
+			}
+
+			result, err := client.CloneProject(cmd.Context(), projectName, pullPath)
 			if err != nil {
-				err = emoji.Errorf("Failed to clone project '%s'. Error: ", err)
-				err = os.RemoveAll(filepath.FromSlash(fmt.Sprintf("%s/%s", pullPath, projectName)))
+				return config, false, err
+			}
+
+			entry := pkg.NewHistoryEntry("clone", projectName)
+			status := pkg.HistoryStatusSucceeded
+			if result.Skipped {
+				status = pkg.HistoryStatusSkipped
+			}
+			entry.AddProject(result.Clone, status, "", result.Err)
+			pkg.RecordRun(cmd, entry)
+
+			if result.Err != nil {
+				return config, false, emoji.Errorf("Failed to clone project '%s'. Error: %s", projectName, result.Err)
 			}
 
-			return config, false, nil
+			return config, true, nil
 		}),
 		SilenceUsage:  true,
 		SilenceErrors: true,
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+)
+
+func NewDoctorConfigCmd() *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Report and repair configuration problems",
+		Long: `Report and repair configuration problems.
+
+Checks that project names match their keys, project types are valid, project URLs can be parsed,
+groups do not contain duplicate projects and that groups only reference existing projects.
+
+When --fix is provided the problems which can be repaired automatically are fixed and the configuration is saved.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			issues := config.Validate()
+			if len(issues) == 0 {
+				emoji.Println(":star: No configuration issues were found.")
+				return config, false, nil
+			}
+
+			if fix == false {
+				for _, issue := range issues {
+					if issue.Fixable() {
+						emoji.Println(":warning:", issue, "(fixable)")
+					} else {
+						emoji.Println(":prohibited:", issue)
+					}
+				}
+
+				return config, false, fmt.Errorf("found %d configuration issues", len(issues))
+			}
+
+			fixed := 0
+			for _, issue := range issues {
+				if issue.Fix() {
+					emoji.Println(":wrench: Fixed:", issue)
+					fixed++
+				}
+			}
+
+			for _, issue := range config.Validate() {
+				emoji.Println(":prohibited: Requires manual fix:", issue)
+			}
+
+			return config, fixed != 0, nil
+		}),
+		Annotations:   map[string]string{pkg.SkipValidationAnnotation: "true"},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Repair the problems which can be fixed automatically")
+
+	return cmd
+}
@@ -0,0 +1,15 @@
+package config
+
+import "github.com/spf13/cobra"
+
+var ConfigCmd = &cobra.Command{
+	Use: "config",
+}
+
+func init() {
+	ConfigCmd.AddCommand(NewDoctorConfigCmd())
+	ConfigCmd.AddCommand(NewRestoreConfigCmd())
+	ConfigCmd.AddCommand(NewShowConfigCmd())
+	ConfigCmd.AddCommand(NewRefreshConfigCmd())
+	ConfigCmd.AddCommand(NewMigrateConfigCmd())
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"os"
+	"wildfire/pkg"
+)
+
+func NewMigrateConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the configuration files to the current schema version",
+		Long: fmt.Sprintf(`Upgrade the configuration files to the current schema version %d.
+
+Older configuration files are upgraded in memory every time they are loaded and written in the current
+format the next time they are saved. This command upgrades every configuration layer at once.
+The previous version of each file is kept as a backup.
+`, pkg.ConfigVersion),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			for _, layer := range pkg.GetStoreConfigLayers(pkg.GetConfigStore(cmd.Context())) {
+				if _, err := os.Stat(layer.Path); os.IsNotExist(err) {
+					continue
+				}
+
+				applied, diff, err := pkg.MigrateConfigFile(layer.Path, pkg.IsDryRun(cmd.Context()))
+				if err != nil {
+					return err
+				}
+
+				if len(applied) == 0 {
+					emoji.Fprintf(out, ":star: %s configuration '%s' is up to date.\n", layer.Name, layer.Path)
+					continue
+				}
+
+				emoji.Fprintf(out, ":wrench: %s configuration '%s':\n", layer.Name, layer.Path)
+				for _, migration := range applied {
+					fmt.Fprintf(out, "    -> Version %d: %s\n", migration.Version, migration.Description)
+				}
+				fmt.Fprint(out, diff)
+
+				if pkg.IsDryRun(cmd.Context()) == false {
+					emoji.Fprintf(out, ":cloud: Migrated to version %d.\n", pkg.ConfigVersion)
+				}
+			}
+
+			return nil
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	return cmd
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+)
+
+func NewRefreshConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh",
+		Short: "Fetch the included registries again",
+		Long: `Fetch the included registries again.
+
+Includes from URLs and git repositories are cached locally the first time they are loaded.
+The cached copies are only updated when this command is executed. With --dry-run the includes
+are listed without fetching them.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dryRun := pkg.IsDryRun(cmd.Context())
+
+			refreshed, err := pkg.RefreshIncludes(pkg.GetConfigStore(cmd.Context()), dryRun)
+			for _, include := range refreshed {
+				if dryRun {
+					emoji.Fprintf(cmd.OutOrStdout(), ":ocean: Would refresh '%s'\n", include.SourceName())
+					continue
+				}
+
+				emoji.Fprintf(cmd.OutOrStdout(), ":ocean: Refreshed '%s'\n", include.SourceName())
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if len(refreshed) == 0 {
+				emoji.Fprintln(cmd.OutOrStdout(), ":cloud: Configuration has no includes.")
+			}
+
+			return nil
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+}
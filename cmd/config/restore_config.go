@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"strconv"
+	"wildfire/pkg"
+)
+
+func NewRestoreConfigCmd() *cobra.Command {
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "restore [backup number]",
+		Short: "Restore the configuration from a backup",
+		Long: fmt.Sprintf(`Restore the configuration from a backup.
+
+Every time the configuration is saved the previous version is kept as a backup next to the configuration
+file. The %d most recent versions are kept, backup 1 being the most recent one. If no backup number is
+provided backup 1 is restored. The replaced configuration becomes the most recent backup.
+`, pkg.ConfigBackupCount),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			if len(args) == 1 {
+				if _, err := strconv.Atoi(args[0]); err != nil {
+					return errors.New("invalid backup number provided")
+				}
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := pkg.GetConfigStore(cmd.Context())
+			configPath := store.Path()
+			if configPath == "" {
+				return errors.New("no configuration file is in use")
+			}
+
+			if list {
+				backups, err := pkg.GetConfigBackups(configPath)
+				if err != nil {
+					return err
+				}
+
+				if len(backups) == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No backups were found for", configPath)
+				}
+				for _, backup := range backups {
+					fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\n", backup.Number, backup.ModTime.Format("2006-01-02 15:04:05"), backup.Path)
+				}
+
+				return nil
+			}
+
+			number := 1
+			if len(args) == 1 {
+				number, _ = strconv.Atoi(args[0])
+			}
+
+			if pkg.IsDryRun(cmd.Context()) {
+				diff, err := pkg.DiffConfigBackup(configPath, number)
+				if err != nil {
+					return err
+				}
+
+				emoji.Fprintf(cmd.ErrOrStderr(), ":memo: Dry run, restoring backup %d would change the configuration:\n", number)
+				fmt.Fprint(cmd.OutOrStdout(), diff)
+
+				return nil
+			}
+
+			lock, err := store.Lock()
+			if err != nil {
+				return err
+			}
+			defer lock.Unlock()
+
+			if err := pkg.RestoreConfigBackup(configPath, number); err != nil {
+				return err
+			}
+
+			emoji.Fprintf(cmd.OutOrStdout(), ":cloud: Configuration has been restored from backup %d.\n", number)
+
+			return nil
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List the available backups")
+
+	return cmd
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"sort"
+	"text/tabwriter"
+	"wildfire/pkg"
+)
+
+func NewShowConfigCmd() *cobra.Command {
+	var sources bool
+	var resolved bool
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the merged configuration",
+		Long: `Show the configuration merged from all configuration layers.
+
+Configuration is merged from the following layers, later layers overriding entries with the same name:
+    global - config.yaml in the wildfire directory of the user configuration directory
+    team   - the closest .wildfire.team.yaml in the current directory or its parents
+    local  - the file provided with --config, ./.wildfire.yaml by default
+
+Entries included by a layer through 'includes' are merged beneath the entries of that layer.
+Values are shown without substituting ${VAR} and ${file:path} references unless --resolved is provided.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			out := cmd.OutOrStdout()
+
+			if sources == false {
+				shown := config
+				if resolved {
+					shown = config.ResolveValues()
+				}
+
+				data, err := yaml.Marshal(shown)
+				if err != nil {
+					return config, false, err
+				}
+
+				_, err = out.Write(data)
+
+				return config, false, err
+			}
+
+			fmt.Fprintln(out, "Layers (lowest precedence first):")
+			for _, layer := range pkg.GetStoreConfigLayers(pkg.GetConfigStore(cmd.Context())) {
+				fmt.Fprintf(out, "    %s: %s\n", layer.Name, layer.Path)
+			}
+			fmt.Fprintln(out)
+
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SECTION\tNAME\tLAYER\tFILE")
+			for _, section := range []string{"projects", "groups", "workspaces"} {
+				var names []string
+				switch section {
+				case "projects":
+					for name := range config.Projects {
+						names = append(names, name)
+					}
+				case "groups":
+					for name := range config.Groups {
+						names = append(names, name)
+					}
+				case "workspaces":
+					for name := range config.Workspaces {
+						names = append(names, name)
+					}
+				}
+				sort.Strings(names)
+
+				for _, name := range names {
+					source := config.Source(section, name)
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", section, name, source, config.SourcePath(source))
+				}
+			}
+
+			return config, false, w.Flush()
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().BoolVar(&sources, "sources", false, "Show the source from which each project, group and workspace was loaded")
+	cmd.Flags().BoolVar(&resolved, "resolved", false, "Show values with ${VAR} and ${file:path} references substituted")
+
+	return cmd
+}
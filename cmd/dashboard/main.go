@@ -0,0 +1,135 @@
+package dashboard
+
+import (
+	"errors"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"wildfire/pkg"
+	"wildfire/pkg/group_dashboard"
+	"wildfire/pkg/project_repository"
+)
+
+func NewDashboardCmd() *cobra.Command {
+	var clone bool
+
+	cmd := &cobra.Command{
+		Use:   "dashboard <group name> [path]",
+		Short: "Open an interactive dashboard for a group",
+		Long: `Open a full screen dashboard listing the projects of a group with their clone and run state.
+
+Projects can be selected, cloned and commands run in their clones while the output of each project is
+streamed to the output panel. Failed clones and commands can be retried.
+
+The clones are located in the workspace of the group when it has been cloned before, otherwise in the group
+directory inside path, the default path of the group or the current directory.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			update, err := OpenGroupDashboard(cmd, config, args[0], args[1:], clone)
+
+			return config, update, err
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().BoolVar(&clone, "clone", false, "Clone the projects which have not been cloned when the dashboard opens")
+
+	return cmd
+}
+
+// OpenGroupDashboard shows the dashboard of the group and registers the workspace of the group once the dashboard
+// is closed. It returns whether the configuration has been updated. The dashboard can not be opened for dry runs.
+func OpenGroupDashboard(cmd *cobra.Command, config *pkg.WildFireConfig, groupName string, pathArgs []string, clone bool) (bool, error) {
+	if pkg.IsDryRun(cmd.Context()) {
+		return false, emoji.Errorf("The dashboard does not support --dry-run.")
+	}
+
+	groupService := pkg.NewGroupService(config)
+	workspaceService := pkg.NewWorkspaceService(config)
+
+	group := groupService.GetGroup(groupName)
+	if group == nil {
+		return false, emoji.Errorf("Group '%s' does not exist in configuration.", groupName)
+	}
+
+	group, err := config.ResolveGroup(groupName, group)
+	if err != nil {
+		return false, err
+	}
+
+	projects, err := groupService.GetGroupProjects(groupName)
+	if err != nil {
+		return false, err
+	}
+
+	path := getGroupPath(workspaceService, groupName, group, pathArgs)
+	d := NewGroupDashboard(config, groupName, group, path, projects)
+	d.Record = func(entry *pkg.HistoryEntry) {
+		// The dashboard owns the terminal, a run which can not be recorded is not reported.
+		_ = pkg.RecordHistory(cmd.Context(), entry)
+	}
+
+	if err := Show(d, clone); err != nil {
+		return false, err
+	}
+
+	cloned := d.GetClonedProjects()
+	if len(cloned) == 0 {
+		return false, nil
+	}
+
+	workspaceService.SetWorkspace(groupName, &pkg.WorkspaceConfig{
+		Path:     path,
+		Group:    groupName,
+		Projects: cloned,
+	})
+
+	return true, nil
+}
+
+// NewGroupDashboard creates the dashboard of the group projects cloned in path. The values of the group must have
+// been substituted, see pkg.WildFireConfig.ResolveGroup.
+func NewGroupDashboard(
+	config *pkg.WildFireConfig,
+	groupName string,
+	group *pkg.GroupConfig,
+	path string,
+	projects []string,
+) *group_dashboard.Dashboard {
+	projectService := pkg.NewProjectService(config)
+	groupService := pkg.NewGroupService(config)
+	repoService := project_repository.NewProjectRepositoryService(
+		&projectService,
+		&groupService,
+		project_repository.NewResolvingCloner(config, &project_repository.GitCloner{Branch: group.Branch}),
+	)
+
+	return group_dashboard.NewDashboard(groupName, path, projects, projectService, repoService)
+}
+
+func getGroupPath(workspaceService pkg.WorkspaceService, groupName string, group *pkg.GroupConfig, args []string) string {
+	if len(args) != 0 {
+		return filepath.Join(args[0], groupName)
+	}
+
+	if workspace := workspaceService.GetWorkspace(groupName); workspace != nil {
+		return workspace.Path
+	}
+
+	if group.Path != "" {
+		return filepath.Join(group.Path, groupName)
+	}
+
+	currentWD, _ := os.Getwd()
+
+	return filepath.Join(currentWD, groupName)
+}
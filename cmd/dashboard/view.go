@@ -0,0 +1,243 @@
+package dashboard
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"wildfire/pkg/group_dashboard"
+)
+
+const dashboardHelp = "[yellow]space[-] select  [yellow]a[-] select all  [yellow]c[-] clone  [yellow]r[-] run command  " +
+	"[yellow]f[-] retry failed  [yellow]tab[-] scroll output  [yellow]q[-] quit"
+
+type dashboardView struct {
+	dashboard *group_dashboard.Dashboard
+	app       *tview.Application
+	table     *tview.Table
+	output    *tview.TextView
+	footer    *tview.Pages
+	status    *tview.TextView
+	input     *tview.InputField
+}
+
+// Show displays the dashboard until the user quits. When clone is set the projects which have not been cloned are
+// cloned when the dashboard opens.
+func Show(d *group_dashboard.Dashboard, clone bool) error {
+	view := &dashboardView{
+		dashboard: d,
+		app:       tview.NewApplication(),
+		table:     tview.NewTable(),
+		output:    tview.NewTextView(),
+		footer:    tview.NewPages(),
+		status:    tview.NewTextView(),
+		input:     tview.NewInputField(),
+	}
+	view.build()
+
+	d.OnChange = func(project *group_dashboard.ProjectState) {
+		go view.app.QueueUpdateDraw(func() {
+			view.refresh()
+		})
+	}
+
+	if clone {
+		view.background("Cloning projects", func() error {
+			d.Clone(d.Projects)
+			return nil
+		})
+	}
+
+	return view.app.Run()
+}
+
+func (v *dashboardView) build() {
+	v.table.
+		SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectionChangedFunc(func(row, column int) {
+			v.refreshOutput()
+		}).
+		SetInputCapture(v.handleKey)
+	v.table.SetBorder(true).SetTitle(fmt.Sprintf(" %s - %s ", v.dashboard.Group, v.dashboard.Path))
+
+	v.output.
+		SetScrollable(true).
+		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyEscape {
+				v.app.SetFocus(v.table)
+				return nil
+			}
+
+			return event
+		})
+	v.output.SetBorder(true).SetTitle(" Output ")
+
+	v.status.SetDynamicColors(true).SetText(dashboardHelp)
+
+	v.input.
+		SetLabel("Command: ").
+		SetDoneFunc(func(key tcell.Key) {
+			command := v.input.GetText()
+			v.input.SetText("")
+			v.footer.SwitchToPage("status")
+			v.app.SetFocus(v.table)
+
+			if key != tcell.KeyEnter || command == "" {
+				return
+			}
+
+			targets := v.dashboard.Targets()
+			v.background(fmt.Sprintf("Running '%s'", command), func() error {
+				return v.dashboard.Run(command, targets)
+			})
+		})
+
+	v.footer.
+		AddPage("status", v.status, true, true).
+		AddPage("input", v.input, true, false)
+
+	main := tview.NewFlex().
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.output, 0, 2, false)
+
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(main, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+
+	v.refresh()
+	v.table.Select(1, 0)
+	v.app.SetRoot(layout, true).SetFocus(v.table)
+}
+
+func (v *dashboardView) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyTab:
+		v.app.SetFocus(v.output)
+		return nil
+	case tcell.KeyEscape:
+		v.app.Stop()
+		return nil
+	case tcell.KeyRune:
+	default:
+		return event
+	}
+
+	switch event.Rune() {
+	case ' ':
+		row, _ := v.table.GetSelection()
+		v.dashboard.Toggle(row - 1)
+	case 'a':
+		v.dashboard.SelectAll()
+	case 'c':
+		targets := v.dashboard.Targets()
+		v.background("Cloning projects", func() error {
+			v.dashboard.Clone(targets)
+			return nil
+		})
+	case 'r':
+		v.footer.SwitchToPage("input")
+		v.app.SetFocus(v.input)
+	case 'f':
+		v.background("Retrying failed projects", func() error {
+			v.dashboard.RetryFailed()
+			return nil
+		})
+	case 'q':
+		v.app.Stop()
+	default:
+		return event
+	}
+
+	return nil
+}
+
+// background runs the action in a goroutine and displays its progress in the status bar.
+func (v *dashboardView) background(description string, action func() error) {
+	v.status.SetText(fmt.Sprintf("[yellow]%s...[-]", description))
+
+	go func() {
+		err := action()
+
+		v.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.status.SetText(fmt.Sprintf("[red]%s[-]  %s", err, dashboardHelp))
+				return
+			}
+
+			v.status.SetText(dashboardHelp)
+		})
+	}()
+}
+
+func (v *dashboardView) refresh() {
+	v.dashboard.ReadState(func() {
+		for column, header := range []string{"", "PROJECT", "CLONE", "RUN"} {
+			v.table.SetCell(0, column, tview.NewTableCell(header).SetSelectable(false).SetTextColor(tcell.ColorYellow))
+		}
+
+		for index, project := range v.dashboard.Projects {
+			selected := "[ ]"
+			if project.Selected {
+				selected = "[x]"
+			}
+
+			row := index + 1
+			v.table.SetCell(row, 0, tview.NewTableCell(tview.Escape(selected)))
+			v.table.SetCell(row, 1, tview.NewTableCell(project.Name).SetExpansion(1))
+			v.table.SetCell(row, 2, tview.NewTableCell(string(project.Clone)).SetTextColor(cloneColor(project.Clone)))
+			v.table.SetCell(row, 3, tview.NewTableCell(string(project.Run)).SetTextColor(runColor(project.Run)))
+		}
+	})
+
+	v.refreshOutput()
+}
+
+func (v *dashboardView) refreshOutput() {
+	row, _ := v.table.GetSelection()
+	if row < 1 || row > len(v.dashboard.Projects) {
+		return
+	}
+
+	project := v.dashboard.Projects[row-1]
+	v.output.SetTitle(fmt.Sprintf(" Output: %s ", project.Name))
+
+	var text string
+	v.dashboard.ReadState(func() {
+		text = project.Output()
+		if project.Err != nil {
+			text = fmt.Sprintf("%s\n%s", text, project.Err)
+		}
+	})
+
+	if text != v.output.GetText(false) {
+		v.output.SetText(text)
+		v.output.ScrollToEnd()
+	}
+}
+
+func cloneColor(status group_dashboard.CloneStatus) tcell.Color {
+	switch status {
+	case group_dashboard.CloneStatusCloned:
+		return tcell.ColorGreen
+	case group_dashboard.CloneStatusCloning:
+		return tcell.ColorYellow
+	case group_dashboard.CloneStatusFailed:
+		return tcell.ColorRed
+	default:
+		return tcell.ColorGray
+	}
+}
+
+func runColor(status group_dashboard.RunStatus) tcell.Color {
+	switch status {
+	case group_dashboard.RunStatusSucceeded:
+		return tcell.ColorGreen
+	case group_dashboard.RunStatusRunning:
+		return tcell.ColorYellow
+	case group_dashboard.RunStatusFailed:
+		return tcell.ColorRed
+	default:
+		return tcell.ColorWhite
+	}
+}
@@ -0,0 +1,40 @@
+package edit
+
+import (
+	"errors"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+)
+
+func NewBumpCmd() *cobra.Command {
+	var where []string
+
+	cmd := &cobra.Command{
+		Use:   "bump <group|workspace> <dependency> <version>",
+		Short: "Bump the version of a dependency",
+		Long: `Bump the version of a dependency in the go.mod, package.json, requirements.txt and pom.xml files at the root
+of every clone.
+
+The range operator of npm dependencies and the version operator of pip requirements are kept unless the version has
+one. Maven dependencies are named artifactId or groupId:artifactId, versions defined by a property update the property.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 3 || args[1] == "" || args[2] == "" {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			editor := &pkg.DependencyEditor{Name: args[1], Version: args[2]}
+
+			return config, false, runEditor(config, cmd, args[0], editor, where)
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	addWhereFlag(cmd, &where)
+
+	return cmd
+}
@@ -0,0 +1,56 @@
+package edit
+
+import (
+	"errors"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+)
+
+func NewAddLineCmd() *cobra.Command {
+	return newLineCmd(
+		"add-line <group|workspace> <file> <line>",
+		"Add a line to a file",
+		`Add a line to the end of a file in every clone, unless the file already contains it. The file is created if it
+does not exist.
+`,
+		false,
+	)
+}
+
+func NewRemoveLineCmd() *cobra.Command {
+	return newLineCmd(
+		"remove-line <group|workspace> <file> <line>",
+		"Remove a line from a file",
+		`Remove every occurrence of a line from a file in every clone.
+`,
+		true,
+	)
+}
+
+func newLineCmd(use string, short string, long string, remove bool) *cobra.Command {
+	var where []string
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 3 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			editor := &pkg.LineEditor{File: args[1], Line: args[2], Remove: remove}
+
+			return config, false, runEditor(config, cmd, args[0], editor, where)
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	addWhereFlag(cmd, &where)
+
+	return cmd
+}
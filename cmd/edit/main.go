@@ -0,0 +1,95 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"strings"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+var EditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the files of the clones of a workspace",
+	Long: `Edit the files of the clones of a workspace, or of the workspace cloned from a group, without running
+external commands. Edits run in parallel and report the files which have been changed in every clone. Running an edit
+again does not change anything.`,
+}
+
+func init() {
+	EditCmd.AddCommand(NewSetKeyCmd())
+	EditCmd.AddCommand(NewReplaceCmd())
+	EditCmd.AddCommand(NewBumpCmd())
+	EditCmd.AddCommand(NewAddLineCmd())
+	EditCmd.AddCommand(NewRemoveLineCmd())
+}
+
+// addWhereFlag adds the flag restricting an edit to the clones matching predicates, see pkg.ClonePredicate.
+func addWhereFlag(cmd *cobra.Command, where *[]string) {
+	cmd.Flags().StringArrayVarP(where, "where", "w", nil, "Only edit the clones matching the predicate")
+}
+
+// runEditor runs the editor in the clones of the workspace matching the predicates and reports the changed files.
+func runEditor(config *pkg.WildFireConfig, cmd *cobra.Command, name string, editor pkg.Editor, where []string) error {
+	predicates, err := pkg.ParseClonePredicates(where)
+	if err != nil {
+		return err
+	}
+
+	client := wildfire.NewClientFromConfig(config)
+
+	workspace, err := client.GetWorkspace(cmd.Context(), name)
+	if errors.Is(err, wildfire.ErrNotFound) {
+		return emoji.Errorf("Workspace '%s' does not exist in configuration. Clone the group first.", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	emoji.Fprintf(cmd.ErrOrStderr(), ":pencil2: Editing clones: %s\n\n", editor)
+
+	results, err := client.Edit(cmd.Context(), name, predicates, editor)
+	if err != nil {
+		return err
+	}
+
+	changed, failed := 0, 0
+
+	entry := pkg.NewHistoryEntry("edit", name)
+	entry.Group = workspace.Group
+	entry.Command = editor.String()
+
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":fast_forward: Skipped '%s': %s\n", result.Project, result.SkipReason)
+			entry.AddProject(result.Clone, pkg.HistoryStatusSkipped, result.SkipReason, nil)
+		case result.Err != nil:
+			failed++
+			emoji.Fprintf(cmd.ErrOrStderr(), ":x: '%s' failed: %s\n", result.Project, result.Err)
+			entry.AddProject(result.Clone, pkg.HistoryStatusFailed, "", result.Err)
+		case len(result.Changed) != 0:
+			changed++
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", result.Project, strings.Join(result.Changed, ", "))
+			entry.AddProject(result.Clone, pkg.HistoryStatusChanged, strings.Join(result.Changed, ", "), nil)
+		default:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":zzz: '%s' unchanged\n", result.Project)
+			entry.AddProject(result.Clone, pkg.HistoryStatusSucceeded, "unchanged", nil)
+		}
+	}
+
+	pkg.RecordRun(cmd, entry)
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "\n%d changed, %d unchanged or skipped, %d failed\n", changed, len(results)-changed-failed, failed)
+	if pkg.IsDryRun(cmd.Context()) {
+		emoji.Fprintln(cmd.ErrOrStderr(), ":memo: Dry run, the files listed as changed have not been written.")
+	}
+
+	if failed != 0 {
+		return emoji.Errorf("Failed in %d of %d projects.", failed, len(results))
+	}
+
+	return nil
+}
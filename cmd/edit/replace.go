@@ -0,0 +1,41 @@
+package edit
+
+import (
+	"errors"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+)
+
+func NewReplaceCmd() *cobra.Command {
+	var where []string
+
+	cmd := &cobra.Command{
+		Use:   "replace <group|workspace> <glob> <pattern> <replacement>",
+		Short: "Replace a regular expression in files matching a glob pattern",
+		Long: `Replace the matches of a regular expression in the files of every clone matching a glob pattern.
+
+In the glob pattern ** matches any number of directories. The replacement can reference the groups of the regular
+expression, e.g. $1. Binary files are left unchanged.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 4 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			_, err := pkg.NewReplaceEditor(args[1], args[2], args[3])
+
+			return err
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			editor, _ := pkg.NewReplaceEditor(args[1], args[2], args[3])
+
+			return config, false, runEditor(config, cmd, args[0], editor, where)
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	addWhereFlag(cmd, &where)
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+package edit
+
+import (
+	"errors"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+)
+
+func NewSetKeyCmd() *cobra.Command {
+	var where []string
+
+	cmd := &cobra.Command{
+		Use:   "set-key <group|workspace> <file> <key> <value>",
+		Short: "Set a key of a YAML or JSON file",
+		Long: `Set the value of a key of a YAML or JSON file in every clone.
+
+The key is a dot separated path in which numbers index sequences, e.g. spec.containers.0.image. Missing mappings are
+created. The value is parsed as YAML, so 'true' is a boolean while "'true'" is a string.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 4 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			editor := &pkg.SetKeyEditor{File: args[1], Key: args[2], Value: args[3]}
+
+			return config, false, runEditor(config, cmd, args[0], editor, where)
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	addWhereFlag(cmd, &where)
+
+	return cmd
+}
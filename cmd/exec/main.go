@@ -0,0 +1,351 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"io"
+	"strings"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func NewExecCmd() *cobra.Command {
+	var (
+		where     []string
+		saveGroup string
+		rollout   rolloutFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "exec <workspace> [--where <predicate>]... -- <command> [args...]",
+		Short: "Run a command in the clones of a workspace",
+		Long: `Run a command in parallel in the clones of a workspace. Workspaces are created when a group is cloned and
+are named after the group.
+
+Predicates provided with --where are evaluated in every clone before the command is run. The command is only run in
+the clones which match all of them, the other clones are reported as skipped. Available predicates:
+
+  file-exists:<path>        the file or directory exists in the clone
+  contains:<path>:<text>    the file exists in the clone and contains the text
+  glob:<pattern>            a file of the clone matches the pattern, ** matches any number of directories
+  cmd:<command>             the command exits with status 0 when run in the clone
+
+With --save-group the matching projects are saved as a group. The command can then be omitted to only build the group.
+
+With --canary or --wave-size the command runs in waves: first in the canary projects, then in waves of --wave-size
+projects, or in all the remaining projects without a wave size. Sizes are a number of projects, N, or a percentage of
+the projects, N%. Each wave starts once the previous one completed and the next wave is confirmed, or automatically
+with --auto. The rollout stops when the command fails in more than --max-failure-rate of the projects of a wave,
+0% by default. A stopped rollout can be continued with 'wildfire resume'.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			positional := args
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				positional = args[:dash]
+			}
+
+			if len(positional) != 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			if len(args) == 1 && saveGroup == "" {
+				return errors.New("no command has been provided")
+			}
+
+			if _, err := pkg.ParseClonePredicates(where); err != nil {
+				return err
+			}
+
+			if rollout, err := rollout.parse(); err != nil {
+				return err
+			} else if rollout != nil && len(args) == 1 {
+				return errors.New("no command has been provided to roll out")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			workspaceName := args[0]
+			predicates, _ := pkg.ParseClonePredicates(where)
+			waves, _ := rollout.parse()
+
+			command, commandArgs := "", []string(nil)
+			if len(args) > 1 {
+				command, commandArgs = args[1], args[2:]
+			}
+
+			// Dry runs only evaluate the predicates and report the command which would run in the matching clones.
+			dryRun := pkg.IsDryRun(cmd.Context()) && command != ""
+
+			ctx := cmd.Context()
+			var run *execRun
+			if command != "" && dryRun == false {
+				run = startRun(cmd, config, workspaceName, strings.Join(args[1:], " "))
+				ctx = run.ctx
+			}
+
+			var results []pkg.ExecResult
+			var outcome *rolloutOutcome
+			var err error
+			switch {
+			case dryRun:
+				results, err = client.Exec(ctx, workspaceName, predicates, "")
+			case waves != nil:
+				results, outcome, err = runRollout(
+					ctx,
+					cmd,
+					client,
+					config,
+					run,
+					waves,
+					rollout.auto,
+					workspaceName,
+					predicates,
+					command,
+					commandArgs,
+				)
+			default:
+				results, err = client.Exec(ctx, workspaceName, predicates, command, commandArgs...)
+			}
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, emoji.Errorf(
+					"Workspace '%s' does not exist in configuration. Clone the group first.",
+					workspaceName,
+				)
+			}
+			if err != nil {
+				return config, false, err
+			}
+
+			if dryRun {
+				if err := printPlan(cmd, results, command, commandArgs); err != nil {
+					return config, false, err
+				}
+				if waves != nil {
+					if err := printPlannedWaves(cmd, waves, config, results); err != nil {
+						return config, false, err
+					}
+				}
+				if saveGroup != "" {
+					return config, true, saveMatchingProjects(cmd, client, saveGroup, results)
+				}
+
+				return config, false, nil
+			}
+
+			failed := 0
+			if outcome != nil {
+				failed = countFailed(results)
+			} else {
+				failed = printResults(cmd, results, len(args) > 1)
+			}
+			if run != nil {
+				run.finish(cmd, outcome == nil || outcome.stopped == false)
+			}
+
+			update := false
+			if saveGroup != "" {
+				if err := saveMatchingProjects(cmd, client, saveGroup, results); err != nil {
+					return config, false, err
+				}
+				update = true
+			}
+
+			// Only building a group does not fail because of clones which could not be evaluated, they are reported
+			// and left out of the group.
+			if failed != 0 && len(args) > 1 {
+				if update {
+					if err := pkg.SaveCommandConfig(cmd, config); err != nil {
+						return config, false, err
+					}
+				}
+
+				if outcome != nil && outcome.exceeded {
+					return config, false, rolloutError(waves, outcome)
+				}
+
+				return config, false, emoji.Errorf("Failed in %d of %d projects.", failed, len(results))
+			}
+
+			return config, update, nil
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringArrayVarP(&where, "where", "w", nil, "Only run in the clones matching the predicate")
+	cmd.Flags().StringVar(&saveGroup, "save-group", "", "Save the projects matching the predicates as a group")
+	rollout.add(cmd)
+
+	return cmd
+}
+
+// printPlan writes the command every clone matching the predicates would run, and the branches it would push.
+func printPlan(cmd *cobra.Command, results []pkg.ExecResult, command string, args []string) error {
+	var changes []pkg.PlannedChange
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":fast_forward: Skipped '%s': %s\n", result.Project, result.SkipReason)
+		case result.Err != nil:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":x: '%s' could not be evaluated: %s\n", result.Project, result.Err)
+		default:
+			changes = append(changes, pkg.PlanCommand(result.Clone, command, args...)...)
+		}
+	}
+
+	emoji.Fprintln(cmd.ErrOrStderr(), ":memo: Dry run, the command has not been run:")
+
+	return pkg.WritePlan(cmd.OutOrStdout(), changes)
+}
+
+func countFailed(results []pkg.ExecResult) int {
+	res := 0
+	for _, result := range results {
+		if result.Skipped == false && result.Err != nil {
+			res++
+		}
+	}
+
+	return res
+}
+
+// printResults writes the output of every project to stdout, prefixed with the project name, and the outcome of
+// every project to stderr. It returns the number of projects in which the command failed.
+func printResults(cmd *cobra.Command, results []pkg.ExecResult, ran bool) int {
+	failed := 0
+	matched := 0
+
+	for _, result := range results {
+		if result.Skipped {
+			emoji.Fprintf(cmd.ErrOrStderr(), ":fast_forward: Skipped '%s': %s\n", result.Project, result.SkipReason)
+			continue
+		}
+
+		writeOutput(cmd.OutOrStdout(), result.Project, result.Output)
+
+		if result.Err != nil {
+			failed++
+			emoji.Fprintf(cmd.ErrOrStderr(), ":x: '%s' failed: %s\n", result.Project, result.Err)
+			continue
+		}
+
+		matched++
+		if ran {
+			emoji.Fprintf(cmd.ErrOrStderr(), ":white_check_mark: '%s' succeeded\n", result.Project)
+		} else {
+			emoji.Fprintf(cmd.ErrOrStderr(), ":white_check_mark: '%s' matches\n", result.Project)
+		}
+	}
+
+	outcome := "succeeded"
+	if ran == false {
+		outcome = "matched"
+	}
+
+	fmt.Fprintf(
+		cmd.ErrOrStderr(),
+		"\n%d %s, %d skipped, %d failed\n",
+		matched,
+		outcome,
+		len(results)-matched-failed,
+		failed,
+	)
+
+	return failed
+}
+
+func writeOutput(w io.Writer, project string, output string) {
+	output = strings.TrimRight(output, "\n")
+	if output == "" {
+		return
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		fmt.Fprintf(w, "%s | %s\n", project, line)
+	}
+}
+
+func saveMatchingProjects(cmd *cobra.Command, client *wildfire.Client, groupName string, results []pkg.ExecResult) error {
+	group, err := client.GetGroup(cmd.Context(), groupName)
+	if err != nil && errors.Is(err, wildfire.ErrNotFound) == false {
+		return err
+	}
+
+	// The group is replaced by the matching projects, projects of included groups would not be matching ones.
+	group.Groups = nil
+	group.Projects = []string{}
+	for _, result := range results {
+		if result.Matched {
+			group.Projects = append(group.Projects, result.Project)
+		}
+	}
+
+	if err := client.SetGroup(cmd.Context(), groupName, group); err != nil {
+		return err
+	}
+
+	emoji.Fprintf(cmd.ErrOrStderr(), ":star: Saved %d projects to group '%s'\n", len(group.Projects), groupName)
+
+	return nil
+}
+
+// execRun records the outcome of the command in every clone in the history, and checkpoints it in the workspace as
+// soon as the command completed so an interrupted run can be resumed.
+type execRun struct {
+	ctx        context.Context
+	entry      *pkg.HistoryEntry
+	checkpoint *pkg.Checkpoint
+	step       *pkg.CheckpointStep
+}
+
+// startRun starts recording the run of the command in the workspace. When a run is resumed the command only runs in
+// the clones which have not completed it.
+func startRun(cmd *cobra.Command, config *pkg.WildFireConfig, workspaceName string, command string) *execRun {
+	run := &execRun{ctx: cmd.Context(), entry: pkg.NewHistoryEntry("exec", workspaceName)}
+	run.entry.Command = command
+
+	workspace := pkg.NewWorkspaceService(config).FindWorkspace(workspaceName)
+	if workspace == nil {
+		return run
+	}
+	run.entry.Group = workspace.Group
+
+	run.checkpoint = pkg.StartCheckpoint(run.ctx, run.entry, workspace.Path)
+	run.step = run.checkpoint.Step("exec", command, pkg.SelectProjects(run.ctx, workspace.Projects))
+	run.entry.Projects = append(run.entry.Projects, run.checkpoint.Completed(run.step)...)
+
+	run.ctx = pkg.WithProjectSelection(run.ctx, run.checkpoint.Incomplete(run.step))
+	run.ctx = wildfire.WithProgress(run.ctx, run.record)
+
+	return run
+}
+
+// record adds the outcome of the command in the clone of the event to the history and completes the clone in the
+// checkpoint.
+func (r *execRun) record(event wildfire.ProjectEvent) {
+	message := ""
+	if event.Status == wildfire.ProjectStatusSkipped {
+		message = event.Output
+	}
+
+	project := r.entry.AddProject(event.Clone, pkg.HistoryStatus(event.Status), message, event.Err)
+	_ = r.checkpoint.Complete(r.step, project)
+}
+
+// finish records the run in the history and removes the checkpoint once the command ran in every project. The
+// checkpoint of a stopped rollout is kept so the rollout can be resumed.
+func (r *execRun) finish(cmd *cobra.Command, completed bool) {
+	if completed == false && r.checkpoint != nil {
+		emoji.Fprintf(cmd.ErrOrStderr(), ":repeat: Continue the rollout with 'wildfire resume %s'\n", r.checkpoint.ID)
+	} else if err := r.checkpoint.Finish(); err != nil {
+		emoji.Fprintf(cmd.ErrOrStderr(), ":warning: Failed to remove the checkpoint of the run: %s\n", err)
+	}
+
+	pkg.RecordRun(cmd, r.entry)
+}
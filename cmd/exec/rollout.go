@@ -0,0 +1,269 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+// rolloutFlags are the flags running the command in waves.
+type rolloutFlags struct {
+	canary         string
+	waveSize       string
+	maxFailureRate string
+	auto           bool
+}
+
+func (f *rolloutFlags) add(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.canary, "canary", "", "Run the command in N projects or N% of the projects first")
+	cmd.Flags().StringVar(&f.waveSize, "wave-size", "", "Run the command in waves of N projects or N% of the projects")
+	cmd.Flags().StringVar(
+		&f.maxFailureRate,
+		"max-failure-rate",
+		"0%",
+		"Stop the rollout when the command fails in more than this percentage of the projects of a wave",
+	)
+	cmd.Flags().BoolVar(&f.auto, "auto", false, "Start the next wave without confirmation while the failure rate is not exceeded")
+}
+
+// parse returns the rollout set with the flags, nil when the command does not run in waves.
+func (f *rolloutFlags) parse() (*pkg.Rollout, error) {
+	if f.canary == "" && f.waveSize == "" {
+		return nil, nil
+	}
+
+	rollout := &pkg.Rollout{}
+	var err error
+
+	if f.canary != "" {
+		if rollout.Canary, err = pkg.ParseRolloutSize(f.canary); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.waveSize != "" {
+		if rollout.WaveSize, err = pkg.ParseRolloutSize(f.waveSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if rollout.MaxFailureRate, err = pkg.ParsePercent(f.maxFailureRate); err != nil {
+		return nil, err
+	}
+
+	return rollout, nil
+}
+
+// confirmWave asks whether the next wave of the rollout should start.
+func confirmWave(message string) (bool, error) {
+	var response bool
+	if err := survey.AskOne(&survey.Confirm{Message: message, Default: false}, &response); err != nil {
+		return false, err
+	}
+
+	return response, nil
+}
+
+// rolloutOutcome is the outcome of a rollout. Stopped is set when the rollout stopped before its last wave, Exceeded
+// when it stopped because the failure rate of a wave exceeded the maximum failure rate.
+type rolloutOutcome struct {
+	waves    []*pkg.RolloutWave
+	ran      int
+	stopped  bool
+	exceeded bool
+}
+
+// runRollout runs the command in the clones of the projects wave after wave. The predicates are evaluated once and
+// only the projects matching them are split into waves, following the dependencies between the projects. Once a wave
+// completed the next one only starts when its failure rate does not exceed the maximum failure rate and, unless auto
+// is set, the user confirms. The rollout stops when the next wave can not be confirmed.
+func runRollout(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client *wildfire.Client,
+	config *pkg.WildFireConfig,
+	run *execRun,
+	rollout *pkg.Rollout,
+	auto bool,
+	workspaceName string,
+	predicates []pkg.ClonePredicate,
+	command string,
+	args []string,
+) ([]pkg.ExecResult, *rolloutOutcome, error) {
+	// The evaluation is not reported to the run, only the projects which do not match are recorded as skipped.
+	evaluated, err := client.Exec(wildfire.WithProgress(ctx, func(wildfire.ProjectEvent) {}), workspaceName, predicates, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	waves, err := planWaves(rollout, config, evaluated)
+	if err != nil {
+		return nil, nil, err
+	}
+	outcome := &rolloutOutcome{waves: waves}
+
+	var res []pkg.ExecResult
+	for _, result := range evaluated {
+		switch {
+		case result.Skipped:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":fast_forward: Skipped '%s': %s\n", result.Project, result.SkipReason)
+			run.record(wildfire.ProjectEvent{Clone: result.Clone, Status: wildfire.ProjectStatusSkipped, Output: result.SkipReason})
+		case result.Err != nil:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":x: '%s' could not be evaluated: %s\n", result.Project, result.Err)
+			run.record(wildfire.ProjectEvent{Clone: result.Clone, Status: wildfire.ProjectStatusFailed, Err: result.Err})
+		default:
+			continue
+		}
+
+		res = append(res, result)
+	}
+
+	for _, wave := range outcome.waves {
+		emoji.Fprintf(
+			cmd.ErrOrStderr(),
+			"\n:ocean: Wave %d of %d%s: %s\n\n",
+			wave.Number,
+			len(outcome.waves),
+			waveLabel(rollout, wave),
+			strings.Join(wave.Projects, ", "),
+		)
+
+		// Projects fail without running when one of their dependencies failed in an earlier wave.
+		waveCtx := wildfire.WithDependencyResults(pkg.WithProjectSelection(ctx, wave.Projects), res)
+		results, err := client.Exec(waveCtx, workspaceName, nil, command, args...)
+		if err != nil {
+			return res, outcome, err
+		}
+
+		printResults(cmd, results, true)
+		wave.AddResults(results)
+		res = append(res, results...)
+		outcome.ran++
+
+		if rollout.Exceeds(wave) {
+			outcome.stopped, outcome.exceeded = outcome.ran < len(outcome.waves), true
+			break
+		}
+
+		if outcome.ran == len(outcome.waves) || auto {
+			continue
+		}
+
+		next := outcome.waves[outcome.ran]
+		proceed, err := confirmWave(fmt.Sprintf("Start wave %d of %d in %d projects?", next.Number, len(outcome.waves), len(next.Projects)))
+		if err != nil {
+			emoji.Fprintf(cmd.ErrOrStderr(), ":warning: Failed to confirm the next wave, use --auto to start it without confirmation: %s\n", err)
+		}
+		if proceed == false {
+			outcome.stopped = true
+			break
+		}
+	}
+
+	fmt.Fprintln(cmd.ErrOrStderr())
+	printWaves(cmd, rollout, outcome)
+
+	return res, outcome, nil
+}
+
+// printWaves writes the outcome of every wave of the rollout as a table.
+func printWaves(cmd *cobra.Command, rollout *pkg.Rollout, outcome *rolloutOutcome) {
+	w := tabwriter.NewWriter(cmd.ErrOrStderr(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WAVE\tPROJECTS\tSUCCEEDED\tFAILED\tFAILURE RATE\tOUTCOME")
+
+	for i, wave := range outcome.waves {
+		status := "completed"
+		switch {
+		case i >= outcome.ran:
+			status = "not run"
+		case rollout.Exceeds(wave):
+			status = "failure rate exceeded"
+		}
+
+		fmt.Fprintf(
+			w,
+			"%d%s\t%d\t%d\t%d\t%s%%\t%s\n",
+			wave.Number,
+			waveLabel(rollout, wave),
+			len(wave.Projects),
+			wave.Succeeded,
+			wave.Failed,
+			strconv.FormatFloat(wave.FailureRate(), 'f', 0, 64),
+			status,
+		)
+	}
+
+	_ = w.Flush()
+}
+
+// planWaves splits the projects matching the predicates into the waves of the rollout. Projects run after their
+// dependencies.
+func planWaves(rollout *pkg.Rollout, config *pkg.WildFireConfig, results []pkg.ExecResult) ([]*pkg.RolloutWave, error) {
+	var projects []string
+	for _, result := range results {
+		if result.Matched {
+			projects = append(projects, result.Project)
+		}
+	}
+
+	projects, err := pkg.GetProjectDependencies(config, projects).Sort(projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return rollout.Waves(projects), nil
+}
+
+// printPlannedWaves writes the projects of every wave the rollout would run in.
+func printPlannedWaves(cmd *cobra.Command, rollout *pkg.Rollout, config *pkg.WildFireConfig, results []pkg.ExecResult) error {
+	waves, err := planWaves(rollout, config, results)
+	if err != nil {
+		return err
+	}
+
+	for _, wave := range waves {
+		emoji.Fprintf(
+			cmd.ErrOrStderr(),
+			":ocean: Wave %d of %d%s: %s\n",
+			wave.Number,
+			len(waves),
+			waveLabel(rollout, wave),
+			strings.Join(wave.Projects, ", "),
+		)
+	}
+
+	return nil
+}
+
+func waveLabel(rollout *pkg.Rollout, wave *pkg.RolloutWave) string {
+	if wave.Number == 1 && rollout.Canary.IsZero() == false {
+		return " (canary)"
+	}
+
+	return ""
+}
+
+// rolloutError returns the error reported when the rollout stopped because of the failure rate of a wave.
+func rolloutError(rollout *pkg.Rollout, outcome *rolloutOutcome) error {
+	if outcome.exceeded == false {
+		return nil
+	}
+
+	wave := outcome.waves[outcome.ran-1]
+
+	return emoji.Errorf(
+		"Rollout stopped after wave %d of %d: the command failed in %d of %d projects, more than %s%%.",
+		wave.Number,
+		len(outcome.waves),
+		wave.Failed,
+		wave.Succeeded+wave.Failed,
+		strconv.FormatFloat(rollout.MaxFailureRate, 'f', -1, 64),
+	)
+}
@@ -5,10 +5,13 @@ import (
 	"github.com/kyokomi/emoji/v2"
 	"github.com/spf13/cobra"
 	"wildfire/pkg"
+	"wildfire/wildfire"
 )
 
 func NewCreateGroupCmd() *cobra.Command {
-	return &cobra.Command{
+	var metadata groupMetadata
+
+	cmd := &cobra.Command{
 		Use:   "create <name> [project...]",
 		Short: "Create project group",
 		Long: `Create new project group.
@@ -24,42 +27,28 @@ Groups are primarily used when commands are to be executed on specific projects.
 			return nil
 		},
 		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-			projectService := pkg.NewProjectService(config)
-			groupService := pkg.NewGroupService(config)
+			client := wildfire.NewClientFromConfig(config)
 
 			groupName := args[0]
 
-			group, err := groupService.CreateGroup(groupName)
-			if err != nil {
+			group := pkg.GroupConfig{Projects: args[1:]}
+			if err := metadata.apply(cmd, pkg.NewGroupService(config), &group); err != nil {
 				return config, false, err
 			}
 
-			projectNames := args[1:]
-
-			emoji.Printf(":star: Created new group '%s'\n", groupName)
-			if len(projectNames) == 0 {
-				return config, true, nil
+			err := client.CreateGroup(cmd.Context(), groupName, group)
+			if errors.Is(err, wildfire.ErrNotFound) {
+				emoji.Println(":prohibited:", err)
+				emoji.Println(":error: Reverting configuration. Resolve issues and try again.")
+				return config, false, nil
 			}
-
-			success := true
-			for _, name := range projectNames {
-				exists := projectService.HasProject(name)
-
-				if exists == false {
-					emoji.Println(":prohibited: ProjectConfig ", name, " does not exist in this configuration.")
-					success = false
-					continue
-				}
-
-				if success == true {
-					emoji.Printf(":ocean: ProjectConfig '%s' has been added to group '%s'\n", name, groupName)
-					group, _ = groupService.AddProject(group, name)
-				}
+			if err != nil {
+				return config, false, err
 			}
 
-			if success == false {
-				emoji.Println(":error: Reverting configuration. Resolve issues and try again.")
-				return config, false, nil
+			emoji.Printf(":star: Created new group '%s'\n", groupName)
+			for _, name := range group.Projects {
+				emoji.Printf(":ocean: ProjectConfig '%s' has been added to group '%s'\n", name, groupName)
 			}
 
 			return config, true, nil
@@ -67,4 +56,8 @@ Groups are primarily used when commands are to be executed on specific projects.
 		SilenceUsage: true,
 		SilenceErrors: true,
 	}
+
+	metadata.addFlags(cmd)
+
+	return cmd
 }
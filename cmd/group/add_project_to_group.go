@@ -5,6 +5,7 @@ import (
 	"github.com/kyokomi/emoji/v2"
 	"github.com/spf13/cobra"
 	"wildfire/pkg"
+	"wildfire/wildfire"
 )
 
 func NewAddProjectToGroupCmd() *cobra.Command {
@@ -24,38 +25,28 @@ If some project name does not exist in the configuration then no projects will b
 			return nil
 		},
 		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-			projectService := pkg.NewProjectService(config)
-			groupService := pkg.NewGroupService(config)
+			client := wildfire.NewClientFromConfig(config)
 
 			groupName := args[0]
-
-			group := groupService.GetGroup(groupName)
-			if group == nil {
+			if pkg.NewGroupService(config).GetGroup(groupName) == nil {
 				return config, false, emoji.Errorf("Group '%s' does not exist", groupName)
 			}
 
 			projectNames := args[1:]
 
-			success := true
-			for _, name := range projectNames {
-				exists := projectService.HasProject(name)
-
-				if exists == false {
-					emoji.Println(":prohibited: ProjectConfig ", name, " does not exist in this configuration.")
-					success = false
-					continue
-				}
-
-				if success == true {
-					emoji.Printf(":ocean: ProjectConfig '%s' has been added to group '%s'\n", name, groupName)
-					group, _ = groupService.AddProject(group, name)
-				}
-			}
-
-			if success == false {
+			err := client.AddProjectsToGroup(cmd.Context(), groupName, projectNames...)
+			if errors.Is(err, wildfire.ErrNotFound) {
+				emoji.Println(":prohibited:", err)
 				emoji.Println(":error: Group was not updated. Resolve issues and try again.")
 				return config, false, nil
 			}
+			if err != nil {
+				return config, false, err
+			}
+
+			for _, name := range projectNames {
+				emoji.Printf(":ocean: ProjectConfig '%s' has been added to group '%s'\n", name, groupName)
+			}
 
 			return config, true, nil
 		}),
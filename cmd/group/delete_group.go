@@ -5,6 +5,7 @@ import (
 	"github.com/kyokomi/emoji/v2"
 	"github.com/spf13/cobra"
 	"wildfire/pkg"
+	"wildfire/wildfire"
 )
 
 func NewDeleteGroupCmd() *cobra.Command {
@@ -20,15 +21,16 @@ func NewDeleteGroupCmd() *cobra.Command {
 			return nil
 		},
 		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-			groupService := pkg.NewGroupService(config)
+			client := wildfire.NewClientFromConfig(config)
 			groupName := args[0]
-			group := groupService.GetGroup(groupName)
 
-			if group == nil {
+			err := client.DeleteGroup(cmd.Context(), groupName)
+			if errors.Is(err, wildfire.ErrNotFound) {
 				return config, false, emoji.Errorf("Group '%s' does not exist in configuration.", groupName)
 			}
-
-			groupService.DeleteGroup(groupName)
+			if err != nil {
+				return config, false, err
+			}
 
 			return config, true, nil
 		}),
@@ -0,0 +1,54 @@
+package group
+
+import (
+	"errors"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func NewDoctorGroupCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Remove references to missing projects from all groups",
+		Long: `Remove references to missing projects and groups from all groups.
+
+Groups can end up referencing projects or included groups which no longer exist in the configuration, for example
+when the configuration file has been edited by hand. Such references are removed from every group.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			repairs, err := client.RepairGroups(cmd.Context())
+			if err != nil {
+				return config, false, err
+			}
+
+			for _, repair := range repairs {
+				if repair.Project != "" {
+					emoji.Printf(":dash: Removed missing project '%s' from group '%s'\n", repair.Project, repair.Group)
+					continue
+				}
+
+				emoji.Printf(":dash: Removed missing group '%s' from group '%s'\n", repair.IncludedGroup, repair.Group)
+			}
+
+			if len(repairs) == 0 {
+				emoji.Println(":star: All group references are valid.")
+			}
+
+			return config, len(repairs) != 0, nil
+		}),
+		Annotations:   map[string]string{pkg.SkipValidationAnnotation: "true"},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+}
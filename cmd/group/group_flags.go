@@ -0,0 +1,66 @@
+package group
+
+import (
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+)
+
+// groupMetadata holds the group metadata flags shared by the create and set commands.
+type groupMetadata struct {
+	description string
+	owner       string
+	path        string
+	branch      string
+	command     string
+	labels      []string
+	groups      []string
+}
+
+func (m *groupMetadata) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&m.description, "description", "d", "", "Description of the group")
+	cmd.Flags().StringVar(&m.owner, "owner", "", "Owner or team responsible for the group")
+	cmd.Flags().StringVar(&m.path, "path", "", "Default path in which the group is cloned")
+	cmd.Flags().StringVar(&m.branch, "branch", "", "Default branch checked out when the group is cloned")
+	cmd.Flags().StringVar(&m.command, "command", "", "Default command to run in the group clones")
+	cmd.Flags().StringSliceVarP(&m.labels, "label", "l", nil, "Label to attach to the group")
+	cmd.Flags().StringSliceVarP(&m.groups, "group", "g", nil, "Group whose projects are included in the group")
+}
+
+// apply sets the metadata of which the flags were provided on the group. An error is returned if an included group
+// does not exist or including it would create a cycle.
+func (m *groupMetadata) apply(cmd *cobra.Command, groupService pkg.GroupService, group *pkg.GroupConfig) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("description") {
+		group.Description = m.description
+	}
+	if flags.Changed("owner") {
+		group.Owner = m.owner
+	}
+	if flags.Changed("path") {
+		group.Path = m.path
+	}
+	if flags.Changed("branch") {
+		group.Branch = m.branch
+	}
+	if flags.Changed("command") {
+		group.Command = m.command
+	}
+	if flags.Changed("label") {
+		group.Labels = m.labels
+	}
+
+	if flags.Changed("group") {
+		includedGroups := group.Groups
+		group.Groups = nil
+
+		for _, groupName := range m.groups {
+			if _, err := groupService.IncludeGroup(group, groupName); err != nil {
+				group.Groups = includedGroups
+				return err
+			}
+		}
+	}
+
+	return nil
+}
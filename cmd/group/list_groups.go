@@ -4,20 +4,34 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"wildfire/pkg"
+	"wildfire/wildfire"
 )
 
 func NewListGroupsCommand() *cobra.Command {
 	return &cobra.Command{
 		Use: "list",
 		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-			groupService := pkg.NewGroupService(config)
-			groupNames := groupService.GetGroupNames()
+			client := wildfire.NewClientFromConfig(config)
+
+			groupNames, err := client.ListGroups(cmd.Context())
+			if err != nil {
+				return config, false, err
+			}
 
 			if len(groupNames) != 0 {
 				fmt.Println(fmt.Sprintf("Found %d groups in configuration:", len(groupNames)))
 				for _, groupName := range groupNames {
-					group := groupService.GetGroup(groupName)
-					fmt.Println(fmt.Sprintf("- %s (%d projects)", groupName, len(*group)))
+					group, err := client.GetGroup(cmd.Context(), groupName)
+					if err != nil {
+						return config, false, err
+					}
+
+					if group.Description != "" {
+						fmt.Println(fmt.Sprintf("- %s (%d projects) - %s", groupName, len(group.Projects), group.Description))
+						continue
+					}
+
+					fmt.Println(fmt.Sprintf("- %s (%d projects)", groupName, len(group.Projects)))
 				}
 			} else {
 				fmt.Println("No groups were found in configuration.")
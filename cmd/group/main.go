@@ -12,4 +12,8 @@ func init() {
 	GroupCmd.AddCommand(NewDeleteGroupCmd())
 	GroupCmd.AddCommand(NewAddProjectToGroupCmd())
 	GroupCmd.AddCommand(NewRemoveProjectFromGroupCmd())
+	GroupCmd.AddCommand(NewShowGroupCmd())
+	GroupCmd.AddCommand(NewDoctorGroupCmd())
+	GroupCmd.AddCommand(NewRenameGroupCmd())
+	GroupCmd.AddCommand(NewSetGroupCmd())
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/kyokomi/emoji/v2"
 	"github.com/spf13/cobra"
 	"wildfire/pkg"
+	"wildfire/wildfire"
 )
 
 func NewRemoveProjectFromGroupCmd() *cobra.Command {
@@ -23,19 +24,16 @@ Will remove projects from group if group exists.
 			return nil
 		},
 		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-			groupService := pkg.NewGroupService(config)
+			client := wildfire.NewClientFromConfig(config)
 
 			groupName := args[0]
 
-			group := groupService.GetGroup(groupName)
-			if group == nil {
+			err := client.RemoveProjectsFromGroup(cmd.Context(), groupName, args[1:]...)
+			if errors.Is(err, wildfire.ErrNotFound) {
 				return config, false, emoji.Errorf("Group '%s' does not exist", groupName)
 			}
-
-			projectNames := args[1:]
-
-			for _, name := range projectNames {
-				group = groupService.RemoveProject(group, name)
+			if err != nil {
+				return config, false, err
 			}
 
 			return config, true, nil
@@ -0,0 +1,41 @@
+package group
+
+import (
+	"errors"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func NewRenameGroupCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old name> <new name>",
+		Short: "Rename project group",
+		Long:  `Rename a project group and the workspace cloned from it`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			err := client.RenameGroup(cmd.Context(), args[0], args[1])
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, emoji.Errorf("Group '%s' does not exist in configuration.", args[0])
+			}
+			if err != nil {
+				return config, false, err
+			}
+
+			emoji.Fprintf(cmd.ErrOrStderr(), ":pencil2: Renamed group '%s' to '%s'\n", args[0], args[1])
+
+			return config, true, nil
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+}
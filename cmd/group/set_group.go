@@ -0,0 +1,59 @@
+package group
+
+import (
+	"errors"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func NewSetGroupCmd() *cobra.Command {
+	var metadata groupMetadata
+
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Update or create project group metadata",
+		Long: `Update the metadata of a project group. The group is created if it does not exist.
+
+Only the metadata of which a flag is provided is changed, pass an empty value to clear it.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			groupName := args[0]
+			group, err := client.GetGroup(cmd.Context(), groupName)
+			created := errors.Is(err, wildfire.ErrNotFound)
+			if err != nil && created == false {
+				return config, false, err
+			}
+
+			if err := metadata.apply(cmd, pkg.NewGroupService(config), &group); err != nil {
+				return config, false, err
+			}
+			if err := client.SetGroup(cmd.Context(), groupName, group); err != nil {
+				return config, false, err
+			}
+
+			if created {
+				emoji.Fprintf(cmd.ErrOrStderr(), ":star: Created new group '%s'\n", groupName)
+			}
+			emoji.Fprintf(cmd.ErrOrStderr(), ":fire: Updated group '%s'\n", groupName)
+
+			return config, true, nil
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	metadata.addFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,272 @@
+package group
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+type groupDetails struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Owner       string                 `json:"owner,omitempty"`
+	Path        string                 `json:"path,omitempty"`
+	Branch      string                 `json:"branch,omitempty"`
+	Command     string                 `json:"command,omitempty"`
+	Labels      []string               `json:"labels,omitempty"`
+	Groups      []string               `json:"groups,omitempty"`
+	Projects    []wildfire.GroupMember `json:"projects"`
+}
+
+func NewShowGroupCmd() *cobra.Command {
+	var output string
+	var tree bool
+	var graph bool
+
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show group members",
+		Long: `Show the projects of a group with their type and URL.
+
+The projects of included groups are listed after the projects of the group itself, use --tree to display
+the group hierarchy instead. Members which reference projects that no longer exist in the configuration
+are flagged as missing. Use 'group doctor' to remove them.
+
+Use --graph to print the dependencies between the projects of the group in the DOT format, which can be
+rendered with Graphviz: wildfire group show backend --graph | dot -Tsvg > backend.svg
+Projects outside of the group which are depended on are drawn dashed.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			format := pkg.OutputFormat(output)
+			if format.ValidFormat() == false {
+				return fmt.Errorf("invalid output format '%s' has been provided", output)
+			}
+
+			if tree && graph {
+				return errors.New("--tree and --graph can not be used together")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			groupName := args[0]
+			group, err := client.GetGroup(cmd.Context(), groupName)
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, emoji.Errorf("Group '%s' does not exist in configuration.", groupName)
+			}
+			if err != nil {
+				return config, false, err
+			}
+
+			members, err := client.GetGroupMembers(cmd.Context(), groupName)
+			if err != nil {
+				return config, false, err
+			}
+
+			if tree {
+				groupTree, err := client.GetGroupTree(cmd.Context(), groupName)
+				if err != nil {
+					return config, false, err
+				}
+
+				return config, false, printGroupTree(cmd.OutOrStdout(), pkg.OutputFormat(output), groupTree)
+			}
+
+			if graph {
+				printGroupGraph(cmd.OutOrStdout(), groupName, members)
+				return config, false, nil
+			}
+
+			details := groupDetails{
+				Name:        groupName,
+				Description: group.Description,
+				Owner:       group.Owner,
+				Path:        group.Path,
+				Branch:      group.Branch,
+				Command:     group.Command,
+				Labels:      group.Labels,
+				Groups:      group.Groups,
+				Projects:    members,
+			}
+
+			if pkg.OutputFormat(output) == pkg.OutputFormatJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+
+				return config, false, encoder.Encode(details)
+			}
+
+			out := cmd.OutOrStdout()
+			printGroupMetadata(out, details)
+
+			if len(details.Projects) == 0 {
+				fmt.Fprintf(out, "Group '%s' has no projects.\n", groupName)
+				return config, false, nil
+			}
+
+			fmt.Fprintf(out, "Group '%s' (%d projects):\n", groupName, len(details.Projects))
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tTYPE\tURL")
+			for _, member := range details.Projects {
+				if member.Missing {
+					fmt.Fprintf(w, "%s\t-\tmissing from configuration\n", member.Name)
+					continue
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\n", member.Name, member.Type, member.URL)
+			}
+			if err := w.Flush(); err != nil {
+				return config, false, err
+			}
+
+			missing := 0
+			for _, member := range details.Projects {
+				if member.Missing {
+					missing++
+				}
+			}
+
+			if missing != 0 {
+				emoji.Fprintf(
+					cmd.ErrOrStderr(),
+					":warning: %d projects are missing from configuration. Run 'group doctor' to remove them.\n",
+					missing,
+				)
+			}
+
+			return config, false, nil
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", string(pkg.OutputFormatTable), "Output format (table, json)")
+	cmd.Flags().BoolVar(&tree, "tree", false, "Display the hierarchy of included groups")
+	cmd.Flags().BoolVar(&graph, "graph", false, "Print the dependencies between the projects of the group in the DOT format")
+
+	return cmd
+}
+
+func printGroupMetadata(out io.Writer, details groupDetails) {
+	metadata := []struct {
+		name  string
+		value string
+	}{
+		{"Description", details.Description},
+		{"Owner", details.Owner},
+		{"Path", details.Path},
+		{"Branch", details.Branch},
+		{"Command", details.Command},
+		{"Labels", strings.Join(details.Labels, ", ")},
+		{"Includes", strings.Join(details.Groups, ", ")},
+	}
+
+	printed := false
+	for _, field := range metadata {
+		if field.value != "" {
+			fmt.Fprintf(out, "%s: %s\n", field.name, field.value)
+			printed = true
+		}
+	}
+
+	if printed {
+		fmt.Fprintln(out)
+	}
+}
+
+// printGroupGraph prints the projects of the group and their dependencies as a DOT digraph. An edge points from a
+// project to the project it depends on.
+func printGroupGraph(out io.Writer, groupName string, members []wildfire.GroupMember) {
+	fmt.Fprintf(out, "digraph %s {\n", strconv.Quote(groupName))
+
+	inGroup := map[string]bool{}
+	for _, member := range members {
+		inGroup[member.Name] = true
+	}
+
+	for _, member := range members {
+		if member.Missing {
+			fmt.Fprintf(out, "  %s [style=dashed, label=%s];\n", strconv.Quote(member.Name), strconv.Quote(member.Name+" (missing)"))
+			continue
+		}
+
+		fmt.Fprintf(out, "  %s;\n", strconv.Quote(member.Name))
+	}
+
+	external := map[string]bool{}
+	for _, member := range members {
+		for _, dependency := range member.DependsOn {
+			if inGroup[dependency] || external[dependency] {
+				continue
+			}
+
+			external[dependency] = true
+			fmt.Fprintf(out, "  %s [style=dashed];\n", strconv.Quote(dependency))
+		}
+	}
+
+	for _, member := range members {
+		for _, dependency := range member.DependsOn {
+			fmt.Fprintf(out, "  %s -> %s;\n", strconv.Quote(member.Name), strconv.Quote(dependency))
+		}
+	}
+
+	fmt.Fprintln(out, "}")
+}
+
+// printGroupTree prints the projects and included groups of the group as a tree.
+func printGroupTree(out io.Writer, format pkg.OutputFormat, node wildfire.GroupTree) error {
+	if format == pkg.OutputFormatJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(node)
+	}
+
+	fmt.Fprintln(out, node.Name)
+	printGroupNode(out, node, "")
+
+	return nil
+}
+
+func printGroupNode(out io.Writer, node wildfire.GroupTree, indent string) {
+	count := len(node.Projects) + len(node.Groups)
+
+	for index, member := range node.Projects {
+		branch, _ := treeBranch(index == count-1)
+		if member.Missing {
+			fmt.Fprintf(out, "%s%s%s (missing)\n", indent, branch, member.Name)
+			continue
+		}
+
+		fmt.Fprintf(out, "%s%s%s\n", indent, branch, member.Name)
+	}
+
+	for index, included := range node.Groups {
+		branch, childIndent := treeBranch(len(node.Projects)+index == count-1)
+		fmt.Fprintf(out, "%s%s%s (group)\n", indent, branch, included.Name)
+		printGroupNode(out, included, indent+childIndent)
+	}
+}
+
+func treeBranch(last bool) (string, string) {
+	if last {
+		return "└── ", "    "
+	}
+
+	return "├── ", "│   "
+}
@@ -0,0 +1,96 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"text/tabwriter"
+	"wildfire/pkg"
+)
+
+func NewListHistoryCmd() *cobra.Command {
+	var (
+		output string
+		limit  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the recorded runs",
+		Long: `List the most recent runs, oldest first, with the number of projects by status.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			format := pkg.OutputFormat(output)
+			if format.ValidFormat() == false {
+				return fmt.Errorf("invalid output format '%s' has been provided", output)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := getHistoryStore(cmd)
+			if err != nil {
+				return err
+			}
+
+			entries, err := store.List()
+			if err != nil {
+				return err
+			}
+
+			if limit > 0 && len(entries) > limit {
+				entries = entries[len(entries)-limit:]
+			}
+
+			return printHistory(cmd, entries, pkg.OutputFormat(output))
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", string(pkg.OutputFormatTable), "Output format (table, json)")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Number of runs to list, 0 lists every run")
+
+	return cmd
+}
+
+func printHistory(cmd *cobra.Command, entries []*pkg.HistoryEntry, format pkg.OutputFormat) error {
+	if format == pkg.OutputFormatJSON {
+		if entries == nil {
+			entries = []*pkg.HistoryEntry{}
+		}
+
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No runs have been recorded.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tUSER\tKIND\tTARGET\tCOMMAND\tPROJECTS")
+	for _, entry := range entries {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.ID,
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.User,
+			entry.Kind,
+			entry.Target,
+			entry.Command,
+			summarize(entry),
+		)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,65 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"strings"
+	"wildfire/pkg"
+)
+
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Inspect and run again the commands run on groups",
+	Long: `Inspect and run again the clones, commands, edits, patches and actions run on the projects of groups.
+
+Every run is recorded with the user, time, group, command, and the status, exit code and resulting commit of every
+project. Runs are appended to history.jsonl next to the global configuration, or to the file set with the
+WILDFIRE_HISTORY environment variable.`,
+}
+
+func init() {
+	HistoryCmd.AddCommand(NewListHistoryCmd())
+	HistoryCmd.AddCommand(NewShowHistoryCmd())
+	HistoryCmd.AddCommand(NewRerunCmd())
+}
+
+func getHistoryStore(cmd *cobra.Command) (*pkg.HistoryStore, error) {
+	store := pkg.GetHistoryStore(cmd.Context())
+	if store == nil {
+		return nil, errors.New("the run history is not available")
+	}
+
+	return store, nil
+}
+
+// summarize counts the projects of the entry by status.
+func summarize(entry *pkg.HistoryEntry) string {
+	statuses := []pkg.HistoryStatus{
+		pkg.HistoryStatusSucceeded,
+		pkg.HistoryStatusChanged,
+		pkg.HistoryStatusSkipped,
+		pkg.HistoryStatusFailed,
+	}
+
+	var res []string
+	for _, status := range statuses {
+		if count := len(entry.GetProjects(status)); count != 0 {
+			res = append(res, fmt.Sprintf("%d %s", count, status))
+		}
+	}
+
+	if len(res) == 0 {
+		return "no projects"
+	}
+
+	return strings.Join(res, ", ")
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+
+	return commit
+}
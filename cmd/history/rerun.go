@@ -0,0 +1,90 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"wildfire/pkg"
+)
+
+func NewRerunCmd() *cobra.Command {
+	var failed bool
+
+	cmd := &cobra.Command{
+		Use:   "rerun <id>",
+		Short: "Run a recorded run again",
+		Long: `Run the command of a recorded run again, from the directory and with the configuration file it was run
+with. With --failed the command only runs on the projects which failed. The new run is recorded as well.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := getHistoryStore(cmd)
+			if err != nil {
+				return err
+			}
+
+			entry, err := store.Get(args[0])
+			if err != nil {
+				return err
+			}
+			if len(entry.Args) == 0 {
+				return emoji.Errorf("Run '%s' has not been started from the command line and can not be run again.", entry.ID)
+			}
+
+			ctx := pkg.WithHistory(cmd.Context(), store, entry.Args)
+			if failed {
+				projects := entry.GetProjects(pkg.HistoryStatusFailed)
+				if len(projects) == 0 {
+					return emoji.Errorf("Run '%s' has no failed projects.", entry.ID)
+				}
+
+				ctx = pkg.WithProjectSelection(ctx, projects)
+				emoji.Fprintf(cmd.ErrOrStderr(), ":dart: Only running on: %s\n", strings.Join(projects, ", "))
+			}
+
+			emoji.Fprintf(cmd.ErrOrStderr(), ":repeat: Running again: wildfire %s\n", strings.Join(entry.Args, " "))
+
+			return runAgain(cmd, ctx, entry.Dir, entry.ConfigFile, entry.Args)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().BoolVar(&failed, "failed", false, "Only run on the projects which failed")
+
+	return cmd
+}
+
+// runAgain runs the root command of cmd with the arguments, from the directory and with the configuration file of the
+// original run.
+func runAgain(cmd *cobra.Command, ctx context.Context, dir string, configFile string, args []string) error {
+	if dir != "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		if err := os.Chdir(dir); err != nil {
+			return err
+		}
+		defer os.Chdir(wd)
+	}
+
+	if configFile != "" {
+		ctx = pkg.WithConfigStore(ctx, pkg.NewFileConfigStore(configFile))
+	}
+
+	root := cmd.Root()
+	root.SetArgs(args)
+
+	return root.ExecuteContext(ctx)
+}
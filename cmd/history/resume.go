@@ -0,0 +1,114 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"wildfire/pkg"
+)
+
+func NewResumeCmd() *cobra.Command {
+	var paths []string
+
+	cmd := &cobra.Command{
+		Use:   "resume [run]",
+		Short: "Resume an interrupted run",
+		Long: `Resume a run of 'exec' or 'clone group' which has been interrupted. Runs checkpoint the progress of every
+project in their workspace, the resumed run skips the steps every project completed and retries the others. Without
+a run the interrupted runs are listed.
+
+Interrupted runs are searched for in the workspaces and the default group paths of the configuration, and in the
+workspace directories provided with --path.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := pkg.GetConfigStore(cmd.Context()).Load()
+			if err != nil {
+				return err
+			}
+
+			checkpoints := pkg.FindCheckpoints(append(getWorkspaceDirs(config), paths...)...)
+			if len(args) == 0 {
+				return printCheckpoints(cmd, checkpoints)
+			}
+
+			checkpoint, err := pkg.FindCheckpoint(checkpoints, args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := pkg.WithCheckpoint(cmd.Context(), checkpoint)
+			if store := pkg.GetHistoryStore(cmd.Context()); store != nil {
+				ctx = pkg.WithHistory(ctx, store, checkpoint.Args)
+			}
+
+			completed, total := checkpoint.Progress()
+			emoji.Fprintf(
+				cmd.ErrOrStderr(),
+				":repeat: Resuming %d of %d projects: wildfire %s\n",
+				total-completed,
+				total,
+				strings.Join(checkpoint.Args, " "),
+			)
+
+			return runAgain(cmd, ctx, checkpoint.Dir, checkpoint.ConfigFile, checkpoint.Args)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringArrayVar(&paths, "path", nil, "Workspace directory to search for interrupted runs")
+
+	return cmd
+}
+
+// getWorkspaceDirs returns the directories of the workspaces and the default directories of the groups.
+func getWorkspaceDirs(config *pkg.WildFireConfig) []string {
+	var res []string
+	for _, workspace := range config.Workspaces {
+		res = append(res, workspace.Path)
+	}
+	for name, group := range config.Groups {
+		if group, err := config.ResolveGroup(name, group); err == nil && group.Path != "" {
+			res = append(res, filepath.Join(group.Path, name))
+		}
+	}
+
+	return res
+}
+
+func printCheckpoints(cmd *cobra.Command, checkpoints []*pkg.Checkpoint) error {
+	if len(checkpoints) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No interrupted runs were found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tKIND\tTARGET\tCOMPLETED\tWORKSPACE")
+	for _, checkpoint := range checkpoints {
+		completed, total := checkpoint.Progress()
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%d/%d\t%s\n",
+			checkpoint.ID,
+			checkpoint.Time.Local().Format("2006-01-02 15:04:05"),
+			checkpoint.Kind,
+			checkpoint.Target,
+			completed,
+			total,
+			filepath.Dir(filepath.Dir(filepath.Dir(checkpoint.Path()))),
+		)
+	}
+
+	return w.Flush()
+}
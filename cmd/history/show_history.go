@@ -0,0 +1,95 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"strings"
+	"text/tabwriter"
+	"wildfire/pkg"
+)
+
+func NewShowHistoryCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a recorded run",
+		Long: `Show a run with the status, exit code, resulting commit and message of every project. The id can be
+shortened as long as it is unique.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			format := pkg.OutputFormat(output)
+			if format.ValidFormat() == false {
+				return fmt.Errorf("invalid output format '%s' has been provided", output)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := getHistoryStore(cmd)
+			if err != nil {
+				return err
+			}
+
+			entry, err := store.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			return printEntry(cmd, entry, pkg.OutputFormat(output))
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", string(pkg.OutputFormatTable), "Output format (table, json)")
+
+	return cmd
+}
+
+func printEntry(cmd *cobra.Command, entry *pkg.HistoryEntry, format pkg.OutputFormat) error {
+	if format == pkg.OutputFormatJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(entry)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Run:      %s\n", entry.ID)
+	fmt.Fprintf(out, "Time:     %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(out, "User:     %s\n", entry.User)
+	fmt.Fprintf(out, "Kind:     %s\n", entry.Kind)
+	fmt.Fprintf(out, "Target:   %s\n", entry.Target)
+	if entry.Group != "" {
+		fmt.Fprintf(out, "Group:    %s\n", entry.Group)
+	}
+	if entry.Command != "" {
+		fmt.Fprintf(out, "Command:  %s\n", entry.Command)
+	}
+	fmt.Fprintf(out, "Run with: wildfire %s\n", strings.Join(entry.Args, " "))
+	fmt.Fprintf(out, "Result:   %s\n\n", summarize(entry))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tSTATUS\tEXIT\tCOMMIT\tMESSAGE")
+	for _, project := range entry.Projects {
+		message := strings.SplitN(strings.TrimSpace(project.Message), "\n", 2)[0]
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%d\t%s\t%s\n",
+			project.Project,
+			project.Status,
+			project.ExitCode,
+			shortCommit(project.Commit),
+			message,
+		)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,131 @@
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"path/filepath"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func NewApplyCmd() *cobra.Command {
+	var (
+		check bool
+		where []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply <group|workspace> <patch-file>",
+		Short: "Apply a patch to the clones of a group",
+		Long: `Apply a unified diff or a git format-patch series to the clones of a workspace, or of the workspace cloned
+from a group, in parallel. Series are committed with 'git am'.
+
+When the patch does not apply cleanly a 3-way merge with the blobs recorded in the patch is attempted. Conflicts are
+left in the clone to be resolved. Clones which already contain the changes of the patch are left unchanged.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			if _, err := pkg.ParseClonePredicates(where); err != nil {
+				return err
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			workspace, err := client.GetWorkspace(cmd.Context(), args[0])
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, emoji.Errorf(
+					"Workspace '%s' does not exist in configuration. Clone the group first.",
+					args[0],
+				)
+			}
+			if err != nil {
+				return config, false, err
+			}
+
+			patchFile, err := filepath.Abs(args[1])
+			if err != nil {
+				return config, false, err
+			}
+
+			// A dry run only checks whether the patch applies.
+			check := check || pkg.IsDryRun(cmd.Context())
+
+			predicates, _ := pkg.ParseClonePredicates(where)
+			results, err := client.ApplyPatch(cmd.Context(), args[0], predicates, patchFile, check)
+			if err != nil {
+				return config, false, err
+			}
+
+			if check == false {
+				recordPatchResults(cmd, workspace, args[0], patchFile, results)
+			}
+
+			return config, false, printPatchResults(cmd, results)
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Only check whether the patch applies without changing the clones")
+	cmd.Flags().StringArrayVarP(&where, "where", "w", nil, "Only apply the patch to the clones matching the predicate")
+
+	return cmd
+}
+
+func printPatchResults(cmd *cobra.Command, results []pkg.PatchResult) error {
+	failed := 0
+
+	for _, result := range results {
+		status := string(result.Status)
+		if result.ThreeWay && result.Err == nil {
+			status += " with 3-way merge"
+		}
+
+		switch {
+		case result.Skipped:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":fast_forward: Skipped '%s': %s\n", result.Project, result.SkipReason)
+		case result.Err != nil:
+			failed++
+			emoji.Fprintf(cmd.ErrOrStderr(), ":x: '%s' %s\n", result.Project, result.Err)
+		case result.Status == pkg.PatchStatusAlreadyApplied:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":zzz: '%s' %s\n", result.Project, status)
+		default:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":white_check_mark: '%s' %s\n", result.Project, status)
+		}
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "\n%d of %d projects failed\n", failed, len(results))
+
+	if failed != 0 {
+		return emoji.Errorf("Failed to apply the patch to %d of %d projects.", failed, len(results))
+	}
+
+	return nil
+}
+
+func recordPatchResults(cmd *cobra.Command, workspace pkg.WorkspaceConfig, name string, patchFile string, results []pkg.PatchResult) {
+	entry := pkg.NewHistoryEntry("patch", name)
+	entry.Group = workspace.Group
+	entry.Command = "apply " + patchFile
+
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			entry.AddProject(result.Clone, pkg.HistoryStatusSkipped, result.SkipReason, nil)
+		case result.Status == pkg.PatchStatusAlreadyApplied:
+			entry.AddProject(result.Clone, pkg.HistoryStatusSucceeded, string(result.Status), result.Err)
+		default:
+			entry.AddProject(result.Clone, pkg.HistoryStatusChanged, string(result.Status), result.Err)
+		}
+	}
+
+	pkg.RecordRun(cmd, entry)
+}
@@ -0,0 +1,109 @@
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func NewCapturePatchCmd() *cobra.Command {
+	var (
+		workspaceName string
+		since         string
+		output        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "capture <project>",
+		Short: "Capture the changes of a clone as a patch",
+		Long: `Capture the changes of the clone of a project as a patch which can be applied to a group with 'wildfire apply'.
+
+The changes compared to HEAD, including files which are not tracked yet, are captured as a unified diff. With --since
+the commits made after the revision are captured as a git format-patch series instead.
+
+When the project has been cloned in more than one workspace the workspace has to be provided.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+			projectName := args[0]
+
+			project, err := client.DescribeProject(cmd.Context(), projectName)
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, emoji.Errorf("Project '%s' does not exist in configuration.", projectName)
+			}
+			if err != nil {
+				return config, false, err
+			}
+
+			if workspaceName == "" {
+				var workspaces []string
+				for name := range project.Workspaces {
+					workspaces = append(workspaces, name)
+				}
+				sort.Strings(workspaces)
+
+				switch len(workspaces) {
+				case 0:
+					return config, false, emoji.Errorf("Project '%s' has not been cloned in any workspace.", projectName)
+				case 1:
+					workspaceName = workspaces[0]
+				default:
+					return config, false, emoji.Errorf(
+						"Project '%s' has been cloned in workspaces %s. Provide one with --workspace.",
+						projectName,
+						strings.Join(workspaces, ", "),
+					)
+				}
+			}
+
+			patch, err := client.CapturePatch(cmd.Context(), workspaceName, projectName, since)
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, emoji.Errorf("Workspace '%s' does not exist in configuration.", workspaceName)
+			}
+			if err != nil {
+				return config, false, fmt.Errorf("failed to capture the changes of '%s': %s", projectName, err)
+			}
+			if patch == "" {
+				return config, false, emoji.Errorf("Project '%s' has no changes to capture.", projectName)
+			}
+
+			if output == "" {
+				_, err = fmt.Fprint(cmd.OutOrStdout(), patch)
+				return config, false, err
+			}
+
+			if pkg.IsDryRun(cmd.Context()) {
+				emoji.Fprintf(cmd.ErrOrStderr(), ":memo: Dry run, the changes of '%s' would be written to '%s'\n", projectName, output)
+				return config, false, nil
+			}
+
+			if err := ioutil.WriteFile(output, []byte(patch), 0644); err != nil {
+				return config, false, err
+			}
+			emoji.Fprintf(cmd.ErrOrStderr(), ":page_facing_up: Changes of '%s' have been written to '%s'\n", projectName, output)
+
+			return config, false, nil
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVar(&workspaceName, "workspace", "", "Workspace containing the clone of the project")
+	cmd.Flags().StringVar(&since, "since", "", "Capture the commits made after the revision as a patch series")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the patch to the file instead of stdout")
+
+	return cmd
+}
@@ -0,0 +1,14 @@
+package patch
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var PatchCmd = &cobra.Command{
+	Use:   "patch",
+	Short: "Capture patches to apply to a group",
+}
+
+func init() {
+	PatchCmd.AddCommand(NewCapturePatchCmd())
+}
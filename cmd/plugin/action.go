@@ -0,0 +1,165 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"strings"
+	"wildfire/pkg"
+)
+
+// NewActionCmd creates the command running the action. Flags preceding the target, e.g. the global --config and
+// --dry-run flags, are parsed as usual. Arguments following the target are passed to the action unparsed, including
+// flags.
+func NewActionCmd(action pkg.Action) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s <workspace|group|project> [args...]", action.Name()),
+		Short: action.Description(),
+		Long: fmt.Sprintf(`%s.
+
+The action runs on the projects of a workspace, of a group or on a single project. Arguments following the target are
+passed to the action.
+`, strings.TrimSuffix(action.Description(), ".")),
+		Annotations: map[string]string{actionAnnotation: action.Name()},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			store := pkg.GetConfigStore(cmd.Context())
+
+			config, err := store.Load()
+			if err != nil {
+				return err
+			}
+
+			context, err := pkg.NewActionContext(config, args[0], args[1:])
+			if err != nil {
+				return err
+			}
+			context.ConfigFile = store.Path()
+			context.Projects = selectProjects(cmd, context.Projects)
+
+			if pkg.IsDryRun(cmd.Context()) {
+				return planAction(cmd, action, context)
+			}
+
+			if plugin, ok := action.(*pkg.PluginAction); ok {
+				plugin.Stderr = cmd.ErrOrStderr()
+			}
+
+			results, err := action.Run(context)
+			failed := printActionResults(cmd, results)
+			recordActionResults(cmd, action, context, results)
+
+			if err != nil {
+				return err
+			}
+			if failed != 0 {
+				return emoji.Errorf("Action '%s' failed for %d of %d projects.", action.Name(), failed, len(results))
+			}
+
+			return nil
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	// Parsing stops at the target, the arguments following it belong to the action.
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+// planAction writes the projects the action would run on, without running it.
+func planAction(cmd *cobra.Command, action pkg.Action, context pkg.ActionContext) error {
+	command := strings.TrimSpace(action.Name() + " " + strings.Join(context.Args, " "))
+
+	var changes []pkg.PlannedChange
+	for _, project := range context.Projects {
+		changes = append(changes, pkg.PlannedChange{
+			Clone:  pkg.Clone{Project: project.Name, Path: project.Path},
+			Action: pkg.PlannedActionRun,
+			Detail: command,
+		})
+	}
+
+	emoji.Fprintf(cmd.ErrOrStderr(), ":memo: Dry run, action '%s' has not been run:\n", action.Name())
+
+	return pkg.WritePlan(cmd.OutOrStdout(), changes)
+}
+
+func printActionResults(cmd *cobra.Command, results []pkg.ActionResult) int {
+	failed := 0
+
+	for _, result := range results {
+		message := result.Message
+		if len(result.Changed) != 0 {
+			message = strings.TrimSpace(fmt.Sprintf("%s %s", message, strings.Join(result.Changed, ", ")))
+		}
+		if message != "" {
+			message = ": " + message
+		}
+
+		switch result.Status {
+		case pkg.ActionStatusFailed:
+			failed++
+			emoji.Fprintf(cmd.ErrOrStderr(), ":x: '%s' failed%s\n", result.Project, message)
+		case pkg.ActionStatusSkipped:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":fast_forward: Skipped '%s'%s\n", result.Project, message)
+		case pkg.ActionStatusChanged:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":pencil2: '%s' changed%s\n", result.Project, message)
+		default:
+			emoji.Fprintf(cmd.ErrOrStderr(), ":white_check_mark: '%s' %s%s\n", result.Project, result.Status, message)
+		}
+	}
+
+	return failed
+}
+
+// selectProjects returns the projects selected in the command context, see pkg.SelectProjects.
+func selectProjects(cmd *cobra.Command, projects []pkg.ActionProject) []pkg.ActionProject {
+	var names []string
+	for _, project := range projects {
+		names = append(names, project.Name)
+	}
+
+	selected := map[string]bool{}
+	for _, name := range pkg.SelectProjects(cmd.Context(), names) {
+		selected[name] = true
+	}
+
+	res := []pkg.ActionProject{}
+	for _, project := range projects {
+		if selected[project.Name] {
+			res = append(res, project)
+		}
+	}
+
+	return res
+}
+
+func recordActionResults(cmd *cobra.Command, action pkg.Action, context pkg.ActionContext, results []pkg.ActionResult) {
+	entry := pkg.NewHistoryEntry("action", context.Target)
+	entry.Group = context.GroupName
+	entry.Command = strings.TrimSpace(action.Name() + " " + strings.Join(context.Args, " "))
+
+	paths := map[string]string{}
+	for _, project := range context.Projects {
+		paths[project.Name] = project.Path
+	}
+
+	for _, result := range results {
+		clone := pkg.Clone{Project: result.Project, Path: paths[result.Project]}
+		message := strings.TrimSpace(fmt.Sprintf("%s %s", result.Message, strings.Join(result.Changed, ", ")))
+
+		if result.Status == pkg.ActionStatusFailed {
+			entry.AddProject(clone, pkg.HistoryStatusFailed, "", errors.New(message))
+		} else {
+			entry.AddProject(clone, pkg.HistoryStatus(result.Status), message, nil)
+		}
+	}
+
+	pkg.RecordRun(cmd, entry)
+}
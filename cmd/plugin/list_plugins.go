@@ -0,0 +1,56 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"text/tabwriter"
+	"wildfire/pkg"
+)
+
+func NewListPluginsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the actions provided by plugins",
+		Long: fmt.Sprintf(`List the registered actions and the plugins found on PATH.
+
+Plugins are executables named %s<name>. They are available as 'wildfire <name>' unless a built-in command has the
+same name.
+`, pkg.PluginPrefix),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			actions := GetActions()
+			if len(actions) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No plugins were found.")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSOURCE")
+			for _, action := range actions {
+				source := "built-in"
+				if plugin, ok := action.(*pkg.PluginAction); ok {
+					source = plugin.Path
+				}
+
+				if isBuiltInCommand(cmd.Root(), action.Name()) {
+					source += " (hidden by the built-in command)"
+				}
+
+				fmt.Fprintf(w, "%s\t%s\n", action.Name(), source)
+			}
+
+			return w.Flush()
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	return cmd
+}
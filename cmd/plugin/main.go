@@ -0,0 +1,67 @@
+package plugin
+
+import (
+	"github.com/spf13/cobra"
+	"os"
+	"wildfire/pkg"
+)
+
+// actionAnnotation marks the commands running an action.
+const actionAnnotation = "wildfire-action"
+
+var PluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage the actions provided by plugins",
+}
+
+func init() {
+	PluginCmd.AddCommand(NewListPluginsCmd())
+}
+
+// GetActions returns the registered actions followed by the plugins found on PATH. Registered actions take
+// precedence over plugins with the same name.
+func GetActions() []pkg.Action {
+	res := pkg.GetActions()
+
+	registered := map[string]bool{}
+	for _, action := range res {
+		registered[action.Name()] = true
+	}
+
+	for _, plugin := range pkg.DiscoverPlugins(os.Getenv("PATH")) {
+		if registered[plugin.Name()] == false {
+			res = append(res, plugin)
+		}
+	}
+
+	return res
+}
+
+// AddActionCommands adds a subcommand to root for every action. Actions named after an existing command are ignored,
+// so plugins can not replace the built-in commands.
+func AddActionCommands(root *cobra.Command) {
+	for _, action := range GetActions() {
+		if isBuiltInCommand(root, action.Name()) {
+			continue
+		}
+
+		root.AddCommand(NewActionCmd(action))
+	}
+}
+
+func isBuiltInCommand(root *cobra.Command, name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+
+	for _, cmd := range root.Commands() {
+		if _, ok := cmd.Annotations[actionAnnotation]; ok {
+			continue
+		}
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+
+	return false
+}
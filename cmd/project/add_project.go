@@ -7,10 +7,14 @@ import (
 	"github.com/spf13/cobra"
 	"strings"
 	"wildfire/pkg"
+	"wildfire/wildfire"
 )
 
 func NewAddProjectCmd() *cobra.Command {
-	return &cobra.Command{
+	var labels []string
+	var dependsOn []string
+
+	cmd := &cobra.Command{
 		Use:   "add name type url",
 		Short: "Add ProjectConfig to the loaded configuration",
 		Long: fmt.Sprintf(`Add a ProjectConfig to the configuration.
@@ -43,12 +47,14 @@ url - The location through which to retrieve clone the project
 			return nil
 		},
 		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-			projectService := pkg.NewProjectService(config)
-			_, err := projectService.AddProject(
-				args[0],
-				pkg.ProjectPath(args[2]),
-				pkg.ProjectType(args[1]),
-			)
+			client := wildfire.NewClientFromConfig(config)
+			err := client.AddProject(cmd.Context(), pkg.ProjectConfig{
+				Name:      args[0],
+				Type:      pkg.ProjectType(args[1]),
+				URL:       pkg.ProjectPath(args[2]),
+				Labels:    labels,
+				DependsOn: dependsOn,
+			})
 
 			if err != nil {
 				return nil, false, err
@@ -58,10 +64,21 @@ url - The location through which to retrieve clone the project
 			fmt.Println("    -> Name: ", args[0])
 			fmt.Println("    -> Type: ", args[1])
 			fmt.Println("    -> URL: ", args[2])
+			if len(labels) != 0 {
+				fmt.Println("    -> Labels: ", strings.Join(labels, ", "))
+			}
+			if len(dependsOn) != 0 {
+				fmt.Println("    -> Depends on: ", strings.Join(dependsOn, ", "))
+			}
 
 			return config, true, nil
 		}),
 		SilenceUsage: true,
 		SilenceErrors: true,
 	}
+
+	cmd.Flags().StringSliceVarP(&labels, "label", "l", nil, "Label to attach to the project")
+	cmd.Flags().StringSliceVar(&dependsOn, "depends-on", nil, "Project which has to succeed before a command runs in the project")
+
+	return cmd
 }
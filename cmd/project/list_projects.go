@@ -0,0 +1,119 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"strings"
+	"text/tabwriter"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func NewListProjectsCmd() *cobra.Command {
+	var (
+		output      string
+		sortBy      string
+		types       []string
+		labels      []string
+		namePattern string
+		groups      []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the projects in the configuration",
+		Long: `List the projects in the configuration.
+
+Projects can be filtered by type, label, name glob pattern and group membership.
+When multiple filters are provided a project has to match all of them.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			format := pkg.OutputFormat(output)
+			if format.ValidFormat() == false {
+				return fmt.Errorf("invalid output format '%s' has been provided", output)
+			}
+
+			field := pkg.ProjectSortField(sortBy)
+			if field.ValidField() == false {
+				return fmt.Errorf("invalid sort field '%s' has been provided", sortBy)
+			}
+
+			for _, projectType := range types {
+				t := pkg.ProjectType(projectType)
+				if t.ValidType() == false {
+					return errors.New("invalid project type has been provided")
+				}
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			filter := pkg.ProjectFilter{
+				Labels:      labels,
+				NamePattern: namePattern,
+				Groups:      groups,
+			}
+			for _, projectType := range types {
+				filter.Types = append(filter.Types, pkg.ProjectType(projectType))
+			}
+
+			listed, err := client.ListProjects(cmd.Context(), filter)
+			if err != nil {
+				return config, false, err
+			}
+
+			var projects []*pkg.ProjectConfig
+			for i := range listed {
+				projects = append(projects, &listed[i])
+			}
+			pkg.SortProjects(projects, pkg.ProjectSortField(sortBy))
+
+			return config, false, printProjects(cmd, projects, pkg.OutputFormat(output))
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", string(pkg.OutputFormatTable), "Output format (table, json)")
+	cmd.Flags().StringVar(&sortBy, "sort", string(pkg.ProjectSortByName), "Sort projects by field (name, type, url)")
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Only list projects of the provided type")
+	cmd.Flags().StringSliceVarP(&labels, "label", "l", nil, "Only list projects which have the provided label")
+	cmd.Flags().StringVar(&namePattern, "name", "", "Only list projects whose name matches the glob pattern")
+	cmd.Flags().StringSliceVarP(&groups, "group", "g", nil, "Only list projects which are members of the provided group")
+
+	return cmd
+}
+
+func printProjects(cmd *cobra.Command, projects []*pkg.ProjectConfig, format pkg.OutputFormat) error {
+	if format == pkg.OutputFormatJSON {
+		if projects == nil {
+			projects = []*pkg.ProjectConfig{}
+		}
+
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(projects)
+	}
+
+	if len(projects) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No projects were found in configuration.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tURL\tLABELS")
+	for _, project := range projects {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", project.Name, project.Type, project.URL, strings.Join(project.Labels, ","))
+	}
+
+	return w.Flush()
+}
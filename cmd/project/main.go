@@ -14,4 +14,7 @@ func init() {
 	ProjectCmd.AddCommand(NewAddProjectCmd())
 	ProjectCmd.AddCommand(NewRemoveProjectCmd())
 	ProjectCmd.AddCommand(NewSetProjectCmd(bufio.NewReader(os.Stdin)))
+	ProjectCmd.AddCommand(NewListProjectsCmd())
+	ProjectCmd.AddCommand(NewShowProjectCmd())
+	ProjectCmd.AddCommand(NewRenameProjectCmd())
 }
@@ -1,9 +1,11 @@
 package project
 
 import (
+	"errors"
 	"github.com/kyokomi/emoji/v2"
 	"github.com/spf13/cobra"
 	"wildfire/pkg"
+	"wildfire/wildfire"
 )
 
 func NewRemoveProjectCmd() *cobra.Command {
@@ -15,20 +17,22 @@ func NewRemoveProjectCmd() *cobra.Command {
 				return config, false, nil
 			}
 
-			projectService := pkg.NewProjectService(config)
-			groupService := pkg.NewGroupService(config)
+			client := wildfire.NewClientFromConfig(config)
 
 			for _, projectName := range args {
-				projectService.RemoveProject(projectName)
+				groups, err := client.RemoveProject(cmd.Context(), projectName)
+				if errors.Is(err, wildfire.ErrNotFound) {
+					emoji.Printf(":warning: Project '%s' does not exist in configuration.\n", projectName)
+					continue
+				}
+				if err != nil {
+					return config, false, err
+				}
 
 				emoji.Println(":cloud: Removed project: ", projectName)
 
-				for groupName, _ := range config.Groups {
-					group := groupService.GetGroup(groupName)
-					if groupService.HasProject(group, projectName) == true {
-						group = groupService.RemoveProject(group, projectName)
-						emoji.Println(emoji.Sprintf(":dash: Removed project '%s' from group '%s'", projectName, groupName))
-					}
+				for _, groupName := range groups {
+					emoji.Println(emoji.Sprintf(":dash: Removed project '%s' from group '%s'", projectName, groupName))
 				}
 			}
 
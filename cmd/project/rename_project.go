@@ -0,0 +1,64 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func NewRenameProjectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old name> <new name>",
+		Short: "Rename project",
+		Long: `Rename a project and update the groups and workspaces which reference it.
+
+Clones of the project in workspaces are moved to the directory of the new name. With --dry-run the moves are listed
+and the clones are left in place.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			moves, err := client.PlanRenameProject(cmd.Context(), args[0], args[1])
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, fmt.Errorf("project with name '%s' does not exist", args[0])
+			}
+			if err != nil {
+				return config, false, err
+			}
+
+			if pkg.IsDryRun(cmd.Context()) && len(moves) != 0 {
+				if err := pkg.WritePlan(cmd.OutOrStdout(), moves); err != nil {
+					return config, false, err
+				}
+			}
+
+			if err := client.RenameProject(cmd.Context(), args[0], args[1]); err != nil {
+				return config, false, err
+			}
+
+			emoji.Fprintf(cmd.ErrOrStderr(), ":pencil2: Renamed project '%s' to '%s'\n", args[0], args[1])
+
+			// The clones have already been moved, they are moved back when the configuration can not be saved.
+			if err := pkg.SaveCommandConfig(cmd, config); err != nil {
+				if pkg.IsDryRun(cmd.Context()) == false {
+					pkg.RestoreClones(moves)
+				}
+
+				return config, false, err
+			}
+
+			return config, false, nil
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"strings"
 	"wildfire/pkg"
+	"wildfire/wildfire"
 )
 
 type CharacterInputReader interface {
@@ -16,7 +17,10 @@ type CharacterInputReader interface {
 }
 
 func NewSetProjectCmd(reader CharacterInputReader) *cobra.Command {
-	return &cobra.Command{
+	var labels []string
+	var dependsOn []string
+
+	cmd := &cobra.Command{
 		Use:   "set name type url",
 		Short: "Update or create a ProjectConfig in the configuration.",
 		Long: fmt.Sprintf(
@@ -52,9 +56,10 @@ url - The location through which to retrieve clone the project
 			return nil
 		},
 		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-			projectService := pkg.NewProjectService(config)
-			project := projectService.GetProject(args[0])
-			if project != nil &&
+			client := wildfire.NewClientFromConfig(config)
+
+			_, err := client.GetProject(cmd.Context(), args[0])
+			if err == nil &&
 				!requestUserApproval(reader, emoji.Sprintf(
 					"ProjectConfig '%s' already exists. Do you wish to overwrite the project configuration?",
 					args[0],
@@ -62,22 +67,38 @@ url - The location through which to retrieve clone the project
 				return config, false, nil
 			}
 
-			projectService.UpdateOrCreate(&pkg.ProjectConfig{
-				Name: args[0],
-				Type: pkg.ProjectType(args[1]),
-				URL:  pkg.ProjectPath(args[2]),
+			err = client.SetProject(cmd.Context(), pkg.ProjectConfig{
+				Name:      args[0],
+				Type:      pkg.ProjectType(args[1]),
+				URL:       pkg.ProjectPath(args[2]),
+				Labels:    labels,
+				DependsOn: dependsOn,
 			})
+			if err != nil {
+				return nil, false, err
+			}
 
 			emoji.Println(":fire: Setting project!")
 			fmt.Println("    -> Name: ", args[0])
 			fmt.Println("    -> Type: ", args[1])
 			fmt.Println("    -> URL: ", args[2])
+			if len(labels) != 0 {
+				fmt.Println("    -> Labels: ", strings.Join(labels, ", "))
+			}
+			if len(dependsOn) != 0 {
+				fmt.Println("    -> Depends on: ", strings.Join(dependsOn, ", "))
+			}
 
 			return config, true, nil
 		}),
 		SilenceUsage: true,
 		SilenceErrors: true,
 	}
+
+	cmd.Flags().StringSliceVarP(&labels, "label", "l", nil, "Label to attach to the project")
+	cmd.Flags().StringSliceVar(&dependsOn, "depends-on", nil, "Project which has to succeed before a command runs in the project")
+
+	return cmd
 }
 
 func requestUserApproval(reader CharacterInputReader, message string) bool {
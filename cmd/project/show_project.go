@@ -0,0 +1,86 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"sort"
+	"strings"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func NewShowProjectCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show project configuration, groups and clones",
+		Long: `Show the configuration of a project, the groups it belongs to and the workspaces in which it
+has been cloned.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			format := pkg.OutputFormat(output)
+			if format.ValidFormat() == false {
+				return fmt.Errorf("invalid output format '%s' has been provided", output)
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			details, err := client.DescribeProject(cmd.Context(), args[0])
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, emoji.Errorf("Project '%s' does not exist in configuration.", args[0])
+			}
+			if err != nil {
+				return config, false, err
+			}
+
+			if pkg.OutputFormat(output) == pkg.OutputFormatJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+
+				return config, false, encoder.Encode(details)
+			}
+
+			project := details.ProjectConfig
+			out := cmd.OutOrStdout()
+			fmt.Fprintln(out, "Name:  ", project.Name)
+			fmt.Fprintln(out, "Type:  ", project.Type)
+			fmt.Fprintln(out, "URL:   ", project.URL)
+			fmt.Fprintln(out, "Labels:", strings.Join(project.Labels, ", "))
+			if len(project.DependsOn) != 0 {
+				fmt.Fprintln(out, "Depends on:", strings.Join(project.DependsOn, ", "))
+			}
+			fmt.Fprintln(out, "Groups:", strings.Join(details.Groups, ", "))
+			fmt.Fprintln(out, "Clones:")
+			if len(details.Workspaces) == 0 {
+				fmt.Fprintln(out, "    No clones found.")
+			}
+			var workspaces []string
+			for name := range details.Workspaces {
+				workspaces = append(workspaces, name)
+			}
+			sort.Strings(workspaces)
+			for _, name := range workspaces {
+				fmt.Fprintf(out, "    -> %s: %s\n", name, details.Workspaces[name])
+			}
+
+			return config, false, nil
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", string(pkg.OutputFormatTable), "Output format (table, json)")
+
+	return cmd
+}
@@ -1,61 +1,120 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"os"
 	"path/filepath"
 	"wildfire/cmd/clone"
+	"wildfire/cmd/config"
+	"wildfire/cmd/dashboard"
+	"wildfire/cmd/edit"
+	"wildfire/cmd/exec"
 	"wildfire/cmd/group"
+	"wildfire/cmd/history"
+	"wildfire/cmd/patch"
+	"wildfire/cmd/plugin"
 	"wildfire/cmd/project"
+	"wildfire/cmd/search"
+	"wildfire/cmd/serve"
+	"wildfire/pkg"
 )
 
 var cfgFile string
+var writeLayer string
+
+// runOptions are set once the flags have been parsed, the commands read them from their context.
+var runOptions = &pkg.RunOptions{}
 
 var rootCmd = &cobra.Command{
 	Use:   "wildfire",
 	Short: "Application for mass update of repositories",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return initConfig(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	// The file of the store is set once the flags have been parsed, see initConfig.
+	ctx := pkg.WithConfigStore(context.Background(), pkg.NewFileConfigStore(""))
+	ctx = pkg.WithRunOptions(ctx, runOptions)
+	if path := pkg.DefaultHistoryPath(); path != "" {
+		ctx = pkg.WithHistory(ctx, pkg.NewHistoryStore(path), os.Args[1:])
+	}
+
+	cobra.CheckErr(rootCmd.ExecuteContext(ctx))
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
-
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.wildfire.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "local config file (default is ./.wildfire.yaml)")
+	rootCmd.PersistentFlags().StringVar(
+		&writeLayer,
+		"layer",
+		pkg.ConfigLayerLocal,
+		"configuration layer to which new entries are written (global, team, local)",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&runOptions.DryRun,
+		"dry-run",
+		false,
+		"show what would change in the configuration, the clones and the remotes without changing them",
+	)
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 
 	rootCmd.AddCommand(project.ProjectCmd)
 	rootCmd.AddCommand(group.GroupCmd)
 	rootCmd.AddCommand(clone.CloneCmd)
+	rootCmd.AddCommand(config.ConfigCmd)
+	rootCmd.AddCommand(edit.EditCmd)
+	rootCmd.AddCommand(patch.PatchCmd)
+	rootCmd.AddCommand(patch.NewApplyCmd())
+	rootCmd.AddCommand(plugin.PluginCmd)
+	rootCmd.AddCommand(dashboard.NewDashboardCmd())
+	rootCmd.AddCommand(exec.NewExecCmd())
+	rootCmd.AddCommand(search.NewGrepCmd())
+	rootCmd.AddCommand(serve.NewServeCmd())
+	rootCmd.AddCommand(history.HistoryCmd)
+	rootCmd.AddCommand(history.NewResumeCmd())
+
+	// Plugins are added last so they can not replace the built-in commands.
+	plugin.AddActionCommands(rootCmd)
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find current directory.
-		dir, err := os.Getwd()
-		cobra.CheckErr(err)
+// initConfig sets the configuration file, the layers and the write layer of the store of the command context. The
+// file of a store which already has one is kept, e.g. the file of the run repeated by 'history rerun'.
+func initConfig(cmd *cobra.Command) error {
+	store, ok := pkg.GetConfigStore(cmd.Context()).(*pkg.FileConfigStore)
+	if ok == false {
+		return nil
+	}
 
-		// Search config in current directory with name ".wildire" (without extension).
-		cfgFile = filepath.FromSlash(dir + "/.wildfire.yaml")
-		viper.SetConfigFile(cfgFile)
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	if store.Path() == "" {
+		path := cfgFile
+		if path == "" {
+			// Search config in current directory with name ".wildire" (without extension).
+			path = filepath.FromSlash(dir + "/.wildfire.yaml")
+		}
+
+		store.SetPath(path)
+	}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err != nil {
-		fmt.Fprintln(os.Stderr, "Configuration file", cfgFile, "failed to load:", err)
+	if _, err := os.Stat(store.Path()); err != nil {
+		fmt.Fprintln(os.Stderr, "Configuration file", store.Path(), "failed to load:", err)
 	} else {
-		fmt.Println("Using configuration file", cfgFile)
+		fmt.Fprintln(os.Stderr, "Using configuration file", store.Path())
 	}
+
+	// Global and team configurations are merged beneath the local configuration.
+	store.SetLayers(pkg.DiscoverConfigLayers(dir)...)
+	store.SetWriteLayer(writeLayer)
+
+	return nil
 }
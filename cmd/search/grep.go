@@ -0,0 +1,168 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"regexp"
+	"text/tabwriter"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+type projectMatches struct {
+	Project string            `json:"project"`
+	Count   int               `json:"count"`
+	Matches []pkg.SearchMatch `json:"matches"`
+	Error   string            `json:"error,omitempty"`
+}
+
+func NewGrepCmd() *cobra.Command {
+	var (
+		output     string
+		count      bool
+		ignoreCase bool
+		ref        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "grep <group|workspace> <pattern>",
+		Short: "Search the clones of a group for a regular expression",
+		Long: `Search the clones of a workspace, or of the workspace cloned from a group, in parallel for lines matching a
+regular expression. Files ignored by .gitignore and binary files are not searched.
+
+With --ref the tree of a branch, tag or commit is read from the repository of every clone instead of the working tree,
+without checking it out. Clones in which the revision does not exist are reported as failed.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			format := pkg.OutputFormat(output)
+			if format.ValidFormat() == false {
+				return fmt.Errorf("invalid output format '%s' has been provided", output)
+			}
+
+			if _, err := compilePattern(args[1], ignoreCase); err != nil {
+				return err
+			}
+
+			return nil
+		},
+		RunE: pkg.ProjectFunc(func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			client := wildfire.NewClientFromConfig(config)
+
+			pattern, _ := compilePattern(args[1], ignoreCase)
+			results, err := client.Search(cmd.Context(), args[0], pattern, ref)
+			if errors.Is(err, wildfire.ErrNotFound) {
+				return config, false, emoji.Errorf(
+					"Workspace '%s' does not exist in configuration. Clone the group first.",
+					args[0],
+				)
+			}
+			if err != nil {
+				return config, false, err
+			}
+
+			failed := 0
+			for _, result := range results {
+				if result.Err != nil {
+					failed++
+					emoji.Fprintf(cmd.ErrOrStderr(), ":x: %s\n", result.Err)
+				}
+			}
+
+			if err := printMatches(cmd, results, pkg.OutputFormat(output), count); err != nil {
+				return config, false, err
+			}
+
+			if failed != 0 {
+				return config, false, emoji.Errorf("Failed to search %d of %d projects.", failed, len(results))
+			}
+
+			return config, false, nil
+		}),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", string(pkg.OutputFormatTable), "Output format (table, json)")
+	cmd.Flags().BoolVarP(&count, "count", "c", false, "Only display the number of matching lines of every project")
+	cmd.Flags().BoolVarP(&ignoreCase, "ignore-case", "i", false, "Ignore case distinctions in the pattern")
+	cmd.Flags().StringVar(&ref, "ref", "", "Search the branch, tag or commit instead of the working tree")
+
+	return cmd
+}
+
+func compilePattern(pattern string, ignoreCase bool) (*regexp.Regexp, error) {
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	expr, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %s", pattern, err)
+	}
+
+	return expr, nil
+}
+
+func printMatches(cmd *cobra.Command, results []pkg.SearchResult, format pkg.OutputFormat, count bool) error {
+	if format == pkg.OutputFormatJSON {
+		res := []projectMatches{}
+		for _, result := range results {
+			matches := projectMatches{Project: result.Project, Count: len(result.Matches), Matches: result.Matches}
+			if count || matches.Matches == nil {
+				matches.Matches = []pkg.SearchMatch{}
+			}
+			if result.Err != nil {
+				matches.Error = result.Err.Error()
+			}
+
+			res = append(res, matches)
+		}
+
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(res)
+	}
+
+	if count {
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PROJECT\tMATCHES")
+		for _, result := range results {
+			if result.Err == nil {
+				fmt.Fprintf(w, "%s\t%d\n", result.Project, len(result.Matches))
+			}
+		}
+
+		return w.Flush()
+	}
+
+	found := false
+	for _, result := range results {
+		if len(result.Matches) == 0 {
+			continue
+		}
+
+		if found {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		found = true
+
+		fmt.Fprintln(cmd.OutOrStdout(), result.Project)
+		for _, match := range result.Matches {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s:%d: %s\n", match.File, match.Line, match.Text)
+		}
+	}
+
+	if found == false {
+		fmt.Fprintln(cmd.OutOrStdout(), "No matches were found.")
+	}
+
+	return nil
+}
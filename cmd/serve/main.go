@@ -0,0 +1,172 @@
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/kyokomi/emoji/v2"
+	"github.com/spf13/cobra"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+	"wildfire/pkg"
+	"wildfire/pkg/api_server"
+	"wildfire/wildfire"
+)
+
+// DefaultAddress is the address the API is served on when none is provided.
+const DefaultAddress = "127.0.0.1:7420"
+
+// TokenVariable is the environment variable providing the token when --token is not set.
+const TokenVariable = "WILDFIRE_API_TOKEN"
+
+func NewServeCmd() *cobra.Command {
+	var (
+		addr       string
+		token      string
+		noToken    bool
+		allowHosts []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the configuration and runs over a local HTTP API",
+		Long: `Serve a REST API returning JSON to list the projects and groups of the configuration, start clone, sync and
+exec runs, and follow their progress by polling the run or streaming its server-sent events.
+
+  GET    /api/projects            projects, filtered by the type, label, name and group query parameters
+  GET    /api/projects/<name>     project
+  GET    /api/groups              groups
+  GET    /api/groups/<name>       group
+  GET    /api/runs                runs started since the server started
+  POST   /api/runs                start a run, e.g. {"type": "exec", "target": "backend", "command": ["make", "test"]}
+  GET    /api/runs/<id>           run with the results of the completed projects
+  DELETE /api/runs/<id>           cancel the run
+  GET    /api/runs/<id>/events    stream a "project" event per completed project then a "done" event
+
+The API is only served on localhost unless another address is provided. Requests have to send a token in an
+"Authorization: Bearer <token>" header or the access_token query parameter. The token is provided with --token or
+the WILDFIRE_API_TOKEN environment variable, otherwise a random token is generated and printed. Use --no-token to
+serve the API without a token.
+
+Requests are only served when their Host and Origin headers are localhost, a loopback address or a host allowed with
+--allow-host. POST and DELETE requests have to be sent with the "Content-Type: application/json" header.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("invalid number of arguments provided")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if pkg.IsDryRun(cmd.Context()) {
+				return emoji.Errorf("The API server does not support --dry-run.")
+			}
+
+			if noToken && token != "" {
+				return emoji.Errorf("The --token and --no-token flags can not be used together")
+			}
+
+			if token == "" && noToken == false {
+				token = os.Getenv(TokenVariable)
+			}
+
+			generated := false
+			if token == "" && noToken == false {
+				var err error
+				if token, err = generateToken(); err != nil {
+					return emoji.Errorf("Failed to generate the token: %s", err)
+				}
+				generated = true
+			}
+
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return emoji.Errorf("Failed to listen on '%s': %s", addr, err)
+			}
+
+			if token == "" && isLoopback(listener.Addr()) == false {
+				emoji.Fprintf(cmd.ErrOrStderr(), ":warning: The API is reachable from the network without a token\n")
+			}
+
+			if generated {
+				emoji.Fprintf(cmd.ErrOrStderr(), ":key: Clients have to send the token %s\n", token)
+			}
+
+			client := wildfire.NewClientFromStore(pkg.GetConfigStore(cmd.Context()))
+			server := api_server.NewServer(client, token)
+			server.AllowHosts(allowHosts...)
+			server.RecordRuns(pkg.GetHistoryStore(cmd.Context()))
+
+			return Serve(cmd, listener, server)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", DefaultAddress, "Address to listen on")
+	cmd.Flags().StringVar(&token, "token", "", "Token required from the clients (default $"+TokenVariable+" or a random token)")
+	cmd.Flags().BoolVar(&noToken, "no-token", false, "Serve the API without a token")
+	cmd.Flags().StringSliceVar(&allowHosts, "allow-host", nil, "Host the clients may reach the API with, in addition to localhost")
+
+	return cmd
+}
+
+// Serve serves the API on the listener until the command context is done or the process is interrupted. Runs in
+// progress are cancelled on shutdown.
+func Serve(cmd *cobra.Command, listener net.Listener, server *api_server.Server) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	httpServer := &http.Server{
+		Handler:     server,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.Serve(listener)
+	}()
+
+	emoji.Fprintf(cmd.ErrOrStderr(), ":rocket: Serving the API on http://%s\n", listener.Addr())
+
+	select {
+	case err := <-errs:
+		server.Close()
+		return err
+	case <-ctx.Done():
+	}
+
+	server.Close()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	emoji.Fprintln(cmd.ErrOrStderr(), ":wave: Stopped serving the API")
+
+	return nil
+}
+
+// generateToken returns a random token of 32 hexadecimal characters.
+func generateToken() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(data), nil
+}
+
+func isLoopback(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+
+	return ok && tcpAddr.IP.IsLoopback()
+}
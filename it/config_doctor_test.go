@@ -0,0 +1,65 @@
+package it_test
+
+import (
+	"bytes"
+	"testing"
+	"wildfire/cmd/config"
+	"wildfire/pkg"
+)
+
+func TestConfigDoctor(t *testing.T) {
+	t.Parallel()
+
+	cfgFile := getConfigFilePath("config_doctor.wildfire.yaml")
+	store, ctx, err := initiateConfiguration(cfgFile)
+	if err != nil {
+		t.Errorf("Failed to initiate configuration. Error: %s", err)
+	}
+
+	cfg := loadConfig(t, store)
+	cfg.Projects["foo"].Name = "other"
+	cfg.Groups["foo"] = &pkg.GroupConfig{Projects: []string{"foo", "foo", "missing"}}
+	err = store.Save(cfg)
+	if err != nil {
+		t.Errorf("Failed to initialize test configuration. Error: %s", err)
+	}
+
+	defer func() {
+		err = deleteConfig(cfgFile)
+		if err != nil {
+			t.Errorf("Failed to tear down test environment. Error: %s", err)
+		}
+	}()
+
+	t.Run("Should return an error and not update the configuration without the fix flag", func(t *testing.T) {
+		cmd := config.NewDoctorConfigCmd()
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{})
+		err := cmd.ExecuteContext(ctx)
+		if err == nil {
+			t.Error("DoctorConfigCmd should have returned an error instead of resolving")
+		}
+
+		if len(loadConfig(t, store).Validate()) != 3 {
+			t.Errorf("Configuration should not have been updated. Found issues %v", loadConfig(t, store).Validate())
+		}
+	})
+
+	t.Run("Should repair the configuration with the fix flag", func(t *testing.T) {
+		cmd := config.NewDoctorConfigCmd()
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--fix"})
+		err := cmd.ExecuteContext(ctx)
+		if err != nil {
+			t.Errorf("DoctorConfigCmd should not have returned an error. Error: %s", err)
+		}
+
+		cfg := loadConfig(t, store)
+		if issues := cfg.Validate(); len(issues) != 0 {
+			t.Errorf("Expected configuration issues to be fixed. Found %v", issues)
+		}
+		if len(cfg.Groups["foo"].Projects) != 1 {
+			t.Errorf("Invalid number of projects in group. Expected '%d' received '%d'", 1, len(cfg.Groups["foo"].Projects))
+		}
+	})
+}
@@ -0,0 +1,94 @@
+//+build integration
+
+package it_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"wildfire/cmd/config"
+	"wildfire/pkg"
+)
+
+func commitRegistry(t *testing.T, dir string, content string) {
+	if err := ioutil.WriteFile(filepath.Join(dir, pkg.DefaultIncludeFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"add", pkg.DefaultIncludeFile},
+		{"-c", "user.name=wildfire", "-c", "user.email=wildfire@example.com", "commit", "-q", "-m", "registry"},
+	} {
+		command := exec.Command("git", args...)
+		command.Dir = dir
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to run git %v. Error: %s %s", args, err, output)
+		}
+	}
+}
+
+func TestConfigRefresh(t *testing.T) {
+	dir := t.TempDir()
+	pkg.SetIncludeCacheDir(filepath.Join(dir, "cache"))
+	defer pkg.SetIncludeCacheDir("")
+
+	repository := filepath.Join(dir, "registry")
+	_ = os.MkdirAll(repository, 0755)
+	if output, err := exec.Command("git", "init", "-q", repository).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to create repository. Error: %s %s", err, output)
+	}
+	commitRegistry(t, repository, "projects:\n  shared:\n    name: shared\n    type: git\n    url: github.com/shared\n")
+
+	cfgFile := filepath.Join(dir, ".wildfire.yaml")
+	if err := ioutil.WriteFile(cfgFile, []byte("includes:\n- name: platform\n  git: "+repository+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, ctx := newConfigStore(cfgFile)
+
+	if _, ok := loadConfig(t, store).Projects["shared"]; ok == false {
+		t.Fatal("The included project should have been loaded")
+	}
+
+	t.Run("should fetch the includes of the configuration again", func(t *testing.T) {
+		commitRegistry(t, repository, "projects:\n  updated:\n    name: updated\n    type: git\n    url: github.com/updated\n")
+
+		var stdout bytes.Buffer
+		cmd := config.NewRefreshConfigCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetArgs([]string{})
+
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Fatalf("RefreshConfigCmd should not have returned an error. Error: %s", err)
+		}
+
+		if !strings.Contains(stdout.String(), "Refreshed 'include:platform'") {
+			t.Errorf("The include should have been reported as refreshed. Received '%s'", stdout.String())
+		}
+		if _, ok := loadConfig(t, store).Projects["updated"]; ok == false {
+			t.Error("The project of the refreshed include should have been loaded")
+		}
+	})
+	t.Run("should list the includes without fetching them in a dry run", func(t *testing.T) {
+		commitRegistry(t, repository, "projects:\n  dry:\n    name: dry\n    type: git\n    url: github.com/dry\n")
+
+		var stdout bytes.Buffer
+		cmd := config.NewRefreshConfigCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetArgs([]string{})
+
+		if err := cmd.ExecuteContext(pkg.WithRunOptions(ctx, &pkg.RunOptions{DryRun: true})); err != nil {
+			t.Fatalf("RefreshConfigCmd should not have returned an error. Error: %s", err)
+		}
+
+		if !strings.Contains(stdout.String(), "Would refresh 'include:platform'") {
+			t.Errorf("The include should have been listed. Received '%s'", stdout.String())
+		}
+		if _, ok := loadConfig(t, store).Projects["dry"]; ok {
+			t.Error("The include should not have been fetched")
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package it_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"wildfire/cmd/exec"
+	"wildfire/pkg"
+)
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+
+	cfgFile := getConfigFilePath("exec.wildfire.yaml")
+	store, ctx, err := initiateConfiguration(cfgFile)
+	if err != nil {
+		t.Errorf("Failed to initiate configuration. Error: %s", err)
+	}
+
+	dir := t.TempDir()
+	for _, project := range []string{"foo", "bar", "zaz"} {
+		_ = os.MkdirAll(filepath.Join(dir, project), 0755)
+	}
+	_ = ioutil.WriteFile(filepath.Join(dir, "foo", "package.json"), []byte("{}"), 0644)
+	_ = ioutil.WriteFile(filepath.Join(dir, "zaz", "package.json"), []byte("{}"), 0644)
+
+	config := loadConfig(t, store)
+	config.Workspaces["backend"] = &pkg.WorkspaceConfig{Path: dir, Group: "backend", Projects: []string{"foo", "bar", "zaz"}}
+	err = store.Save(config)
+	if err != nil {
+		t.Errorf("Failed to initialize test workspace. Error: %s", err)
+	}
+
+	defer func() {
+		err = deleteConfig(cfgFile)
+		if err != nil {
+			t.Errorf("Failed to tear down test environment. Error: %s", err)
+		}
+	}()
+
+	t.Run("should run the command only in the clones matching the predicates", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.NewExecCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{"backend", "--where", "file-exists:package.json", "--", "ls"})
+
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("Exec command should not have returned an error. Error: %s", err)
+		}
+
+		expected := "foo | package.json\nzaz | package.json\n"
+		if stdout.String() != expected {
+			t.Errorf("Invalid output. Expected '%s' received '%s'", expected, stdout.String())
+		}
+
+		if !strings.Contains(stderr.String(), "Skipped 'bar'") {
+			t.Errorf("Project 'bar' should have been reported as skipped. Received '%s'", stderr.String())
+		}
+	})
+
+	t.Run("should save the matching projects as a group", func(t *testing.T) {
+		config := loadConfig(t, store)
+		config.Groups["node"] = &pkg.GroupConfig{Projects: []string{"bar"}, Groups: []string{"backend"}}
+		if err := store.Save(config); err != nil {
+			t.Fatalf("Failed to initialize test group. Error: %s", err)
+		}
+
+		cmd := exec.NewExecCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"backend", "-w", "glob:*.json", "--save-group", "node"})
+
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("Exec command should not have returned an error. Error: %s", err)
+		}
+
+		expected := []string{"foo", "zaz"}
+		if g := loadConfig(t, store).Groups["node"]; g == nil || !reflect.DeepEqual(expected, g.Projects) || len(g.Groups) != 0 {
+			t.Errorf("Invalid group saved. Expected %+v received %+v", expected, g)
+		}
+	})
+
+	t.Run("should return an error if the command fails", func(t *testing.T) {
+		cmd := exec.NewExecCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"backend", "--", "test", "-f", "package.json"})
+
+		if err := cmd.ExecuteContext(ctx); err == nil {
+			t.Error("Exec command should have returned an error instead it resolved")
+		}
+	})
+
+	t.Run("should return an error if the workspace does not exist", func(t *testing.T) {
+		cmd := exec.NewExecCmd()
+		cmd.SetArgs([]string{"missing", "--", "ls"})
+
+		if err := cmd.ExecuteContext(ctx); err == nil {
+			t.Error("Exec command should have returned an error instead it resolved")
+		}
+	})
+}
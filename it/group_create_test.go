@@ -2,21 +2,22 @@ package it_test
 
 import (
 	"fmt"
-	"os"
 	"testing"
 	"wildfire/cmd/group"
 	"wildfire/pkg"
 )
 
 func TestGroupCreate(t *testing.T) {
+	t.Parallel()
+
 	cfgFile := getConfigFilePath("group_create.wildfire.yaml")
-	err := initiateConfiguration(cfgFile)
+	store, ctx, err := initiateConfiguration(cfgFile)
 	if err != nil {
 		t.Errorf("Failed to initiate configuration. Error: %s", err)
 	}
 
 	defer func() {
-		err = os.Remove(cfgFile)
+		err = deleteConfig(cfgFile)
 		if err != nil {
 			t.Errorf("Failed to tear down test environment. Error: %s", err)
 		}
@@ -25,12 +26,12 @@ func TestGroupCreate(t *testing.T) {
 	t.Run("should create empty group if no projects args were provided", func(t *testing.T) {
 		cmd := group.NewCreateGroupCmd()
 		cmd.SetArgs([]string{"foo"})
-		err := cmd.Execute()
+		err := cmd.ExecuteContext(ctx)
 		if err != nil {
 			t.Errorf("Create group command should not have returned an error. Error: %s", err)
 		}
 
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		if len(config.Groups) != 1 {
 			t.Errorf(
 				"Invalid number of groups found in configuration. Expected '%d' found '%d'",
@@ -46,18 +47,18 @@ func TestGroupCreate(t *testing.T) {
 	t.Run("should add projects to group if projects exist", func(t *testing.T) {
 		cmd := group.NewCreateGroupCmd()
 		cmd.SetArgs([]string{"bar", "foo", "bar", "zaz"})
-		err := cmd.Execute()
+		err := cmd.ExecuteContext(ctx)
 		if err != nil {
 			t.Errorf("Command should not have returned an error. Error: %s", err)
 		}
 
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		groupService := pkg.NewGroupService(config)
 
 		newGroup := groupService.GetGroup("bar")
 		fmt.Println(newGroup)
-		if len(*newGroup) != 3 {
-			t.Errorf("Invalid number of projects found in group. Expected '%d' found '%d'", 3, len(*newGroup))
+		if len(newGroup.Projects) != 3 {
+			t.Errorf("Invalid number of projects found in group. Expected '%d' found '%d'", 3, len(newGroup.Projects))
 		}
 	})
 }
@@ -1,29 +1,30 @@
 package it_test
 
 import (
-	"os"
 	"testing"
 	"wildfire/cmd/group"
 	"wildfire/pkg"
 )
 
 func TestGroupDelete(t *testing.T) {
+	t.Parallel()
+
 	cfgFile := getConfigFilePath("group_delete.wildfire.yaml")
-	err := initiateConfiguration(cfgFile)
+	store, ctx, err := initiateConfiguration(cfgFile)
 	if err != nil {
 		t.Errorf("Failed to initiate configuration. Error: %s", err)
 	}
 
-	config := pkg.GetConfig()
+	config := loadConfig(t, store)
 	groupService := pkg.NewGroupService(config)
 	_, _ = groupService.CreateGroup("foo")
-	err = config.SaveConfig()
+	err = store.Save(config)
 	if err != nil {
 		t.Errorf("Failed to initialize test group. Error: %s", err)
 	}
 
 	defer func() {
-		err = os.Remove(cfgFile)
+		err = deleteConfig(cfgFile)
 		if err != nil {
 			t.Errorf("Failed to tear down test environment. Error: %s", err)
 		}
@@ -32,7 +33,7 @@ func TestGroupDelete(t *testing.T) {
 	t.Run("Should throw an error if not enough arguments are passed", func(t *testing.T) {
 		cmd := group.NewDeleteGroupCmd()
 		cmd.SetArgs([]string{})
-		err := cmd.Execute()
+		err := cmd.ExecuteContext(ctx)
 		if err == nil {
 			t.Error("Expected DeleteGroupCMD to return an error instead of resolving")
 		}
@@ -46,12 +47,12 @@ func TestGroupDelete(t *testing.T) {
 	t.Run("Should remove the group from the configuration if the group exists", func(t *testing.T) {
 		cmd := group.NewDeleteGroupCmd()
 		cmd.SetArgs([]string{"foo"})
-		err := cmd.Execute()
+		err := cmd.ExecuteContext(ctx)
 		if err != nil {
 			t.Errorf("DeleteGroupCmd should not have returned an error. Error: %s", err)
 		}
 
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		if len(config.Groups) != 0 {
 			t.Errorf("Invalid number of groups found in configuration. Expected '%d' received '%d'", 0, len(config.Groups))
 		}
@@ -60,7 +61,7 @@ func TestGroupDelete(t *testing.T) {
 	t.Run("Should return an error if the group does not exist in configuration", func(t *testing.T) {
 		cmd := group.NewDeleteGroupCmd()
 		cmd.SetArgs([]string{"foo"})
-		err := cmd.Execute()
+		err := cmd.ExecuteContext(ctx)
 		if err == nil {
 			t.Errorf("DeleteGroupCmd should have returned an error instead of resolving")
 		}
@@ -0,0 +1,98 @@
+package it_test
+
+import (
+	"reflect"
+	"testing"
+	"wildfire/cmd/group"
+	"wildfire/pkg"
+)
+
+func TestGroupSet(t *testing.T) {
+	t.Parallel()
+
+	cfgFile := getConfigFilePath("group_set.wildfire.yaml")
+	store, ctx, err := initiateConfiguration(cfgFile)
+	if err != nil {
+		t.Errorf("Failed to initiate configuration. Error: %s", err)
+	}
+
+	defer func() {
+		err = deleteConfig(cfgFile)
+		if err != nil {
+			t.Errorf("Failed to tear down test environment. Error: %s", err)
+		}
+	}()
+
+	t.Run("should create the group with the provided metadata", func(t *testing.T) {
+		cmd := group.NewCreateGroupCmd()
+		cmd.SetArgs([]string{"api", "foo", "--description", "HTTP services", "--owner", "platform", "-l", "http"})
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("Create group command should not have returned an error. Error: %s", err)
+		}
+
+		expected := pkg.GroupConfig{
+			Description: "HTTP services",
+			Owner:       "platform",
+			Labels:      []string{"http"},
+			Projects:    []string{"foo"},
+		}
+		if g := loadConfig(t, store).Groups["api"]; g == nil || !reflect.DeepEqual(expected, *g) {
+			t.Errorf("Invalid group created. Expected %+v received %+v", expected, g)
+		}
+	})
+
+	t.Run("should only update the provided metadata", func(t *testing.T) {
+		cmd := group.NewSetGroupCmd()
+		cmd.SetArgs([]string{"api", "--branch", "develop", "--command", "make test", "--owner", ""})
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("Set group command should not have returned an error. Error: %s", err)
+		}
+
+		expected := pkg.GroupConfig{
+			Description: "HTTP services",
+			Branch:      "develop",
+			Command:     "make test",
+			Labels:      []string{"http"},
+			Projects:    []string{"foo"},
+		}
+		if g := loadConfig(t, store).Groups["api"]; g == nil || !reflect.DeepEqual(expected, *g) {
+			t.Errorf("Invalid group updated. Expected %+v received %+v", expected, g)
+		}
+	})
+
+	t.Run("should create the group if it does not exist", func(t *testing.T) {
+		cmd := group.NewSetGroupCmd()
+		cmd.SetArgs([]string{"web", "--path", "/tmp/web"})
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("Set group command should not have returned an error. Error: %s", err)
+		}
+
+		if g := loadConfig(t, store).Groups["web"]; g == nil || g.Path != "/tmp/web" {
+			t.Errorf("Group was not created. Received %+v", g)
+		}
+	})
+
+	t.Run("should not include groups which would include each other", func(t *testing.T) {
+		cmd := group.NewSetGroupCmd()
+		cmd.SetArgs([]string{"web", "-g", "api"})
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("Set group command should not have returned an error. Error: %s", err)
+		}
+
+		cmd = group.NewSetGroupCmd()
+		cmd.SetArgs([]string{"api", "-g", "web"})
+		err := cmd.ExecuteContext(ctx)
+		if err == nil {
+			t.Fatal("Set group command should have returned an error instead of resolving")
+		}
+
+		expectedErrString := "group 'api' includes itself: api -> web -> api"
+		if err.Error() != expectedErrString {
+			t.Errorf("Invalid error returned. Expected '%s' received '%s'", expectedErrString, err)
+		}
+
+		if g := loadConfig(t, store).Groups["api"]; len(g.Groups) != 0 {
+			t.Errorf("Group should not have been included. Received %+v", g.Groups)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package it_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"wildfire/cmd/group"
+	"wildfire/pkg"
+)
+
+func TestGroupShow(t *testing.T) {
+	t.Parallel()
+
+	cfgFile := getConfigFilePath("group_show.wildfire.yaml")
+	store, ctx, err := initiateConfiguration(cfgFile)
+	if err != nil {
+		t.Errorf("Failed to initiate configuration. Error: %s", err)
+	}
+
+	config := loadConfig(t, store)
+	config.Groups["foo"] = &pkg.GroupConfig{Projects: []string{"foo", "missing", "bar"}}
+	config.Groups["backend"] = &pkg.GroupConfig{Projects: []string{"zaz", "foo"}, Groups: []string{"foo"}}
+	err = store.Save(config)
+	if err != nil {
+		t.Errorf("Failed to initialize test group. Error: %s", err)
+	}
+
+	defer func() {
+		err = deleteConfig(cfgFile)
+		if err != nil {
+			t.Errorf("Failed to tear down test environment. Error: %s", err)
+		}
+	}()
+
+	t.Run("Should list the group members and flag missing projects", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := group.NewShowGroupCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"foo", "-o", "json"})
+		err := cmd.ExecuteContext(ctx)
+		if err != nil {
+			t.Errorf("ShowGroupCmd should not have returned an error. Error: %s", err)
+		}
+
+		var details struct {
+			Projects []struct {
+				Name    string `json:"name"`
+				URL     string `json:"url"`
+				Missing bool   `json:"missing"`
+			} `json:"projects"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &details); err != nil {
+			t.Errorf("Failed to parse command output. Error: %s", err)
+		}
+
+		if len(details.Projects) != 3 {
+			t.Errorf("Invalid number of group members. Expected '%d' received '%d'", 3, len(details.Projects))
+		}
+
+		for _, member := range details.Projects {
+			if member.Missing != (member.Name == "missing") {
+				t.Errorf("Project '%s' has invalid missing flag '%t'", member.Name, member.Missing)
+			}
+		}
+	})
+
+	t.Run("Should list the projects of included groups once", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := group.NewShowGroupCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"backend", "-o", "json"})
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("ShowGroupCmd should not have returned an error. Error: %s", err)
+		}
+
+		var details struct {
+			Projects []struct {
+				Name string `json:"name"`
+			} `json:"projects"`
+		}
+		_ = json.Unmarshal(out.Bytes(), &details)
+
+		var names []string
+		for _, member := range details.Projects {
+			names = append(names, member.Name)
+		}
+
+		expected := []string{"zaz", "foo", "missing", "bar"}
+		if !reflect.DeepEqual(expected, names) {
+			t.Errorf("Invalid group members. Expected %+v received %+v", expected, names)
+		}
+	})
+
+	t.Run("Should display the group hierarchy", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := group.NewShowGroupCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"backend", "--tree"})
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("ShowGroupCmd should not have returned an error. Error: %s", err)
+		}
+
+		expected := `backend
+├── zaz
+├── foo
+└── foo (group)
+    ├── foo
+    ├── missing (missing)
+    └── bar
+`
+		if out.String() != expected {
+			t.Errorf("Invalid group tree. Expected:\n%s\nReceived:\n%s", expected, out.String())
+		}
+	})
+
+	t.Run("Should return an error if the group does not exist in configuration", func(t *testing.T) {
+		cmd := group.NewShowGroupCmd()
+		cmd.SetArgs([]string{"bar"})
+		err := cmd.ExecuteContext(ctx)
+		if err == nil {
+			t.Errorf("ShowGroupCmd should have returned an error instead of resolving")
+		}
+	})
+}
+
+func TestGroupDoctor(t *testing.T) {
+	t.Parallel()
+
+	cfgFile := getConfigFilePath("group_doctor.wildfire.yaml")
+	store, ctx, err := initiateConfiguration(cfgFile)
+	if err != nil {
+		t.Errorf("Failed to initiate configuration. Error: %s", err)
+	}
+
+	config := loadConfig(t, store)
+	config.Groups["foo"] = &pkg.GroupConfig{Projects: []string{"foo", "missing", "bar"}}
+	config.Groups["bar"] = &pkg.GroupConfig{Projects: []string{"missing", "other_missing"}}
+	err = store.Save(config)
+	if err != nil {
+		t.Errorf("Failed to initialize test groups. Error: %s", err)
+	}
+
+	defer func() {
+		err = deleteConfig(cfgFile)
+		if err != nil {
+			t.Errorf("Failed to tear down test environment. Error: %s", err)
+		}
+	}()
+
+	t.Run("Should remove missing projects from all groups", func(t *testing.T) {
+		cmd := group.NewDoctorGroupCmd()
+		cmd.SetArgs([]string{})
+		err := cmd.ExecuteContext(ctx)
+		if err != nil {
+			t.Errorf("DoctorGroupCmd should not have returned an error. Error: %s", err)
+		}
+
+		config := loadConfig(t, store)
+		gs := pkg.NewGroupService(config)
+		if len(gs.GetGroup("foo").Projects) != 2 {
+			t.Errorf("Invalid number of projects in group 'foo'. Expected '%d' received '%d'", 2, len(gs.GetGroup("foo").Projects))
+		}
+		if len(gs.GetGroup("bar").Projects) != 0 {
+			t.Errorf("Invalid number of projects in group 'bar'. Expected '%d' received '%d'", 0, len(gs.GetGroup("bar").Projects))
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package it_test
+
+import (
+	"bytes"
+	"github.com/spf13/cobra"
+	"reflect"
+	"strings"
+	"testing"
+	"wildfire/cmd/plugin"
+	"wildfire/pkg"
+)
+
+type countProjectsAction struct {
+	context pkg.ActionContext
+}
+
+func (a *countProjectsAction) Name() string {
+	return "count"
+}
+
+func (a *countProjectsAction) Description() string {
+	return "Count the projects"
+}
+
+func (a *countProjectsAction) Run(context pkg.ActionContext) ([]pkg.ActionResult, error) {
+	a.context = context
+
+	var res []pkg.ActionResult
+	for _, project := range context.Projects {
+		status := pkg.ActionStatusSucceeded
+		if project.Name == "zaz" {
+			status = pkg.ActionStatusFailed
+		}
+
+		res = append(res, pkg.ActionResult{Project: project.Name, Status: status})
+	}
+
+	return res, nil
+}
+
+func TestActionCommand(t *testing.T) {
+	t.Parallel()
+
+	cfgFile := getConfigFilePath("plugin.wildfire.yaml")
+	store, ctx, err := initiateConfiguration(cfgFile)
+	if err != nil {
+		t.Errorf("Failed to initiate configuration. Error: %s", err)
+	}
+
+	config := loadConfig(t, store)
+	config.Groups["backend"] = &pkg.GroupConfig{Projects: []string{"foo", "bar"}}
+	config.Groups["all"] = &pkg.GroupConfig{Projects: []string{"foo", "bar", "zaz"}}
+	err = store.Save(config)
+	if err != nil {
+		t.Errorf("Failed to initialize test groups. Error: %s", err)
+	}
+
+	defer func() {
+		err = deleteConfig(cfgFile)
+		if err != nil {
+			t.Errorf("Failed to tear down test environment. Error: %s", err)
+		}
+	}()
+
+	t.Run("should run the action on the projects of the group", func(t *testing.T) {
+		action := &countProjectsAction{}
+		var stderr bytes.Buffer
+		cmd := plugin.NewActionCmd(action)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{"backend", "--verbose"})
+
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("Action command should not have returned an error. Error: %s", err)
+		}
+
+		if action.context.GroupName != "backend" || len(action.context.Projects) != 2 {
+			t.Errorf("Invalid action context. Received %+v", action.context)
+		}
+		if len(action.context.Args) != 1 || action.context.Args[0] != "--verbose" {
+			t.Errorf("Flags should have been passed to the action. Received %v", action.context.Args)
+		}
+		if !strings.Contains(stderr.String(), "'foo' succeeded") {
+			t.Errorf("Results should have been reported. Received '%s'", stderr.String())
+		}
+	})
+
+	t.Run("should parse the global flags preceding the target", func(t *testing.T) {
+		var cfg, layer string
+		root := &cobra.Command{Use: "wildfire"}
+		root.PersistentFlags().StringVar(&cfg, "config", "", "")
+		root.PersistentFlags().StringVar(&layer, "layer", "", "")
+
+		action := &countProjectsAction{}
+		root.AddCommand(plugin.NewActionCmd(action))
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"--config", cfgFile, "count", "--layer=team", "backend", "--config", "other.yaml"})
+
+		if err := root.ExecuteContext(ctx); err != nil {
+			t.Errorf("Action command should not have returned an error. Error: %s", err)
+		}
+
+		if cfg != cfgFile || layer != "team" {
+			t.Errorf("Global flags should have been parsed. Received config '%s' layer '%s'", cfg, layer)
+		}
+		if action.context.Target != "backend" || !reflect.DeepEqual([]string{"--config", "other.yaml"}, action.context.Args) {
+			t.Errorf("Only the arguments following the target should have been passed. Received %+v", action.context)
+		}
+	})
+
+	t.Run("should return an error when the action failed for a project", func(t *testing.T) {
+		var stderr bytes.Buffer
+		cmd := plugin.NewActionCmd(&countProjectsAction{})
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{"all"})
+
+		if err := cmd.ExecuteContext(ctx); err == nil {
+			t.Error("Action command should have returned an error")
+		}
+		if !strings.Contains(stderr.String(), "'zaz' failed") {
+			t.Errorf("Failure should have been reported. Received '%s'", stderr.String())
+		}
+	})
+
+	t.Run("should return an error for an unknown target", func(t *testing.T) {
+		cmd := plugin.NewActionCmd(&countProjectsAction{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"unknown"})
+
+		if err := cmd.ExecuteContext(ctx); err == nil {
+			t.Error("Action command should have returned an error")
+		}
+	})
+}
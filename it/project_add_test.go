@@ -6,23 +6,24 @@ import (
 	"os"
 	"testing"
 	"wildfire/cmd/project"
-	"wildfire/pkg"
 )
 
 func TestAddProject(t *testing.T) {
+	t.Parallel()
+
 	t.Run("Should add project to configuration file", func(t *testing.T) {
 		cfgName := getConfigFilePath("project_add_test.wildfire.yaml")
-		_ = setConfig(cfgName)
+		store, ctx := newConfigStore(cfgName)
 
 		addProjectCmd := project.NewAddProjectCmd()
 		addProjectCmd.SetArgs([]string{"foo", "git", "github.com/example"})
-		err := addProjectCmd.Execute()
+		err := addProjectCmd.ExecuteContext(ctx)
 
 		if err != nil {
 			t.Errorf("Command has failed to execute. Error: %s", err)
 		}
 
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 
 		if len(config.Projects) != 1 {
 			t.Errorf("Invalid number of projects found. Expected '%d' found '%d'", 1, len(config.Projects))
@@ -58,11 +59,11 @@ func TestAddProject(t *testing.T) {
 
 	t.Run("Should throw an error if the provided project type is invalid", func(t *testing.T) {
 		cfgName := getConfigFilePath("add_project_test.wildfire.yaml")
-		_ = setConfig(cfgName)
+		_, ctx := newConfigStore(cfgName)
 
 		addProjectCmd := project.NewAddProjectCmd()
 		addProjectCmd.SetArgs([]string{"foo", "invalid", "github.com/example"})
-		err := addProjectCmd.Execute()
+		err := addProjectCmd.ExecuteContext(ctx)
 
 		if err == nil {
 			t.Errorf("Should have thrown error for invalid project type")
@@ -72,12 +73,12 @@ func TestAddProject(t *testing.T) {
 	t.Run("Should throw an error if the provided project already exists", func(t *testing.T) {
 		cfgName := getConfigFilePath("add_project_test.wildfire.yaml")
 		_ = os.Remove(cfgName)
-		_ = setConfig(cfgName)
+		_, ctx := newConfigStore(cfgName)
 
 		createProject := func() error {
 			addProjectCmd := project.NewAddProjectCmd()
 			addProjectCmd.SetArgs([]string{"foo", "git", "github.com/example"})
-			return addProjectCmd.Execute()
+			return addProjectCmd.ExecuteContext(ctx)
 		}
 
 		_ = createProject()
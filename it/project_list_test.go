@@ -0,0 +1,173 @@
+//+build integration
+
+package it_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"wildfire/cmd/project"
+	"wildfire/pkg"
+)
+
+func TestListProjects(t *testing.T) {
+	t.Parallel()
+
+	cfgFile := getConfigFilePath("project_list.wildfire.yaml")
+	store, ctx, err := initiateConfiguration(cfgFile)
+	if err != nil {
+		t.Errorf("Failed to initiate configuration. Error: %s", err)
+	}
+
+	defer func() {
+		err = deleteConfig(cfgFile)
+		if err != nil {
+			t.Errorf("Failed to tear down test environment. Error: %s", err)
+		}
+	}()
+
+	config := loadConfig(t, store)
+	ps := pkg.NewProjectService(config)
+	gs := pkg.NewGroupService(config)
+	ps.GetProject("foo").Labels = []string{"go"}
+	ps.GetProject("zaz").Type = pkg.ProjectTypeGitLab
+	group, _ := gs.CreateGroup("backend")
+	_, _ = gs.AddProject(group, "bar")
+	_ = store.Save(config)
+
+	t.Run("should list all projects in table format", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := project.NewListProjectsCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{})
+		err := cmd.ExecuteContext(ctx)
+		if err != nil {
+			t.Errorf("List projects command should not have returned an error. Error: %s", err)
+		}
+
+		for _, name := range []string{"NAME", "foo", "bar", "zaz"} {
+			if strings.Contains(out.String(), name) == false {
+				t.Errorf("Expected output to contain '%s'. Output: %s", name, out.String())
+			}
+		}
+	})
+
+	t.Run("should list filtered projects in json format", func(t *testing.T) {
+		tests := []struct {
+			Args     []string
+			Expected []string
+		}{
+			{[]string{"-o", "json"}, []string{"bar", "foo", "zaz"}},
+			{[]string{"-o", "json", "--label", "go"}, []string{"foo"}},
+			{[]string{"-o", "json", "--type", "gitlab"}, []string{"zaz"}},
+			{[]string{"-o", "json", "--group", "backend"}, []string{"bar"}},
+			{[]string{"-o", "json", "--name", "*a*", "--sort", "type"}, []string{"bar", "zaz"}},
+		}
+
+		for _, test := range tests {
+			var out bytes.Buffer
+			cmd := project.NewListProjectsCmd()
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(test.Args)
+			err := cmd.ExecuteContext(ctx)
+			if err != nil {
+				t.Errorf("List projects command should not have returned an error. Error: %s", err)
+			}
+
+			var projects []pkg.ProjectConfig
+			if err := json.Unmarshal(out.Bytes(), &projects); err != nil {
+				t.Errorf("Failed to parse command output. Error: %s", err)
+			}
+
+			var names []string
+			for _, p := range projects {
+				names = append(names, p.Name)
+			}
+
+			if strings.Join(names, ",") != strings.Join(test.Expected, ",") {
+				t.Errorf("Invalid projects listed for %v. Expected '%v' received '%v'", test.Args, test.Expected, names)
+			}
+		}
+	})
+
+	t.Run("should return an error if the output format is invalid", func(t *testing.T) {
+		cmd := project.NewListProjectsCmd()
+		cmd.SetArgs([]string{"-o", "xml"})
+		err := cmd.ExecuteContext(ctx)
+		if err == nil {
+			t.Error("Command should have returned an error, instead it resolved")
+		}
+	})
+}
+
+func TestShowProject(t *testing.T) {
+	t.Parallel()
+
+	cfgFile := getConfigFilePath("project_show.wildfire.yaml")
+	store, ctx, err := initiateConfiguration(cfgFile)
+	if err != nil {
+		t.Errorf("Failed to initiate configuration. Error: %s", err)
+	}
+
+	defer func() {
+		err = deleteConfig(cfgFile)
+		if err != nil {
+			t.Errorf("Failed to tear down test environment. Error: %s", err)
+		}
+	}()
+
+	config := loadConfig(t, store)
+	gs := pkg.NewGroupService(config)
+	group, _ := gs.CreateGroup("backend")
+	_, _ = gs.AddProject(group, "foo")
+	pkg.NewWorkspaceService(config).SetWorkspace("backend", &pkg.WorkspaceConfig{
+		Path:     "/tmp/backend",
+		Group:    "backend",
+		Projects: []string{"foo"},
+	})
+	_ = store.Save(config)
+
+	t.Run("should show the project groups and clones", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := project.NewShowProjectCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"foo", "-o", "json"})
+		err := cmd.ExecuteContext(ctx)
+		if err != nil {
+			t.Errorf("Show project command should not have returned an error. Error: %s", err)
+		}
+
+		var details struct {
+			Name       string            `json:"name"`
+			Groups     []string          `json:"groups"`
+			Workspaces map[string]string `json:"workspaces"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &details); err != nil {
+			t.Errorf("Failed to parse command output. Error: %s", err)
+		}
+
+		if details.Name != "foo" {
+			t.Errorf("Invalid project shown. Expected '%s' received '%s'", "foo", details.Name)
+		}
+		if strings.Join(details.Groups, ",") != "backend" {
+			t.Errorf("Invalid project groups. Expected '%s' received '%v'", "backend", details.Groups)
+		}
+		if _, ok := details.Workspaces["backend"]; ok == false {
+			t.Errorf("Expected project clones to contain workspace 'backend'. Received '%v'", details.Workspaces)
+		}
+	})
+
+	t.Run("should return an error if the project does not exist", func(t *testing.T) {
+		cmd := project.NewShowProjectCmd()
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"missing"})
+		err := cmd.ExecuteContext(ctx)
+		if err == nil {
+			t.Error("Command should have returned an error, instead it resolved")
+		}
+	})
+}
@@ -3,22 +3,23 @@
 package it_test
 
 import (
-	"os"
 	"testing"
 	"wildfire/cmd/project"
 	"wildfire/pkg"
 )
 
 func TestRemoveProject(t *testing.T) {
+	t.Parallel()
+
 	cfgFile := getConfigFilePath("project_remove.wildfire.yaml")
-	err := initiateConfiguration(cfgFile)
+	store, ctx, err := initiateConfiguration(cfgFile)
 	if err != nil {
 		t.Errorf("Failed to initiate configuration. Error: %s", err)
 	}
 
 
 	defer func() {
-		err = os.Remove(cfgFile)
+		err = deleteConfig(cfgFile)
 		if err != nil {
 			t.Errorf("Failed to tear down test environment. Error: %s", err)
 		}
@@ -27,9 +28,9 @@ func TestRemoveProject(t *testing.T) {
 	t.Run("should remove the project from the configuration file", func(t *testing.T) {
 		cmd := project.NewRemoveProjectCmd()
 		cmd.SetArgs([]string{"foo"})
-		_ = cmd.Execute()
+		_ = cmd.ExecuteContext(ctx)
 
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		if len(config.Projects) != 2 {
 			t.Errorf("Failed to remove project from configuration. Expected '%d' projects found '%d'", 2, len(config.Projects))
 		}
@@ -37,41 +38,41 @@ func TestRemoveProject(t *testing.T) {
 
 
 	t.Run("Should remove the all provided projects from the configuration file", func(t *testing.T) {
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		ps := pkg.NewProjectService(config)
 		_, _ = ps.AddProject("foo", "github.com/example", pkg.ProjectTypeGit)
-		_ = config.SaveConfig()
+		_ = store.Save(config)
 
 		cmd := project.NewRemoveProjectCmd()
 		cmd.SetArgs([]string{"foo", "bar"})
-		_ = cmd.Execute()
+		_ = cmd.ExecuteContext(ctx)
 
-		config = pkg.GetConfig()
+		config = loadConfig(t, store)
 		if len(config.Projects) != 1 {
 			t.Errorf("Failed to remove projects from configuration. Expected '%d' projects found '%d'", 1, len(config.Projects))
 		}
 	})
 
 	t.Run("Should not update the configuration if no project names have been provided", func(t *testing.T) {
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		ps := pkg.NewProjectService(config)
 
 		_, _ = ps.AddProject("foo", "github.com/example", pkg.ProjectTypeGit)
 		_, _ = ps.AddProject("bar", "github.com/example", pkg.ProjectTypeGit)
-		_ = config.SaveConfig()
+		_ = store.Save(config)
 
 		cmd := project.NewRemoveProjectCmd()
 		cmd.SetArgs([]string{})
-		_ = cmd.Execute()
+		_ = cmd.ExecuteContext(ctx)
 
-		config = pkg.GetConfig()
+		config = loadConfig(t, store)
 		if len(config.Projects) != 3 {
 			t.Errorf("Invalid number of projects from configuration. Expected '%d' projects found '%d'", 3, len(config.Projects))
 		}
 	})
 
 	t.Run("Should remove removed project from groups which contain said project", func(t *testing.T) {
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		ps := pkg.NewProjectService(config)
 		gs := pkg.NewGroupService(config)
 
@@ -82,19 +83,19 @@ func TestRemoveProject(t *testing.T) {
 		group, _ = gs.AddProject(group, "foo")
 		group, _ = gs.AddProject(group, "bar")
 		group, _ = gs.AddProject(group, "zaz")
-		_ = config.SaveConfig()
+		_ = store.Save(config)
 
 		cmd := project.NewRemoveProjectCmd()
 		cmd.SetArgs([]string{"foo"})
-		err := cmd.Execute()
+		err := cmd.ExecuteContext(ctx)
 		if err != nil {
 			t.Errorf("RemoveProject should have not returned an error. Error: %s", err)
 		}
 
-		config = pkg.GetConfig()
+		config = loadConfig(t, store)
 		gs = pkg.NewGroupService(config)
 		group = gs.GetGroup("foo")
-		for _, projectName := range *group {
+		for _, projectName := range group.Projects {
 			if projectName == "foo" {
 				t.Error("Should not have found project 'foo' in group")
 			}
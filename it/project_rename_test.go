@@ -0,0 +1,65 @@
+//+build integration
+
+package it_test
+
+import (
+	"reflect"
+	"testing"
+	"wildfire/cmd/project"
+	"wildfire/pkg"
+)
+
+func TestRenameProject(t *testing.T) {
+	t.Parallel()
+
+	cfgFile := getConfigFilePath("project_rename.wildfire.yaml")
+	store, ctx, err := initiateConfiguration(cfgFile)
+	if err != nil {
+		t.Errorf("Failed to initiate configuration. Error: %s", err)
+	}
+
+	config := loadConfig(t, store)
+	config.Groups["api"] = &pkg.GroupConfig{Projects: []string{"foo", "bar"}}
+	if err = store.Save(config); err != nil {
+		t.Errorf("Failed to initialize test group. Error: %s", err)
+	}
+
+	defer func() {
+		err = deleteConfig(cfgFile)
+		if err != nil {
+			t.Errorf("Failed to tear down test environment. Error: %s", err)
+		}
+	}()
+
+	t.Run("should rename the project in the configuration file", func(t *testing.T) {
+		cmd := project.NewRenameProjectCmd()
+		cmd.SetArgs([]string{"foo", "baz"})
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Errorf("RenameProjectCmd should not have returned an error. Error: %s", err)
+		}
+
+		config := loadConfig(t, store)
+		if config.Projects["foo"] != nil || config.Projects["baz"] == nil {
+			t.Errorf("Project was not renamed. Projects %+v", config.Projects)
+		}
+
+		expected := []string{"baz", "bar"}
+		if !reflect.DeepEqual(expected, config.Groups["api"].Projects) {
+			t.Errorf("Invalid group projects. Expected %+v received %+v", expected, config.Groups["api"].Projects)
+		}
+	})
+
+	t.Run("should return an error if the project does not exist", func(t *testing.T) {
+		cmd := project.NewRenameProjectCmd()
+		cmd.SetArgs([]string{"foo", "zaz"})
+		err := cmd.ExecuteContext(ctx)
+		if err == nil {
+			t.Fatal("RenameProjectCmd should have returned an error instead of resolving")
+		}
+
+		expectedErrString := "project with name 'foo' does not exist"
+		if err.Error() != expectedErrString {
+			t.Errorf("Invalid error returned. Expected '%s' received '%s'", expectedErrString, err)
+		}
+	})
+}
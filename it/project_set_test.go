@@ -3,7 +3,6 @@
 package it_test
 
 import (
-	"os"
 	"testing"
 	"wildfire/cmd/project"
 	"wildfire/pkg"
@@ -25,14 +24,16 @@ func (r MockInputReader) ReadRune() (rune, int, error) {
 }
 
 func TestSetProject(t *testing.T) {
+	t.Parallel()
+
 	cfgFile := getConfigFilePath("project_set.wildfire.yaml")
-	err := initiateConfiguration(cfgFile)
+	store, ctx, err := initiateConfiguration(cfgFile)
 	if err != nil {
 		t.Errorf("Failed to initiate configuration. Error: %s", err)
 	}
 
 	defer func() {
-		err = os.Remove(cfgFile)
+		err = deleteConfig(cfgFile)
 		if err != nil {
 			t.Errorf("Failed to tear down test environment. Error: %s", err)
 		}
@@ -41,12 +42,12 @@ func TestSetProject(t *testing.T) {
 	t.Run("Should not replace the project configuration if we don't have user approval", func(t *testing.T) {
 		cmd := project.NewSetProjectCmd(NewMockInputReader('N'))
 		cmd.SetArgs([]string{"foo", string(pkg.ProjectTypeGitLab), "github.com/example/new"})
-		err = cmd.Execute()
+		err = cmd.ExecuteContext(ctx)
 		if err != nil {
 			t.Errorf("Failed to Set ProjectConfig. Command should have resolved. Error: %s", err)
 		}
 
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		ps := pkg.NewProjectService(config)
 		updatedPackage := ps.GetProject("foo")
 		if updatedPackage.Type != pkg.ProjectTypeGit {
@@ -68,12 +69,12 @@ func TestSetProject(t *testing.T) {
 	t.Run("Should replace the project configuration if it already exists and we have user approval", func(t *testing.T) {
 		cmd := project.NewSetProjectCmd(NewMockInputReader('y'))
 		cmd.SetArgs([]string{"foo", string(pkg.ProjectTypeGitLab), "github.com/example/new"})
-		err = cmd.Execute()
+		err = cmd.ExecuteContext(ctx)
 		if err != nil {
 			t.Errorf("Failed to Set ProjectConfig. Command should have resolved. Error: %s", err)
 		}
 
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		ps := pkg.NewProjectService(config)
 		updatedPackage := ps.GetProject("foo")
 		if updatedPackage.Type != pkg.ProjectTypeGitLab {
@@ -95,12 +96,12 @@ func TestSetProject(t *testing.T) {
 	t.Run("Should not request input if the project does not exist", func(t *testing.T) {
 		cmd := project.NewSetProjectCmd(NewMockInputReader('y'))
 		cmd.SetArgs([]string{"baz", string(pkg.ProjectTypeBitBucket), "bitbucket.com/example/bar"})
-		err := cmd.Execute()
+		err := cmd.ExecuteContext(ctx)
 		if err != nil {
 			t.Errorf("Command should not have returned an error. Error %s", err)
 		}
 
-		config := pkg.GetConfig()
+		config := loadConfig(t, store)
 		ps := pkg.NewProjectService(config)
 		newProject := ps.GetProject("baz")
 		if newProject.Type != pkg.ProjectTypeBitBucket {
@@ -122,7 +123,7 @@ func TestSetProject(t *testing.T) {
 	t.Run("should return an error if the provided project type is invalid", func(t *testing.T) {
 		cmd := project.NewSetProjectCmd(NewMockInputReader('y'))
 		cmd.SetArgs([]string{"baz", "invalid_type", "bitbucket.com/example/bar"})
-		err := cmd.Execute()
+		err := cmd.ExecuteContext(ctx)
 		if err == nil {
 			t.Error("Command should have returned an error, instead it resolved")
 		}
@@ -132,7 +133,7 @@ func TestSetProject(t *testing.T) {
 		testCall := func(args []string) {
 			cmd := project.NewSetProjectCmd(NewMockInputReader('y'))
 			cmd.SetArgs([]string{"foo"})
-			err := cmd.Execute()
+			err := cmd.ExecuteContext(ctx)
 			if err == nil {
 				t.Error("Command should have returned an error, instead it resolved")
 			}
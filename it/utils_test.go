@@ -1,10 +1,11 @@
 package it_test
 
 import (
+	"context"
 	"fmt"
-	"github.com/spf13/viper"
 	"os"
 	"path/filepath"
+	"testing"
 	"wildfire/pkg"
 )
 
@@ -13,41 +14,56 @@ func getConfigFilePath(fileName string) string {
 	return filepath.FromSlash(fmt.Sprintf("%s/testdata/%s", dir, fileName))
 }
 
-func setConfig(cfgFile string) error {
-	viper.Reset()
-	viper.SetConfigFile(cfgFile)
-	err := viper.ReadInConfig()
+// newConfigStore returns a store for the configuration file and a context in which the commands use it.
+func newConfigStore(cfgFile string) (pkg.ConfigStore, context.Context) {
+	store := pkg.NewFileConfigStore(cfgFile)
 
+	return store, pkg.WithConfigStore(context.Background(), store)
+}
+
+func loadConfig(t *testing.T, store pkg.ConfigStore) *pkg.WildFireConfig {
+	config, err := store.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load '%s' configuration file. Error %s", cfgFile, err)
+		t.Fatalf("Failed to load configuration. Error: %s", err)
 	}
 
-	return nil
+	return config
 }
 
 func deleteConfig(cfgFile string) error {
+	backups, _ := filepath.Glob(cfgFile + ".bak.*")
+	for _, backup := range backups {
+		_ = os.Remove(backup)
+	}
+
 	return os.Remove(cfgFile)
 }
 
-func initiateConfiguration(cfgFile string) error {
-	_ = os.Remove(cfgFile)
-	_ = setConfig(cfgFile)
-	config := pkg.GetConfig()
+func initiateConfiguration(cfgFile string) (pkg.ConfigStore, context.Context, error) {
+	_ = deleteConfig(cfgFile)
+	store, ctx := newConfigStore(cfgFile)
+	config, err := store.Load()
+	if err != nil {
+		return store, ctx, err
+	}
 	projectService := pkg.NewProjectService(config)
-	projectService.UpdateOrCreate(&pkg.ProjectConfig{"foo", pkg.ProjectTypeGit, "github.com/url"})
-	projectService.UpdateOrCreate(&pkg.ProjectConfig{"bar", pkg.ProjectTypeGit, "github.com/url"})
-	projectService.UpdateOrCreate(&pkg.ProjectConfig{"zaz", pkg.ProjectTypeGit, "github.com/url"})
-	err := config.SaveConfig()
+	projectService.UpdateOrCreate(&pkg.ProjectConfig{Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/url"})
+	projectService.UpdateOrCreate(&pkg.ProjectConfig{Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/url"})
+	projectService.UpdateOrCreate(&pkg.ProjectConfig{Name: "zaz", Type: pkg.ProjectTypeGit, URL: "github.com/url"})
+	err = store.Save(config)
+	if err != nil {
+		return store, ctx, fmt.Errorf("failed to save configuration")
+	}
+	config, err = store.Load()
 	if err != nil {
-		return fmt.Errorf("failed to save configuration")
+		return store, ctx, err
 	}
-	config = pkg.GetConfig()
 	if len(config.Projects) != 3 {
-		return fmt.Errorf(
+		return store, ctx, fmt.Errorf(
 			"expected to have 3 projects found '%d'",
 			len(config.Projects),
 			)
 	}
 
-	return nil
+	return store, ctx, nil
 }
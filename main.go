@@ -2,10 +2,11 @@ package main
 
 import (
 	"github.com/kyokomi/emoji/v2"
+	"os"
 	"wildfire/cmd"
 )
 
 func main() {
-	emoji.Println(":fire: Starting a WildFire :fire:\n")
+	emoji.Fprint(os.Stderr, ":fire: Starting a WildFire :fire:\n\n")
 	cmd.Execute()
 }
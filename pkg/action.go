@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type ActionStatus string
+
+const (
+	ActionStatusSucceeded ActionStatus = "succeeded"
+	ActionStatusChanged   ActionStatus = "changed"
+	ActionStatusSkipped   ActionStatus = "skipped"
+	ActionStatusFailed    ActionStatus = "failed"
+)
+
+// ActionProject is a project an action runs on. Path is the location of its clone, empty when the project has not
+// been cloned.
+type ActionProject struct {
+	Name   string      `json:"name"`
+	Type   ProjectType `json:"type"`
+	URL    ProjectPath `json:"url"`
+	Labels []string    `json:"labels,omitempty"`
+	Path   string      `json:"path,omitempty"`
+}
+
+// ActionContext describes what an action runs on: the projects of a workspace, a group or a single project. Group and
+// Workspace are set when the target is, or has been cloned from, a group or a workspace.
+type ActionContext struct {
+	Target     string           `json:"target"`
+	Args       []string         `json:"args"`
+	ConfigFile string           `json:"config_file,omitempty"`
+	Group      *GroupConfig     `json:"group,omitempty"`
+	GroupName  string           `json:"group_name,omitempty"`
+	Workspace  *WorkspaceConfig `json:"workspace,omitempty"`
+	Projects   []ActionProject  `json:"projects"`
+}
+
+// ActionResult is the outcome of an action for a project.
+type ActionResult struct {
+	Project string       `json:"project"`
+	Status  ActionStatus `json:"status"`
+	Message string       `json:"message,omitempty"`
+	Changed []string     `json:"changed,omitempty"`
+}
+
+// Action is a custom operation on the projects of a group, registered with RegisterAction or provided by a plugin
+// executable. Actions are available as wildfire subcommands named after them.
+type Action interface {
+	Name() string
+	Description() string
+	Run(context ActionContext) ([]ActionResult, error)
+}
+
+var (
+	actions      = map[string]Action{}
+	actionsMutex sync.Mutex
+)
+
+// RegisterAction makes the action available as a subcommand. An action registered with the name of another one
+// replaces it.
+func RegisterAction(action Action) {
+	actionsMutex.Lock()
+	defer actionsMutex.Unlock()
+
+	actions[action.Name()] = action
+}
+
+// GetActions returns the registered actions sorted by name.
+func GetActions() []Action {
+	actionsMutex.Lock()
+	defer actionsMutex.Unlock()
+
+	var res []Action
+	for _, action := range actions {
+		res = append(res, action)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name() < res[j].Name()
+	})
+
+	return res
+}
+
+// NewActionContext creates the context of an action run on target, which is the name of a workspace, of a group or of
+// a project, resolved in that order. Groups are resolved to the workspace cloned from them when there is one.
+func NewActionContext(config *WildFireConfig, target string, args []string) (ActionContext, error) {
+	workspaceService := NewWorkspaceService(config)
+	groupService := NewGroupService(config)
+	projectService := NewProjectService(config)
+
+	context := ActionContext{Target: target, Args: append([]string{}, args...), Projects: []ActionProject{}}
+
+	var projects []string
+	paths := map[string]string{}
+
+	if workspace := workspaceService.FindWorkspace(target); workspace != nil {
+		context.Workspace = workspace
+		context.GroupName = workspace.Group
+		context.Group = groupService.GetGroup(workspace.Group)
+
+		projects = workspace.Projects
+		for _, clone := range workspaceService.GetClones(workspace) {
+			paths[clone.Project] = clone.Path
+		}
+	} else if group := groupService.GetGroup(target); group != nil {
+		context.Group = group
+		context.GroupName = target
+
+		groupProjects, err := groupService.GetGroupProjects(target)
+		if err != nil {
+			return context, err
+		}
+		projects = groupProjects
+	} else if projectService.HasProject(target) {
+		projects = []string{target}
+		if workspaces := workspaceService.GetProjectWorkspaces(target); len(workspaces) != 0 {
+			paths[target] = workspaceService.GetClonePath(workspaceService.GetWorkspace(workspaces[0]), target)
+		}
+	} else {
+		return context, fmt.Errorf("no workspace, group or project named '%s' exists in configuration", target)
+	}
+
+	for _, name := range projects {
+		actionProject := ActionProject{Name: name, Path: paths[name]}
+		if project := projectService.GetProject(name); project != nil {
+			if resolved, err := config.ResolveProject(project); err == nil {
+				project = resolved
+			}
+
+			actionProject.Type, actionProject.URL, actionProject.Labels = project.Type, project.URL, project.Labels
+		}
+
+		context.Projects = append(context.Projects, actionProject)
+	}
+
+	return context, nil
+}
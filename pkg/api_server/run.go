@@ -0,0 +1,260 @@
+package api_server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+type RunType string
+
+const (
+	RunTypeClone RunType = "clone"
+	RunTypeSync  RunType = "sync"
+	RunTypeExec  RunType = "exec"
+)
+
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+	RunStatusCancelled RunStatus = "cancelled"
+)
+
+// RunRequest describes the run to start. Target is the group to clone, or the workspace, or group it was cloned
+// from, to sync or run the command in. Path and Projects are only used by clone runs, Command and Where by exec
+// runs. An exec run without a command only evaluates the predicates.
+type RunRequest struct {
+	Type     RunType  `json:"type"`
+	Target   string   `json:"target"`
+	Path     string   `json:"path,omitempty"`
+	Projects []string `json:"projects,omitempty"`
+	Command  []string `json:"command,omitempty"`
+	Where    []string `json:"where,omitempty"`
+}
+
+func (r RunRequest) validate() ([]pkg.ClonePredicate, error) {
+	switch r.Type {
+	case RunTypeClone, RunTypeSync, RunTypeExec:
+	default:
+		return nil, fmt.Errorf("invalid run type '%s', expected clone, sync or exec", r.Type)
+	}
+
+	if r.Target == "" {
+		return nil, errors.New("a target is required")
+	}
+
+	return pkg.ParseClonePredicates(r.Where)
+}
+
+// ProjectResult is the outcome of a run for a project. Status is one of the wildfire.ProjectStatus constants.
+type ProjectResult struct {
+	Project string `json:"project"`
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	Output  string `json:"output,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Run is the state of a run. Projects are in the order in which they completed.
+type Run struct {
+	ID string `json:"id"`
+	RunRequest
+	Status   RunStatus       `json:"status"`
+	Error    string          `json:"error,omitempty"`
+	Started  time.Time       `json:"started"`
+	Finished *time.Time      `json:"finished,omitempty"`
+	Projects []ProjectResult `json:"projects"`
+}
+
+// run tracks a run while it is executed. changed is closed and replaced whenever the run changes, so watchers can
+// wait for the next change.
+type run struct {
+	state   Run
+	changed chan struct{}
+	cancel  context.CancelFunc
+	mutex   sync.Mutex
+}
+
+func newRun(request RunRequest, cancel context.CancelFunc) (*run, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &run{
+		state: Run{
+			ID:         hex.EncodeToString(id),
+			RunRequest: request,
+			Status:     RunStatusRunning,
+			Started:    time.Now(),
+			Projects:   []ProjectResult{},
+		},
+		changed: make(chan struct{}),
+		cancel:  cancel,
+	}, nil
+}
+
+// Snapshot returns a copy of the state of the run.
+func (r *run) Snapshot() Run {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.snapshot()
+}
+
+func (r *run) snapshot() Run {
+	res := r.state
+	res.Projects = append([]ProjectResult{}, r.state.Projects...)
+
+	return res
+}
+
+// Next returns the project results completed after the first from results, a snapshot of the run, and a channel
+// closed on the next change of the run.
+func (r *run) Next(from int) ([]ProjectResult, Run, <-chan struct{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var results []ProjectResult
+	if from < len(r.state.Projects) {
+		results = append(results, r.state.Projects[from:]...)
+	}
+
+	return results, r.snapshot(), r.changed
+}
+
+func (r *run) addResult(event wildfire.ProjectEvent) {
+	result := ProjectResult{Project: event.Project, Path: event.Path, Status: event.Status, Output: event.Output}
+	if event.Err != nil {
+		result.Error = event.Err.Error()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.state.Projects = append(r.state.Projects, result)
+	r.notify()
+}
+
+func (r *run) finish(ctx context.Context, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	finished := time.Now()
+	r.state.Finished = &finished
+	r.state.Status = RunStatusSucceeded
+
+	for _, result := range r.state.Projects {
+		if result.Status == wildfire.ProjectStatusFailed {
+			r.state.Status = RunStatusFailed
+		}
+	}
+
+	if err != nil {
+		r.state.Status = RunStatusFailed
+		r.state.Error = err.Error()
+	}
+	if ctx.Err() != nil {
+		r.state.Status = RunStatusCancelled
+	}
+
+	r.notify()
+}
+
+func (r *run) done() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.state.Finished != nil
+}
+
+func (r *run) notify() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// execute runs the request with the client and records the progress of every project. The run is recorded in the
+// history unless it is nil.
+func (r *run) execute(
+	ctx context.Context,
+	client *wildfire.Client,
+	predicates []pkg.ClonePredicate,
+	history *pkg.HistoryStore,
+) {
+	request := r.state.RunRequest
+	entry := newHistoryEntry(ctx, client, request)
+	ctx = wildfire.WithProgress(ctx, func(event wildfire.ProjectEvent) {
+		message := ""
+		if event.Status == wildfire.ProjectStatusSkipped {
+			message = event.Output
+		}
+
+		entry.AddProject(event.Clone, event.HistoryStatus(), message, event.Err)
+		r.addResult(event)
+	})
+
+	var err error
+	switch request.Type {
+	case RunTypeClone:
+		_, err = client.Clone(ctx, request.Target, wildfire.CloneOptions{Path: request.Path, Projects: request.Projects})
+	case RunTypeSync:
+		_, err = client.Sync(ctx, request.Target)
+	case RunTypeExec:
+		command, args := "", []string(nil)
+		if len(request.Command) > 0 {
+			command, args = request.Command[0], request.Command[1:]
+		}
+
+		_, err = client.Exec(ctx, request.Target, predicates, command, args...)
+	}
+
+	if history != nil && len(entry.Projects) != 0 {
+		// The outcome of the run is reported by the API, a run which can not be recorded does not fail.
+		_ = history.Append(entry)
+	}
+
+	r.finish(ctx, err)
+}
+
+// newHistoryEntry creates the entry recording the run. Only exec runs can be run again from the command line, the
+// arguments of the other runs are not recorded.
+func newHistoryEntry(ctx context.Context, client *wildfire.Client, request RunRequest) *pkg.HistoryEntry {
+	entry := pkg.NewHistoryEntry(string(request.Type), request.Target)
+	entry.Command = strings.Join(request.Command, " ")
+	entry.ConfigFile = client.ConfigPath()
+	if dir, err := os.Getwd(); err == nil {
+		entry.Dir = dir
+	}
+
+	switch request.Type {
+	case RunTypeClone:
+		entry.Group = request.Target
+	case RunTypeExec:
+		entry.Args = []string{"exec", request.Target}
+		for _, predicate := range request.Where {
+			entry.Args = append(entry.Args, "--where", predicate)
+		}
+		if len(request.Command) != 0 {
+			entry.Args = append(append(entry.Args, "--"), request.Command...)
+		}
+	}
+
+	if entry.Group == "" {
+		if workspace, err := client.GetWorkspace(ctx, request.Target); err == nil {
+			entry.Group = workspace.Group
+		}
+	}
+
+	return entry
+}
@@ -0,0 +1,383 @@
+// Package api_server serves the projects, groups and runs of a wildfire configuration over HTTP as JSON.
+package api_server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+// Server handles the API requests. Runs are executed in the background and kept in memory until the server is
+// closed. When the token is not empty every request has to provide it as a bearer token in the Authorization
+// header, or in the access_token query parameter for clients which can not set headers such as EventSource.
+//
+// So that web pages can not use the API from the browser of the user, requests are only served when their Host and
+// Origin headers, if any, are loopback names or addresses, or hosts allowed with AllowHosts, and POST and DELETE
+// requests have to be sent with the application/json content type.
+//
+//	GET    /api/projects             projects, filtered by the type, label, name and group query parameters
+//	GET    /api/projects/<name>      project
+//	GET    /api/groups               groups
+//	GET    /api/groups/<name>        group
+//	GET    /api/runs                 runs, in the order they were started
+//	POST   /api/runs                 start the run described by a RunRequest
+//	GET    /api/runs/<id>            run
+//	DELETE /api/runs/<id>            cancel the run
+//	GET    /api/runs/<id>/events     server-sent "project" events, then a "done" event with the run
+type Server struct {
+	client  *wildfire.Client
+	token   string
+	hosts   map[string]bool
+	history *pkg.HistoryStore
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	runs   map[string]*run
+	order  []string
+	wg     sync.WaitGroup
+	mutex  sync.Mutex
+}
+
+// GroupResponse is a group with its name.
+type GroupResponse struct {
+	Name string `json:"name"`
+	pkg.GroupConfig
+}
+
+func NewServer(client *wildfire.Client, token string) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{client: client, token: token, hosts: map[string]bool{}, ctx: ctx, cancel: cancel, runs: map[string]*run{}}
+}
+
+// AllowHosts allows the requests whose Host or Origin is one of the hosts, in addition to the loopback names and
+// addresses.
+func (s *Server) AllowHosts(hosts ...string) {
+	for _, host := range hosts {
+		s.hosts[strings.ToLower(host)] = true
+	}
+}
+
+// RecordRuns records the outcome of every run in the history store once it completed.
+func (s *Server) RecordRuns(history *pkg.HistoryStore) {
+	s.history = history
+}
+
+// Close cancels the runs in progress and waits until they are done.
+func (s *Server) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := s.checkOrigin(r); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	if r.Method == http.MethodPost || r.Method == http.MethodDelete {
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+			writeError(w, http.StatusUnsupportedMediaType, errors.New("requests have to be sent as application/json"))
+			return
+		}
+	}
+
+	if s.authorized(r) == false {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("a valid token is required"))
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) < 2 || path[0] != "api" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("'%s' not found", r.URL.Path))
+		return
+	}
+
+	switch {
+	case path[1] == "projects" && len(path) == 2 && r.Method == http.MethodGet:
+		s.listProjects(w, r)
+	case path[1] == "projects" && len(path) == 3 && r.Method == http.MethodGet:
+		s.getProject(w, r, path[2])
+	case path[1] == "groups" && len(path) == 2 && r.Method == http.MethodGet:
+		s.listGroups(w, r)
+	case path[1] == "groups" && len(path) == 3 && r.Method == http.MethodGet:
+		s.getGroup(w, r, path[2])
+	case path[1] == "runs" && len(path) == 2 && r.Method == http.MethodGet:
+		s.listRuns(w)
+	case path[1] == "runs" && len(path) == 2 && r.Method == http.MethodPost:
+		s.startRun(w, r)
+	case path[1] == "runs" && len(path) == 3 && r.Method == http.MethodGet:
+		s.getRun(w, path[2])
+	case path[1] == "runs" && len(path) == 3 && r.Method == http.MethodDelete:
+		s.cancelRun(w, path[2])
+	case path[1] == "runs" && len(path) == 4 && path[3] == "events" && r.Method == http.MethodGet:
+		s.streamRun(w, r, path[2])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s '%s' not found", r.Method, r.URL.Path))
+	}
+}
+
+// checkOrigin returns an error when the Host or Origin of the request is not allowed, which prevents DNS rebinding
+// and requests sent by web pages from other origins.
+func (s *Server) checkOrigin(r *http.Request) error {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(r.Host); err == nil {
+		host = h
+	}
+	if s.allowedHost(host) == false {
+		return fmt.Errorf("host '%s' is not allowed", r.Host)
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" {
+		originURL, err := url.Parse(origin)
+		if err != nil || s.allowedHost(originURL.Hostname()) == false {
+			return fmt.Errorf("origin '%s' is not allowed", origin)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) allowedHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+	if host == "localhost" || s.hosts[host] {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+
+	token := r.URL.Query().Get("access_token")
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		token = strings.TrimPrefix(header, "Bearer ")
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := pkg.ProjectFilter{Labels: query["label"], NamePattern: query.Get("name"), Groups: query["group"]}
+	for _, projectType := range query["type"] {
+		filter.Types = append(filter.Types, pkg.ProjectType(projectType))
+	}
+
+	projects, err := s.client.ListProjects(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request, name string) {
+	project, err := s.client.GetProject(r.Context(), name)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, project)
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	names, err := s.client.ListGroups(r.Context())
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	groups := []GroupResponse{}
+	for _, name := range names {
+		group, err := s.client.GetGroup(r.Context(), name)
+		if err != nil {
+			writeClientError(w, err)
+			return
+		}
+
+		groups = append(groups, GroupResponse{Name: name, GroupConfig: group})
+	}
+
+	writeJSON(w, http.StatusOK, groups)
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request, name string) {
+	group, err := s.client.GetGroup(r.Context(), name)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, GroupResponse{Name: name, GroupConfig: group})
+}
+
+func (s *Server) listRuns(w http.ResponseWriter) {
+	s.mutex.Lock()
+	runs := []Run{}
+	for _, id := range s.order {
+		runs = append(runs, s.runs[id].Snapshot())
+	}
+	s.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, runs)
+}
+
+func (s *Server) startRun(w http.ResponseWriter, r *http.Request) {
+	var request RunRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run request: %s", err))
+		return
+	}
+
+	predicates, err := request.validate()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	run, err := newRun(request, cancel)
+	if err != nil {
+		cancel()
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.mutex.Lock()
+	s.runs[run.state.ID] = run
+	s.order = append(s.order, run.state.ID)
+	s.mutex.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+
+		run.execute(ctx, s.client, predicates, s.history)
+	}()
+
+	w.Header().Set("Location", "/api/runs/"+run.state.ID)
+	writeJSON(w, http.StatusAccepted, run.Snapshot())
+}
+
+func (s *Server) getRun(w http.ResponseWriter, id string) {
+	run := s.findRun(w, id)
+	if run == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run.Snapshot())
+}
+
+func (s *Server) cancelRun(w http.ResponseWriter, id string) {
+	run := s.findRun(w, id)
+	if run == nil {
+		return
+	}
+
+	if run.done() {
+		writeError(w, http.StatusConflict, fmt.Errorf("run '%s' is not running", id))
+		return
+	}
+
+	run.cancel()
+	writeJSON(w, http.StatusAccepted, run.Snapshot())
+}
+
+// streamRun sends the results of the projects as server-sent events as soon as they complete. Results completed
+// before the request are sent first, so clients do not miss any of them.
+func (s *Server) streamRun(w http.ResponseWriter, r *http.Request, id string) {
+	run := s.findRun(w, id)
+	if run == nil {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	sent := 0
+	for {
+		results, state, changed := run.Next(sent)
+		for _, result := range results {
+			writeEvent(w, "project", result)
+		}
+		sent += len(results)
+
+		if state.Finished != nil {
+			writeEvent(w, "done", state)
+			flusher.Flush()
+			return
+		}
+
+		flusher.Flush()
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) findRun(w http.ResponseWriter, id string) *run {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	run, ok := s.runs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run '%s' does not exist", id))
+	}
+
+	return run
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeClientError(w http.ResponseWriter, err error) {
+	if errors.Is(err, wildfire.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+	} else {
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event string, value interface{}) {
+	data, _ := json.Marshal(value)
+
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
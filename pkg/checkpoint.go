@@ -0,0 +1,327 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CheckpointDir is the directory of a workspace containing the checkpoints of the runs in progress.
+const CheckpointDir = ".wildfire/runs"
+
+// CheckpointStep is a step of a run, such as cloning the projects or running a command in them. Completed contains
+// the outcome of the projects which completed the step. Projects which failed are retried when the run is resumed,
+// so they are not completed.
+type CheckpointStep struct {
+	Name      string                    `json:"name"`
+	Command   string                    `json:"command,omitempty"`
+	Projects  []string                  `json:"projects"`
+	Completed map[string]HistoryProject `json:"completed"`
+}
+
+// Checkpoint records the progress of a run in the workspace it runs in, so it can be resumed with the arguments it
+// has been run with when it is interrupted. The checkpoint is removed once the run finishes. A nil checkpoint records
+// nothing.
+type Checkpoint struct {
+	ID         string            `json:"id"`
+	Time       time.Time         `json:"time"`
+	Kind       string            `json:"kind"`
+	Target     string            `json:"target"`
+	Dir        string            `json:"dir"`
+	ConfigFile string            `json:"config_file,omitempty"`
+	Args       []string          `json:"args"`
+	Steps      []*CheckpointStep `json:"steps"`
+
+	path  string
+	next  int
+	mutex sync.Mutex
+}
+
+type checkpointKey struct{}
+
+// WithCheckpoint returns a context in which the commands resume the run of the checkpoint.
+func WithCheckpoint(ctx context.Context, checkpoint *Checkpoint) context.Context {
+	return context.WithValue(ctx, checkpointKey{}, checkpoint)
+}
+
+// GetCheckpoint returns the checkpoint of the run resumed in the context, nil when no run is resumed.
+func GetCheckpoint(ctx context.Context) *Checkpoint {
+	if ctx != nil {
+		if checkpoint, ok := ctx.Value(checkpointKey{}).(*Checkpoint); ok {
+			return checkpoint
+		}
+	}
+
+	return nil
+}
+
+// StartCheckpoint returns the checkpoint of the run of the entry, stored in the workspace directory. When the
+// context resumes a run its checkpoint is returned instead and the entry takes its id. Runs can only be resumed with
+// the arguments recorded by the history, so there is no checkpoint when the context has no history nor for dry runs.
+func StartCheckpoint(ctx context.Context, entry *HistoryEntry, workspacePath string) *Checkpoint {
+	if IsDryRun(ctx) {
+		return nil
+	}
+
+	if checkpoint := GetCheckpoint(ctx); checkpoint != nil {
+		entry.ID = checkpoint.ID
+
+		return checkpoint
+	}
+
+	if ctx == nil || workspacePath == "" {
+		return nil
+	}
+
+	recorder, ok := ctx.Value(historyKey{}).(historyRecorder)
+	if !ok {
+		return nil
+	}
+
+	checkpoint := &Checkpoint{
+		ID:         entry.ID,
+		Time:       entry.Time,
+		Kind:       entry.Kind,
+		Target:     entry.Target,
+		ConfigFile: GetConfigStore(ctx).Path(),
+		Args:       recorder.args,
+		Steps:      []*CheckpointStep{},
+		path:       filepath.Join(workspacePath, filepath.FromSlash(CheckpointDir), entry.ID+".json"),
+	}
+	if dir, err := os.Getwd(); err == nil {
+		checkpoint.Dir = dir
+	}
+
+	return checkpoint
+}
+
+// Path returns the file of the checkpoint.
+func (c *Checkpoint) Path() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.path
+}
+
+// Step starts the next step of the run. When a run is resumed the steps it had started are returned in order, as
+// long as they have the same name and command, otherwise the step is added with the projects.
+func (c *Checkpoint) Step(name string, command string, projects []string) *CheckpointStep {
+	if c == nil {
+		return &CheckpointStep{Name: name, Command: command, Projects: projects, Completed: map[string]HistoryProject{}}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.next < len(c.Steps) && c.Steps[c.next].Name == name && c.Steps[c.next].Command == command {
+		c.next++
+
+		return c.Steps[c.next-1]
+	}
+
+	step := &CheckpointStep{Name: name, Command: command, Projects: projects, Completed: map[string]HistoryProject{}}
+	c.Steps = append(c.Steps[:c.next], step)
+	c.next = len(c.Steps)
+	_ = c.save()
+
+	return step
+}
+
+// Remaining returns the steps the resumed run had started after the current step, in order, and marks them as
+// started.
+func (c *Checkpoint) Remaining() []*CheckpointStep {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	res := c.Steps[c.next:]
+	c.next = len(c.Steps)
+
+	return res
+}
+
+// Complete records the outcome of the step for the project. Projects which failed are not completed.
+func (c *Checkpoint) Complete(step *CheckpointStep, project HistoryProject) error {
+	if c == nil || project.Status == HistoryStatusFailed {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	step.Completed[project.Project] = project
+
+	return c.save()
+}
+
+// Completed returns the outcome of the projects which completed the step, in the order of the projects.
+func (c *Checkpoint) Completed(step *CheckpointStep) []HistoryProject {
+	if c != nil {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+	}
+
+	var res []HistoryProject
+	for _, project := range step.Projects {
+		if completed, ok := step.Completed[project]; ok {
+			res = append(res, completed)
+		}
+	}
+
+	return res
+}
+
+// Incomplete returns the projects which did not complete the step.
+func (c *Checkpoint) Incomplete(step *CheckpointStep) []string {
+	if c != nil {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+	}
+
+	res := []string{}
+	for _, project := range step.Projects {
+		if _, ok := step.Completed[project]; !ok {
+			res = append(res, project)
+		}
+	}
+
+	return res
+}
+
+// Progress returns the number of projects which completed every step and the number of projects of the run.
+func (c *Checkpoint) Progress() (int, int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	projects := map[string]bool{}
+	for _, step := range c.Steps {
+		for _, project := range step.Projects {
+			if _, ok := projects[project]; !ok {
+				projects[project] = true
+			}
+			if _, ok := step.Completed[project]; !ok {
+				projects[project] = false
+			}
+		}
+	}
+
+	completed := 0
+	for _, done := range projects {
+		if done {
+			completed++
+		}
+	}
+
+	return completed, len(projects)
+}
+
+// Finish removes the checkpoint once the run finished.
+func (c *Checkpoint) Finish() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Checkpoint) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, c.path)
+}
+
+// LoadCheckpoint reads the checkpoint file.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, err
+	}
+	checkpoint.path = path
+
+	return checkpoint, nil
+}
+
+// FindCheckpoints returns the checkpoints of the runs in progress in the workspace directories, oldest first.
+// Files which can not be read are ignored.
+func FindCheckpoints(dirs ...string) []*Checkpoint {
+	var res []*Checkpoint
+	found := map[string]bool{}
+
+	for _, dir := range dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, filepath.FromSlash(CheckpointDir), "*.json"))
+		for _, file := range files {
+			if found[file] {
+				continue
+			}
+			found[file] = true
+
+			if checkpoint, err := LoadCheckpoint(file); err == nil {
+				res = append(res, checkpoint)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Time.Before(res[j].Time)
+	})
+
+	return res
+}
+
+// FindCheckpoint returns the checkpoint whose ID is, or uniquely starts with, id.
+func FindCheckpoint(checkpoints []*Checkpoint, id string) (*Checkpoint, error) {
+	var res *Checkpoint
+	for _, checkpoint := range checkpoints {
+		if checkpoint.ID == id {
+			return checkpoint, nil
+		}
+
+		if id != "" && strings.HasPrefix(checkpoint.ID, id) {
+			if res != nil {
+				return nil, fmt.Errorf("run id '%s' is ambiguous", id)
+			}
+			res = checkpoint
+		}
+	}
+
+	if res == nil {
+		return nil, fmt.Errorf("no interrupted run '%s' has been found", id)
+	}
+
+	return res, nil
+}
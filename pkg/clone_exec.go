@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// Clone is a project cloned in a workspace.
+type Clone struct {
+	Project string
+	Path    string
+}
+
+// ExecResult is the outcome of running a command in a clone. Matched is set when the clone matched the predicates.
+// Skipped is set when it did not, in which case SkipReason describes the predicate which did not match and the
+// command was not run.
+type ExecResult struct {
+	Clone
+	Matched    bool
+	Skipped    bool
+	SkipReason string
+	Output     string
+	Err        error
+}
+
+// FilterClones evaluates the predicates against every clone in parallel. The returned results are in the order of
+// the clones. Clones which do not match are marked as skipped, clones which do not exist are reported as failed.
+func FilterClones(clones []Clone, predicates []ClonePredicate) []ExecResult {
+	return forEachClone(clones, func(result *ExecResult) {
+		filterClone(result, predicates)
+	})
+}
+
+// ExecInClones runs the command in every clone which matches the predicates, in parallel. The returned results are
+// in the order of the clones.
+func ExecInClones(clones []Clone, predicates []ClonePredicate, name string, args ...string) []ExecResult {
+	return ExecInClonesContext(context.Background(), clones, predicates, name, args...)
+}
+
+// ExecInClonesContext is ExecInClones with a context. The commands are killed when the context is done.
+func ExecInClonesContext(
+	ctx context.Context,
+	clones []Clone,
+	predicates []ClonePredicate,
+	name string,
+	args ...string,
+) []ExecResult {
+	return forEachClone(clones, func(result *ExecResult) {
+		if filterClone(result, predicates) == false {
+			return
+		}
+
+		var output bytes.Buffer
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = result.Path
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+
+		result.Err = cmd.Run()
+		result.Output = output.String()
+	})
+}
+
+func forEachClone(clones []Clone, fn func(result *ExecResult)) []ExecResult {
+	res := make([]ExecResult, len(clones))
+
+	var wg sync.WaitGroup
+	wg.Add(len(clones))
+
+	for i, clone := range clones {
+		res[i].Clone = clone
+
+		go func(result *ExecResult) {
+			defer wg.Done()
+			fn(result)
+		}(&res[i])
+	}
+
+	wg.Wait()
+
+	return res
+}
+
+func filterClone(result *ExecResult, predicates []ClonePredicate) bool {
+	if _, err := os.Stat(result.Path); err != nil {
+		result.Err = fmt.Errorf("project '%s' has not been cloned to '%s'", result.Project, result.Path)
+		return false
+	}
+
+	predicate, err := MatchClone(result.Path, predicates)
+	if err != nil {
+		result.Err = err
+		return false
+	}
+
+	if predicate != nil {
+		result.Skipped = true
+		result.SkipReason = fmt.Sprintf("'%s' does not match", predicate)
+		return false
+	}
+
+	result.Matched = true
+
+	return true
+}
@@ -0,0 +1,230 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type ClonePredicateKind string
+
+const (
+	ClonePredicateFileExists ClonePredicateKind = "file-exists"
+	ClonePredicateContains   ClonePredicateKind = "contains"
+	ClonePredicateGlob       ClonePredicateKind = "glob"
+	ClonePredicateCmd        ClonePredicateKind = "cmd"
+)
+
+func (k ClonePredicateKind) GetAvailableKinds() []ClonePredicateKind {
+	return []ClonePredicateKind{
+		ClonePredicateFileExists,
+		ClonePredicateContains,
+		ClonePredicateGlob,
+		ClonePredicateCmd,
+	}
+}
+
+// ClonePredicate is a condition evaluated against the clone of a project, e.g. to only run a command in the clones
+// which use a particular dependency.
+//
+//	file-exists:<path>         the file or directory exists in the clone
+//	contains:<path>:<text>     the file exists in the clone and contains the text
+//	glob:<pattern>             a file of the clone matches the pattern, ** matches any number of directories
+//	cmd:<command>              the command exits with status 0 when run in the clone
+type ClonePredicate struct {
+	Kind     ClonePredicateKind
+	Path     string
+	Text     string
+	Pattern  string
+	Command  string
+	raw      string
+	globExpr *regexp.Regexp
+}
+
+// ParseClonePredicate parses a predicate written as <kind>:<argument>.
+func ParseClonePredicate(predicate string) (ClonePredicate, error) {
+	parts := strings.SplitN(predicate, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return ClonePredicate{}, fmt.Errorf("invalid predicate '%s': expected <kind>:<argument>", predicate)
+	}
+
+	res := ClonePredicate{Kind: ClonePredicateKind(parts[0]), raw: predicate}
+	argument := parts[1]
+
+	switch res.Kind {
+	case ClonePredicateFileExists:
+		res.Path = argument
+	case ClonePredicateContains:
+		contains := strings.SplitN(argument, ":", 2)
+		if len(contains) != 2 || contains[0] == "" || contains[1] == "" {
+			return ClonePredicate{}, fmt.Errorf("invalid predicate '%s': expected contains:<path>:<text>", predicate)
+		}
+		res.Path, res.Text = contains[0], contains[1]
+	case ClonePredicateGlob:
+		expr, err := compileGlob(argument)
+		if err != nil {
+			return ClonePredicate{}, fmt.Errorf("invalid predicate '%s': %s", predicate, err)
+		}
+		res.Pattern, res.globExpr = argument, expr
+	case ClonePredicateCmd:
+		if _, _, err := ParseCommand(argument); err != nil {
+			return ClonePredicate{}, fmt.Errorf("invalid predicate '%s': %s", predicate, err)
+		}
+		res.Command = argument
+	default:
+		var kinds []string
+		for _, kind := range res.Kind.GetAvailableKinds() {
+			kinds = append(kinds, string(kind))
+		}
+
+		return ClonePredicate{}, fmt.Errorf(
+			"invalid predicate '%s': unknown kind '%s', available kinds are %s",
+			predicate,
+			res.Kind,
+			strings.Join(kinds, ", "),
+		)
+	}
+
+	return res, nil
+}
+
+// ParseClonePredicates parses every predicate, stopping at the first invalid one.
+func ParseClonePredicates(predicates []string) ([]ClonePredicate, error) {
+	var res []ClonePredicate
+
+	for _, predicate := range predicates {
+		p, err := ParseClonePredicate(predicate)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, p)
+	}
+
+	return res, nil
+}
+
+func (p ClonePredicate) String() string {
+	return p.raw
+}
+
+// Match evaluates the predicate against the clone located in dir. An error is returned if the predicate can not be
+// evaluated, e.g. when the command of a cmd predicate can not be started.
+func (p ClonePredicate) Match(dir string) (bool, error) {
+	switch p.Kind {
+	case ClonePredicateFileExists:
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(p.Path)))
+
+		return err == nil, nil
+	case ClonePredicateContains:
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(p.Path)))
+		if err != nil {
+			return false, nil
+		}
+
+		return strings.Contains(string(data), p.Text), nil
+	case ClonePredicateGlob:
+		return p.matchGlob(dir)
+	case ClonePredicateCmd:
+		name, args, _ := ParseCommand(p.Command)
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate predicate '%s': %s", p.raw, err)
+		}
+
+		return true, nil
+	}
+
+	return false, fmt.Errorf("invalid predicate '%s'", p.raw)
+}
+
+// MatchClone returns the first predicate which does not match the clone located in dir, or nil if all of them match.
+func MatchClone(dir string, predicates []ClonePredicate) (*ClonePredicate, error) {
+	for _, predicate := range predicates {
+		matched, err := predicate.Match(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if matched == false {
+			predicate := predicate
+			return &predicate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+var errGlobMatched = errors.New("glob matched")
+
+func (p ClonePredicate) matchGlob(dir string) (bool, error) {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		if p.globExpr.MatchString(filepath.ToSlash(rel)) {
+			return errGlobMatched
+		}
+
+		return nil
+	})
+
+	if err == errGlobMatched {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate predicate '%s': %s", p.raw, err)
+	}
+
+	return false, nil
+}
+
+// compileGlob converts a glob pattern into a regular expression. * and ? do not match the path separator while **
+// matches any number of directories.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	expr.WriteString("$")
+
+	return regexp.Compile(expr.String())
+}
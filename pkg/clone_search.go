@@ -0,0 +1,208 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// binaryCheckSize is the number of bytes inspected to decide whether a file is binary, the same as git does.
+const binaryCheckSize = 8000
+
+// SearchMatch is a line matching the searched pattern. File is relative to the root of the clone and uses forward
+// slashes.
+type SearchMatch struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// SearchResult holds the matches found in the clone of a project.
+type SearchResult struct {
+	Clone
+	Matches []SearchMatch
+	Err     error
+}
+
+// SearchClones searches the clones in parallel for lines matching the pattern. The returned results are in the order
+// of the clones. When ref is empty the working tree of every clone is searched, skipping the files ignored by
+// .gitignore. Otherwise the tree of the revision, e.g. a branch, a tag or a commit hash, is read from the repository
+// of the clone without checking it out.
+func SearchClones(clones []Clone, pattern *regexp.Regexp, ref string) []SearchResult {
+	res := make([]SearchResult, len(clones))
+
+	var wg sync.WaitGroup
+	wg.Add(len(clones))
+
+	for i, clone := range clones {
+		res[i].Clone = clone
+
+		go func(result *SearchResult) {
+			defer wg.Done()
+
+			if ref == "" {
+				result.Matches, result.Err = searchWorkingTree(result.Path, pattern)
+			} else {
+				result.Matches, result.Err = searchRevision(result.Path, pattern, ref)
+			}
+
+			if result.Err != nil {
+				result.Err = fmt.Errorf("failed to search project '%s': %s", result.Project, result.Err)
+			}
+		}(&res[i])
+	}
+
+	wg.Wait()
+
+	return res
+}
+
+func searchWorkingTree(root string, pattern *regexp.Regexp) ([]SearchMatch, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("clone '%s' does not exist", root)
+	}
+
+	var matches []SearchMatch
+	ignored := readIgnorePatterns(filepath.Join(root, ".git", "info", "exclude"), nil)
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		var parts []string
+		if rel != "." {
+			parts = strings.Split(filepath.ToSlash(rel), "/")
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if len(parts) != 0 && gitignore.NewMatcher(ignored).Match(parts, true) {
+				return filepath.SkipDir
+			}
+
+			ignored = readIgnorePatterns(filepath.Join(path, ".gitignore"), parts, ignored...)
+
+			return nil
+		}
+
+		if info.Mode().IsRegular() == false || gitignore.NewMatcher(ignored).Match(parts, false) {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		matches = append(matches, searchContent(filepath.ToSlash(rel), data, pattern)...)
+
+		return nil
+	})
+
+	return matches, err
+}
+
+// readIgnorePatterns appends the patterns of the ignore file, which applies to the directory at domain, to patterns.
+// Patterns of sibling directories are harmless since their domain never matches.
+func readIgnorePatterns(path string, domain []string, patterns ...gitignore.Pattern) []gitignore.Pattern {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return patterns
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+
+	return patterns
+}
+
+func searchRevision(root string, pattern *regexp.Regexp, ref string) ([]SearchMatch, error) {
+	repo, err := git.PlainOpen(root)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve '%s': %s", ref, err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []SearchMatch
+	err = tree.Files().ForEach(func(file *object.File) error {
+		contents, err := file.Contents()
+		if err != nil {
+			return err
+		}
+
+		matches = append(matches, searchContent(file.Name, []byte(contents), pattern)...)
+
+		return nil
+	})
+
+	return matches, err
+}
+
+// searchContent returns the lines of the file matching the pattern. Binary files are not searched.
+func searchContent(file string, data []byte, pattern *regexp.Regexp) []SearchMatch {
+	if isBinary(data) {
+		return nil
+	}
+
+	var matches []SearchMatch
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if pattern.MatchString(text) {
+			matches = append(matches, SearchMatch{File: file, Line: line, Text: text})
+		}
+	}
+
+	return matches
+}
+
+// isBinary reports whether the data contains a NUL byte within its first bytes, as git does.
+func isBinary(data []byte) bool {
+	head := data
+	if len(head) > binaryCheckSize {
+		head = head[:binaryCheckSize]
+	}
+
+	return bytes.IndexByte(head, 0) != -1
+}
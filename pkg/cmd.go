@@ -6,27 +6,90 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// SkipValidationAnnotation marks commands which handle configuration issues themselves. ProjectFunc will not
+// report configuration issues for commands annotated with it.
+const SkipValidationAnnotation = "wildfire_skip_validation"
+
+// RecordRun records the run in the history of the command context, see RecordHistory. A run which can not be
+// recorded is reported as a warning. Dry runs are not recorded.
+func RecordRun(cmd *cobra.Command, entry *HistoryEntry) {
+	if IsDryRun(cmd.Context()) {
+		return
+	}
+
+	if err := RecordHistory(cmd.Context(), entry); err != nil {
+		emoji.Fprintf(cmd.ErrOrStderr(), ":warning: Failed to record the run in the history: %s\n", err)
+	}
+}
+
 type CMDFunc func(config *WildFireConfig, cmd *cobra.Command, args []string) (*WildFireConfig, bool, error)
 type CobraCMDFunc func(cmd *cobra.Command, args []string) error
 
+// ProjectFunc runs the function with the configuration of the store of the command context, see GetConfigStore. The
+// configuration is not locked while the function runs, the changes it made are saved with SaveConfigChanges if it
+// reports an update.
 func ProjectFunc(c CMDFunc) CobraCMDFunc {
 	return func(cmd *cobra.Command, args []string) error {
-		fmt.Println()
-		config, update, err := c(GetConfig(), cmd, args)
-		fmt.Println()
+		store := GetConfigStore(cmd.Context())
+
+		config, err := store.Load()
+		if err != nil {
+			return err
+		}
+		config.trackChanges()
+
+		_, skipValidation := cmd.Annotations[SkipValidationAnnotation]
+		if issues := config.Validate(); len(issues) != 0 && skipValidation == false {
+			for _, issue := range issues {
+				emoji.Fprintf(cmd.ErrOrStderr(), ":warning: Configuration issue: %s\n", issue)
+			}
+			fmt.Fprintln(cmd.ErrOrStderr(), "Run 'wildfire config doctor' for details.")
+		}
+
+		fmt.Fprintln(cmd.ErrOrStderr())
+		config, update, err := c(config, cmd, args)
+		fmt.Fprintln(cmd.ErrOrStderr())
 
 		if err != nil {
 			return err
 		}
 
 		if update {
-			cobra.CheckErr(config.SaveConfig())
-			emoji.Println(":cloud: Configuration has been updated.")
+			return SaveCommandConfig(cmd, config)
+		}
 
+		emoji.Fprintln(cmd.ErrOrStderr(), ":cloud: Dousing WildFire.")
+		return nil
+	}
+}
+
+// SaveCommandConfig saves the changes made to the configuration to the store of the command context, see
+// SaveConfigChanges. In a dry run the changes which would be saved are written to the command output as a diff
+// instead.
+func SaveCommandConfig(cmd *cobra.Command, config *WildFireConfig) error {
+	store := GetConfigStore(cmd.Context())
+
+	if IsDryRun(cmd.Context()) {
+		diff, err := ConfigDiff(store, config)
+		if err != nil {
+			return err
+		}
+
+		if diff == "" {
+			emoji.Fprintln(cmd.ErrOrStderr(), ":cloud: Configuration would not change.")
 			return nil
 		}
 
-		emoji.Println(":cloud: Dousing WildFire.")
+		emoji.Fprintln(cmd.ErrOrStderr(), ":memo: Configuration would be updated:")
+		fmt.Fprint(cmd.OutOrStdout(), diff)
+
 		return nil
 	}
+
+	if err := SaveConfigChanges(store, config); err != nil {
+		return err
+	}
+	emoji.Fprintln(cmd.ErrOrStderr(), ":cloud: Configuration has been updated.")
+
+	return nil
 }
@@ -0,0 +1,31 @@
+package pkg
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseCommand splits a command into the program and its arguments. Arguments containing spaces can be quoted.
+func ParseCommand(command string) (string, []string, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", nil, errors.New("no command has been provided")
+	}
+
+	r := csv.NewReader(strings.NewReader(strings.TrimSpace(command)))
+	r.Comma = ' '
+	parts, err := r.Read()
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid command '%s': %s", command, err)
+	}
+
+	var res []string
+	for _, part := range parts {
+		if part != "" {
+			res = append(res, part)
+		}
+	}
+
+	return res[0], res[1:], nil
+}
@@ -1,39 +1,87 @@
 package pkg
 
 import (
-	"github.com/spf13/viper"
+	"errors"
 )
 
 type WildFireConfig struct {
-	Projects map[string]*ProjectConfig `yaml:"projects"`
-	Groups map[string]*GroupConfig     `yaml:"groups"`
+	Version    int                         `yaml:"version,omitempty"`
+	Projects   map[string]*ProjectConfig   `yaml:"projects"`
+	Groups     map[string]*GroupConfig     `yaml:"groups"`
+	Workspaces map[string]*WorkspaceConfig `yaml:"workspaces"`
+	Includes   []IncludeConfig             `yaml:"includes,omitempty"`
+
+	// localPath is the local configuration file, lowerLayers are the layers beneath it and writeLayer the layer new
+	// entries are written to, see FileConfigStore.
+	localPath        string
+	lowerLayers      []ConfigLayer
+	writeLayer       string
+	sources          map[string]string
+	snapshots        map[string]string
+	includePaths     map[string]string
+	includeSnapshots map[string]string
+
+	substitutionIssues []ConfigIssue
+
+	// loadedEntries are the entries of the configuration when its changes started to be tracked, see trackChanges.
+	loadedEntries map[string]string
+}
+
+// LoadConfigFile returns the configuration of the file, including its includes. The configuration is saved to the
+// same file. A missing file is loaded as an empty configuration. Use a FileConfigStore to merge other configuration
+// layers beneath the file.
+func LoadConfigFile(path string) (*WildFireConfig, error) {
+	return NewFileConfigStore(path).Load()
 }
 
-func GetConfig() *WildFireConfig {
-	var config WildFireConfig
-	err := viper.Unmarshal(&config)
+// load merges the layers of the configuration, see layers.
+func (config *WildFireConfig) load() (*WildFireConfig, error) {
+	config.Version = ConfigVersion
+	config.Projects = make(map[string]*ProjectConfig)
+	config.Groups = make(map[string]*GroupConfig)
+	config.Workspaces = make(map[string]*WorkspaceConfig)
 
-	if err != nil {
-		return &WildFireConfig{
-			Projects: make(map[string]*ProjectConfig),
-			Groups: make(map[string]*GroupConfig),
+	for _, layer := range config.layers() {
+		layerConfig, err := loadConfigLayer(layer)
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	if len(config.Projects) == 0 {
-		config.Projects = make(map[string]*ProjectConfig)
-	}
+		if err := config.mergeIncludes(layer, layerConfig.Includes); err != nil {
+			return nil, err
+		}
 
-	if len(config.Groups) == 0 {
-		config.Groups = make(map[string]*GroupConfig)
+		config.mergeLayer(layer, layerConfig)
 	}
 
-	return &config
+	config.snapshotLayers()
+	config.snapshotIncludes()
+	config.checkSubstitutions()
+
+	return config, nil
 }
 
+// SaveConfig writes every entry of the configuration to the layer it was loaded from. New entries are written to
+// the write layer, see FileConfigStore.SetWriteLayer. An error is returned if the configuration was not loaded from
+// a file.
 func (config *WildFireConfig) SaveConfig() error {
-	viper.Set("projects", config.Projects)
-	viper.Set("groups", config.Groups)
+	if config.localPath == "" {
+		return errors.New("a configuration file is required")
+	}
+
+	if _, err := config.getConfigLayer(config.getWriteLayer()); err != nil {
+		return err
+	}
+
+	config.detachModifiedIncludes()
+
+	for _, layer := range config.layers() {
+		if err := config.saveConfigLayer(layer); err != nil {
+			return err
+		}
+	}
+
+	config.snapshotLayers()
 
-	return viper.WriteConfig()
+	return nil
 }
@@ -0,0 +1,199 @@
+package pkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigBackupCount is the number of previous configuration versions kept next to the configuration file.
+const ConfigBackupCount = 5
+
+const (
+	configLockTimeout      = 10 * time.Second
+	configLockPollInterval = 100 * time.Millisecond
+)
+
+// ConfigLock is an advisory lock held on a configuration file while it is read, modified and written.
+type ConfigLock struct {
+	path string
+}
+
+// LockConfig creates the lock file of the configuration. If another process holds the lock it waits until the lock
+// is released or the timeout expires.
+func LockConfig(configPath string) (*ConfigLock, error) {
+	lockPath := configPath + ".lock"
+	deadline := time.Now().Add(configLockTimeout)
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = fmt.Fprintf(file, "%d\n", os.Getpid())
+			_ = file.Close()
+
+			return &ConfigLock{path: lockPath}, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock configuration '%s': %s", configPath, err)
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf(
+				"configuration '%s' is locked by another process. Remove '%s' if no other wildfire process is running",
+				configPath,
+				lockPath,
+			)
+		}
+
+		time.Sleep(configLockPollInterval)
+	}
+}
+
+func (l *ConfigLock) Unlock() error {
+	return os.Remove(l.path)
+}
+
+// ConfigBackup is a previous version of the configuration file.
+type ConfigBackup struct {
+	Number  int
+	Path    string
+	ModTime time.Time
+}
+
+func configBackupPath(configPath string, number int) string {
+	return fmt.Sprintf("%s.bak.%d", configPath, number)
+}
+
+// GetConfigBackups returns the existing backups of the configuration file, newest first.
+func GetConfigBackups(configPath string) ([]ConfigBackup, error) {
+	matches, err := filepath.Glob(configPath + ".bak.*")
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []ConfigBackup
+	for _, match := range matches {
+		number, err := strconv.Atoi(strings.TrimPrefix(match, configPath+".bak."))
+		if err != nil {
+			continue
+		}
+
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, ConfigBackup{Number: number, Path: match, ModTime: info.ModTime()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Number < backups[j].Number
+	})
+
+	return backups, nil
+}
+
+// RestoreConfigBackup replaces the configuration file with the backup with the provided number.
+// The replaced configuration is kept as the newest backup.
+func RestoreConfigBackup(configPath string, number int) error {
+	data, err := ioutil.ReadFile(configBackupPath(configPath, number))
+	if err != nil {
+		return fmt.Errorf("failed to read configuration backup %d: %s", number, err)
+	}
+
+	return writeConfigFile(configPath, func(tmpPath string) error {
+		return ioutil.WriteFile(tmpPath, data, 0644)
+	})
+}
+
+// DiffConfigBackup returns the changes restoring the backup would make to the configuration file, see DiffLines.
+func DiffConfigBackup(configPath string, number int) (string, error) {
+	backup, err := ioutil.ReadFile(configBackupPath(configPath, number))
+	if err != nil {
+		return "", fmt.Errorf("failed to read configuration backup %d: %s", number, err)
+	}
+
+	current, err := ioutil.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	return DiffLines(string(current), string(backup)), nil
+}
+
+// writeConfigFile writes the configuration through a temporary file in the configuration directory which then
+// replaces the configuration file, so an interrupted write never leaves a partially written configuration behind.
+// The replaced configuration file is rotated into the backups.
+func writeConfigFile(configPath string, write func(tmpPath string) error) error {
+	dir, base := filepath.Split(configPath)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := ioutil.TempFile(dir, fmt.Sprintf(".%s.*%s", base, filepath.Ext(base)))
+	if err != nil {
+		return fmt.Errorf("failed to create temporary configuration file: %s", err)
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+
+	if err := write(tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := syncFile(tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if info, err := os.Stat(configPath); err == nil {
+		_ = os.Chmod(tmpPath, info.Mode())
+
+		if err := rotateConfigBackups(configPath); err != nil {
+			_ = os.Remove(tmpPath)
+			return err
+		}
+	}
+
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace configuration '%s': %s", configPath, err)
+	}
+
+	return nil
+}
+
+func rotateConfigBackups(configPath string) error {
+	_ = os.Remove(configBackupPath(configPath, ConfigBackupCount))
+
+	for number := ConfigBackupCount - 1; number > 0; number-- {
+		err := os.Rename(configBackupPath(configPath, number), configBackupPath(configPath, number+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate configuration backups: %s", err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to back up configuration: %s", err)
+	}
+
+	return ioutil.WriteFile(configBackupPath(configPath, 1), data, 0644)
+}
+
+func syncFile(path string) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return file.Sync()
+}
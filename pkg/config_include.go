@@ -0,0 +1,394 @@
+package pkg
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultIncludeFile is the file read from an included git repository when no file is configured.
+const DefaultIncludeFile = "registry.yaml"
+
+const includeFetchTimeout = 30 * time.Second
+
+// IncludeConfig references a YAML file from which project and group definitions are included.
+// Exactly one of Path, URL or Git has to be set. Included definitions are read-only, changing an included
+// project or group stores the changed copy in the configuration layer that is being written.
+type IncludeConfig struct {
+	Name string `yaml:"name,omitempty"`
+	Path string `yaml:"path,omitempty"`
+	URL  string `yaml:"url,omitempty"`
+	Git  string `yaml:"git,omitempty"`
+	Ref  string `yaml:"ref,omitempty"`
+	File string `yaml:"file,omitempty"`
+}
+
+var includeCacheDir string
+
+// SetIncludeCacheDir sets the directory in which remote includes are cached. By default includes are cached in
+// the wildfire directory of the user cache directory.
+func SetIncludeCacheDir(dir string) {
+	includeCacheDir = dir
+}
+
+func getIncludeCacheDir() (string, error) {
+	if includeCacheDir != "" {
+		return includeCacheDir, nil
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find include cache directory: %s", err)
+	}
+
+	return filepath.Join(cacheDir, "wildfire", "includes"), nil
+}
+
+func (i IncludeConfig) Validate() error {
+	set := 0
+	for _, location := range []string{i.Path, i.URL, i.Git} {
+		if location != "" {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return errors.New("include must have exactly one of 'path', 'url' or 'git'")
+	}
+
+	if i.Git != "" {
+		if _, err := i.gitFilePath(""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i IncludeConfig) location() string {
+	switch {
+	case i.Path != "":
+		return i.Path
+	case i.URL != "":
+		return i.URL
+	default:
+		return fmt.Sprintf("%s@%s:%s", i.Git, i.Ref, i.file())
+	}
+}
+
+func (i IncludeConfig) file() string {
+	if i.File == "" {
+		return DefaultIncludeFile
+	}
+
+	return i.File
+}
+
+// SourceName is the name under which included entries are reported as configuration sources.
+func (i IncludeConfig) SourceName() string {
+	if i.Name != "" {
+		return "include:" + i.Name
+	}
+
+	return "include:" + i.location()
+}
+
+func (i IncludeConfig) cachePath(cacheDir string) string {
+	sum := sha1.Sum([]byte(i.location()))
+
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:]))
+}
+
+// Resolve returns the local path of the included file. Remote includes are fetched into the include cache when
+// they are not cached yet or when refresh is set. Relative paths are resolved against baseDir.
+func (i IncludeConfig) Resolve(baseDir string, refresh bool) (string, error) {
+	if err := i.Validate(); err != nil {
+		return "", err
+	}
+
+	var path string
+	var err error
+
+	switch {
+	case i.Path != "":
+		path = i.Path
+		if filepath.IsAbs(path) == false {
+			path = filepath.Join(baseDir, path)
+		}
+	case i.URL != "":
+		path, err = i.resolveURL(refresh)
+	default:
+		path, err = i.resolveGit(refresh)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch include '%s': %s", i.location(), err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("included file '%s' could not be read: %s", i.location(), err)
+	}
+
+	return path, nil
+}
+
+func (i IncludeConfig) resolveURL(refresh bool) (string, error) {
+	cacheDir, err := getIncludeCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := i.cachePath(cacheDir) + ".yaml"
+	if _, err := os.Stat(path); err == nil && refresh == false {
+		return path, nil
+	}
+
+	client := &http.Client{Timeout: includeFetchTimeout}
+	response, err := client.Get(i.URL)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response status '%s'", response.Status)
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", err
+	}
+
+	return path, writeCacheFile(path, data)
+}
+
+// writeCacheFile replaces the cached file with the data. The data is written to a temporary file first, so the
+// cached file is never partially written.
+func writeCacheFile(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+
+	return err
+}
+
+func (i IncludeConfig) resolveGit(refresh bool) (string, error) {
+	cacheDir, err := getIncludeCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := i.cachePath(cacheDir)
+	path, err := i.gitFilePath(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		if refresh == false {
+			return path, nil
+		}
+
+		if err := os.RemoveAll(dir); err != nil {
+			return "", err
+		}
+	}
+
+	if err := i.cloneGit(dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// gitFilePath returns the path of the included file in the clone directory. An error is returned when the file is
+// not inside the clone.
+func (i IncludeConfig) gitFilePath(dir string) (string, error) {
+	file := filepath.Clean(filepath.FromSlash(i.file()))
+	if filepath.IsAbs(file) || file == ".." || strings.HasPrefix(file, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file '%s' is not inside the repository", i.file())
+	}
+
+	return filepath.Join(dir, file), nil
+}
+
+// cloneGit clones the repository of the include to dir. The ref is checked out as a branch, as a tag or, when
+// neither exists, as a commit.
+func (i IncludeConfig) cloneGit(dir string) error {
+	if i.Ref == "" {
+		_, err := git.PlainClone(dir, false, &git.CloneOptions{URL: i.Git, Depth: 1})
+		return err
+	}
+
+	for _, name := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(i.Ref), plumbing.NewTagReferenceName(i.Ref)} {
+		_, err := git.PlainClone(dir, false, &git.CloneOptions{
+			URL:           i.Git,
+			Depth:         1,
+			ReferenceName: name,
+			SingleBranch:  true,
+		})
+		if err == nil {
+			return nil
+		}
+
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+
+		if errors.Is(err, plumbing.ErrReferenceNotFound) == false && errors.Is(err, git.NoMatchingRefSpecError{}) == false {
+			return err
+		}
+	}
+
+	repository, err := git.PlainClone(dir, false, &git.CloneOptions{URL: i.Git, NoCheckout: true})
+	if err != nil {
+		return err
+	}
+
+	hash, err := repository.ResolveRevision(plumbing.Revision(i.Ref))
+	if err != nil {
+		return fmt.Errorf("ref '%s' is not a branch, tag or commit of '%s'", i.Ref, i.Git)
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return err
+	}
+
+	return worktree.Checkout(&git.CheckoutOptions{Hash: *hash})
+}
+
+// RefreshIncludes fetches every remote include of the configuration layers of the store again. With dryRun the
+// includes which would be fetched are returned without fetching them.
+func RefreshIncludes(store ConfigStore, dryRun bool) ([]IncludeConfig, error) {
+	var refreshed []IncludeConfig
+
+	for _, layer := range GetStoreConfigLayers(store) {
+		if layer.Path == "" {
+			continue
+		}
+
+		layerConfig, err := loadConfigLayer(layer)
+		if err != nil {
+			return refreshed, err
+		}
+
+		for _, include := range layerConfig.Includes {
+			if dryRun {
+				refreshed = append(refreshed, include)
+				continue
+			}
+
+			resolvedInclude, err := include.substitute(filepath.Dir(layer.Path))
+			if err != nil {
+				return refreshed, err
+			}
+
+			if _, err := resolvedInclude.Resolve(filepath.Dir(layer.Path), true); err != nil {
+				return refreshed, err
+			}
+
+			refreshed = append(refreshed, include)
+		}
+	}
+
+	return refreshed, nil
+}
+
+func (config *WildFireConfig) mergeIncludes(layer ConfigLayer, includes []IncludeConfig) error {
+	for _, include := range includes {
+		resolvedInclude, err := include.substitute(filepath.Dir(layer.Path))
+		if err != nil {
+			return err
+		}
+
+		path, err := resolvedInclude.Resolve(filepath.Dir(layer.Path), false)
+		if err != nil {
+			return err
+		}
+
+		includeLayer := ConfigLayer{Name: include.SourceName(), Path: path}
+		includeConfig, err := loadConfigLayer(includeLayer)
+		if err != nil {
+			return err
+		}
+
+		config.mergeLayer(includeLayer, includeConfig)
+		config.Includes = append(config.Includes, include)
+
+		if config.includePaths == nil {
+			config.includePaths = make(map[string]string)
+		}
+		config.includePaths[includeLayer.Name] = path
+	}
+
+	return nil
+}
+
+func isIncludeSource(source string) bool {
+	return strings.HasPrefix(source, "include:")
+}
+
+func (config *WildFireConfig) snapshotIncludes() {
+	config.includeSnapshots = make(map[string]string)
+
+	for key, source := range config.sources {
+		if isIncludeSource(source) {
+			parts := strings.SplitN(key, ".", 2)
+			config.includeSnapshots[key] = config.entrySnapshot(parts[0], parts[1])
+		}
+	}
+}
+
+// detachModifiedIncludes moves included entries which have been changed to the write layer, so the changed copy
+// is saved and overrides the included definition.
+func (config *WildFireConfig) detachModifiedIncludes() {
+	for key, snapshot := range config.includeSnapshots {
+		parts := strings.SplitN(key, ".", 2)
+		if config.entrySnapshot(parts[0], parts[1]) != snapshot {
+			config.sources[key] = config.getWriteLayer()
+			delete(config.includeSnapshots, key)
+		}
+	}
+}
+
+// SourcePath returns the file of a configuration source returned by WildFireConfig.Source.
+func (config *WildFireConfig) SourcePath(source string) string {
+	if path, ok := config.includePaths[source]; ok {
+		return path
+	}
+
+	for _, layer := range config.layers() {
+		if layer.Name == source {
+			return layer.Path
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,257 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+)
+
+const (
+	ConfigLayerGlobal = "global"
+	ConfigLayerTeam   = "team"
+	ConfigLayerLocal  = "local"
+)
+
+// TeamConfigFileName is the name of the team-shared configuration, usually checked into a repository.
+// It is looked up in the current directory and its parents.
+const TeamConfigFileName = ".wildfire.team.yaml"
+
+// ConfigLayer is a configuration file merged into the loaded configuration. Entries of later layers
+// override entries with the same name from earlier layers.
+type ConfigLayer struct {
+	Name string
+	Path string
+}
+
+// getConfigLayers returns the layers followed by the local configuration at path. Layers of the same file as the
+// local configuration are left out.
+func getConfigLayers(path string, lowerLayers []ConfigLayer) []ConfigLayer {
+	local := ConfigLayer{Name: ConfigLayerLocal, Path: path}
+	localPath, _ := filepath.Abs(local.Path)
+
+	var layers []ConfigLayer
+	for _, layer := range lowerLayers {
+		if layerPath, _ := filepath.Abs(layer.Path); layerPath == localPath {
+			continue
+		}
+
+		layers = append(layers, layer)
+	}
+
+	return append(layers, local)
+}
+
+// DiscoverConfigLayers returns the global configuration in the user configuration directory and the closest
+// team configuration found in dir or its parents. The WILDFIRE_TEAM_CONFIG environment variable overrides
+// the team configuration lookup.
+func DiscoverConfigLayers(dir string) []ConfigLayer {
+	var layers []ConfigLayer
+
+	if configDir, err := os.UserConfigDir(); err == nil {
+		layers = append(layers, ConfigLayer{
+			Name: ConfigLayerGlobal,
+			Path: filepath.Join(configDir, "wildfire", "config.yaml"),
+		})
+	}
+
+	if teamConfig := os.Getenv("WILDFIRE_TEAM_CONFIG"); teamConfig != "" {
+		return append(layers, ConfigLayer{Name: ConfigLayerTeam, Path: teamConfig})
+	}
+
+	for {
+		teamConfig := filepath.Join(dir, TeamConfigFileName)
+		if _, err := os.Stat(teamConfig); err == nil {
+			return append(layers, ConfigLayer{Name: ConfigLayerTeam, Path: teamConfig})
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return layers
+		}
+		dir = parent
+	}
+}
+
+// layers returns the configuration layers the configuration has been loaded from.
+func (config *WildFireConfig) layers() []ConfigLayer {
+	return getConfigLayers(config.localPath, config.lowerLayers)
+}
+
+// getWriteLayer returns the layer to which new entries are written.
+func (config *WildFireConfig) getWriteLayer() string {
+	if config.writeLayer == "" {
+		return ConfigLayerLocal
+	}
+
+	return config.writeLayer
+}
+
+func (config *WildFireConfig) getConfigLayer(name string) (ConfigLayer, error) {
+	for _, layer := range config.layers() {
+		if layer.Name == name {
+			return layer, nil
+		}
+	}
+
+	return ConfigLayer{}, fmt.Errorf("configuration layer '%s' is not available", name)
+}
+
+// loadConfigLayer reads the file of the layer. A missing file, or a layer without a file, is read as an empty
+// configuration.
+func loadConfigLayer(layer ConfigLayer) (*WildFireConfig, error) {
+	if layer.Path == "" {
+		return &WildFireConfig{}, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(layer.Path)
+
+	if err := v.ReadInConfig(); err != nil {
+		if os.IsNotExist(err) {
+			return &WildFireConfig{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to read %s configuration '%s': %s", layer.Name, layer.Path, err)
+	}
+
+	settings := v.AllSettings()
+	if _, err := MigrateSettings(settings); err != nil {
+		return nil, fmt.Errorf("failed to load configuration '%s': %s", layer.Path, err)
+	}
+
+	var layerConfig WildFireConfig
+	if err := decodeSettings(settings, &layerConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode configuration '%s': %s", layer.Path, err)
+	}
+
+	return &layerConfig, nil
+}
+
+// Source returns the name of the layer from which the entry of the section (projects, groups or workspaces) was
+// loaded. Entries which have not been saved yet belong to the write layer, see FileConfigStore.SetWriteLayer.
+func (config *WildFireConfig) Source(section string, name string) string {
+	if source, ok := config.sources[section+"."+name]; ok {
+		return source
+	}
+
+	return config.getWriteLayer()
+}
+
+// renameSource moves the source of an entry to its new name, so a renamed entry is written back to the layer it
+// was loaded from. Included entries can not be renamed as the included definition would be loaded again.
+func (config *WildFireConfig) renameSource(section string, oldName string, newName string) error {
+	source := config.Source(section, oldName)
+	if isIncludeSource(source) {
+		return fmt.Errorf("'%s' is included from '%s' and can not be renamed", oldName, config.SourcePath(source))
+	}
+
+	if config.sources == nil {
+		config.sources = make(map[string]string)
+	}
+
+	delete(config.sources, section+"."+oldName)
+	config.sources[section+"."+newName] = source
+
+	return nil
+}
+
+func (config *WildFireConfig) mergeLayer(layer ConfigLayer, layerConfig *WildFireConfig) {
+	if config.sources == nil {
+		config.sources = make(map[string]string)
+	}
+
+	for name, project := range layerConfig.Projects {
+		config.Projects[name] = project
+		config.sources["projects."+name] = layer.Name
+	}
+
+	for name, group := range layerConfig.Groups {
+		config.Groups[name] = group
+		config.sources["groups."+name] = layer.Name
+	}
+
+	for name, workspace := range layerConfig.Workspaces {
+		config.Workspaces[name] = workspace
+		config.sources["workspaces."+name] = layer.Name
+	}
+}
+
+// layerConfig returns the entries of the configuration which are stored in the layer.
+func (config *WildFireConfig) layerConfig(layer string) *WildFireConfig {
+	res := &WildFireConfig{
+		Projects:   make(map[string]*ProjectConfig),
+		Groups:     make(map[string]*GroupConfig),
+		Workspaces: make(map[string]*WorkspaceConfig),
+	}
+
+	for name, project := range config.Projects {
+		if config.Source("projects", name) == layer {
+			res.Projects[name] = project
+		}
+	}
+
+	for name, group := range config.Groups {
+		if config.Source("groups", name) == layer {
+			res.Groups[name] = group
+		}
+	}
+
+	for name, workspace := range config.Workspaces {
+		if config.Source("workspaces", name) == layer {
+			res.Workspaces[name] = workspace
+		}
+	}
+
+	return res
+}
+
+func (config *WildFireConfig) layerSnapshot(layer string) string {
+	data, _ := yaml.Marshal(config.layerConfig(layer))
+
+	return string(data)
+}
+
+func (config *WildFireConfig) snapshotLayers() {
+	config.snapshots = make(map[string]string)
+
+	for _, layer := range config.layers() {
+		config.snapshots[layer.Name] = config.layerSnapshot(layer.Name)
+	}
+}
+
+// saveConfigLayer writes the entries of a layer to its file if they changed since the configuration was loaded. The
+// local configuration is expected to be locked by the caller.
+func (config *WildFireConfig) saveConfigLayer(layer ConfigLayer) error {
+	if config.layerSnapshot(layer.Name) == config.snapshots[layer.Name] {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(layer.Path), 0755); err != nil {
+		return err
+	}
+
+	if layer.Name != ConfigLayerLocal {
+		lock, err := LockConfig(layer.Path)
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock()
+	}
+
+	layerConfig := config.layerConfig(layer.Name)
+
+	v := viper.New()
+	v.SetConfigFile(layer.Path)
+	if err := v.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s configuration '%s': %s", layer.Name, layer.Path, err)
+	}
+
+	v.Set("version", ConfigVersion)
+	v.Set("projects", layerConfig.Projects)
+	v.Set("groups", layerConfig.Groups)
+	v.Set("workspaces", layerConfig.Workspaces)
+
+	return writeConfigFile(layer.Path, v.WriteConfigAs)
+}
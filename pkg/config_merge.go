@@ -0,0 +1,195 @@
+package pkg
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"sort"
+	"strings"
+)
+
+var configSections = []string{"projects", "groups", "workspaces"}
+
+// ConfigConflictError is returned when an entry of the configuration has been changed by a command and, since the
+// command loaded the configuration, by another one.
+type ConfigConflictError struct {
+	Section string
+	Name    string
+}
+
+func (e *ConfigConflictError) Error() string {
+	return fmt.Sprintf(
+		"%s '%s' has been changed by another command since the configuration was loaded, run the command again",
+		strings.TrimSuffix(e.Section, "s"),
+		e.Name,
+	)
+}
+
+// trackChanges records the entries of the configuration, so the changes made to it can be merged into the
+// configuration of the store once saved, see SaveConfigChanges.
+func (config *WildFireConfig) trackChanges() {
+	config.loadedEntries = config.entrySnapshots()
+}
+
+// entrySnapshots returns the YAML of every project, group and workspace keyed by section and name.
+func (config *WildFireConfig) entrySnapshots() map[string]string {
+	res := map[string]string{}
+
+	for _, section := range configSections {
+		for _, name := range config.entryNames(section) {
+			res[section+"."+name] = config.entrySnapshot(section, name)
+		}
+	}
+
+	return res
+}
+
+func (config *WildFireConfig) entryNames(section string) []string {
+	var res []string
+
+	switch section {
+	case "projects":
+		for name := range config.Projects {
+			res = append(res, name)
+		}
+	case "groups":
+		for name := range config.Groups {
+			res = append(res, name)
+		}
+	case "workspaces":
+		for name := range config.Workspaces {
+			res = append(res, name)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// entrySnapshot returns the YAML of the entry, empty when it does not exist.
+func (config *WildFireConfig) entrySnapshot(section string, name string) string {
+	var entry interface{}
+	var ok bool
+
+	switch section {
+	case "projects":
+		entry, ok = config.Projects[name]
+	case "groups":
+		entry, ok = config.Groups[name]
+	case "workspaces":
+		entry, ok = config.Workspaces[name]
+	}
+
+	if ok == false {
+		return ""
+	}
+
+	data, _ := yaml.Marshal(entry)
+
+	return string(data)
+}
+
+// copyEntry sets the entry of the section to the entry of the other configuration, removing it when it does not
+// exist there. The entry keeps the layer it belongs to in the other configuration.
+func (config *WildFireConfig) copyEntry(other *WildFireConfig, section string, name string) {
+	key := section + "." + name
+
+	switch section {
+	case "projects":
+		if project, ok := other.Projects[name]; ok {
+			config.Projects[name] = project
+		} else {
+			delete(config.Projects, name)
+		}
+	case "groups":
+		if group, ok := other.Groups[name]; ok {
+			config.Groups[name] = group
+		} else {
+			delete(config.Groups, name)
+		}
+	case "workspaces":
+		if workspace, ok := other.Workspaces[name]; ok {
+			config.Workspaces[name] = workspace
+		} else {
+			delete(config.Workspaces, name)
+		}
+	}
+
+	if config.sources == nil {
+		config.sources = make(map[string]string)
+	}
+	config.sources[key] = other.Source(section, name)
+}
+
+// mergeChanges applies the entries which have been added, changed or removed in the other configuration since its
+// changes have been tracked. A ConfigConflictError is returned when one of them has been changed differently in the
+// configuration, which is then left unchanged.
+func (config *WildFireConfig) mergeChanges(other *WildFireConfig) error {
+	type change struct {
+		section string
+		name    string
+	}
+	var changes []change
+
+	for _, section := range configSections {
+		names := other.entryNames(section)
+		for key := range other.loadedEntries {
+			if strings.HasPrefix(key, section+".") {
+				names = appendMissingName(names, strings.TrimPrefix(key, section+"."))
+			}
+		}
+
+		for _, name := range names {
+			before := other.loadedEntries[section+"."+name]
+			after := other.entrySnapshot(section, name)
+			if before == after {
+				continue
+			}
+
+			if current := config.entrySnapshot(section, name); current != before && current != after {
+				return &ConfigConflictError{Section: section, Name: name}
+			}
+
+			changes = append(changes, change{section, name})
+		}
+	}
+
+	for _, change := range changes {
+		config.copyEntry(other, change.section, change.name)
+	}
+
+	return nil
+}
+
+// SaveConfigChanges saves the configuration to the store. When the configuration has been loaded by ProjectFunc
+// only the changes made to it are saved: the store is locked, the configuration is loaded again and the projects,
+// groups and workspaces which have been changed are merged into it, so the changes saved by other commands in the
+// meantime are kept. A ConfigConflictError is returned when an entry has also been changed by another command.
+func SaveConfigChanges(store ConfigStore, config *WildFireConfig) error {
+	lock, err := store.Lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if config.loadedEntries == nil {
+		return store.Save(config)
+	}
+
+	current, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := current.mergeChanges(config); err != nil {
+		return err
+	}
+
+	if err := store.Save(current); err != nil {
+		return err
+	}
+
+	config.trackChanges()
+
+	return nil
+}
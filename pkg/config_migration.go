@@ -0,0 +1,205 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ConfigVersion is the configuration schema version written by this version of wildfire.
+const ConfigVersion = 2
+
+// ConfigMigration upgrades the raw settings of a configuration file to Version from the previous version.
+type ConfigMigration struct {
+	Version     int
+	Description string
+	Migrate     func(settings map[string]interface{}) error
+}
+
+var configMigrations = []ConfigMigration{
+	{
+		Version:     1,
+		Description: "Set missing project names and lower case project types",
+		Migrate:     migrateProjectNamesAndTypes,
+	},
+	{
+		Version:     2,
+		Description: "Convert group project lists to group objects",
+		Migrate:     migrateGroupObjects,
+	},
+}
+
+// GetConfigMigrations returns all configuration migrations ordered by version.
+func GetConfigMigrations() []ConfigMigration {
+	return configMigrations
+}
+
+func getSettingsVersion(settings map[string]interface{}) (int, error) {
+	switch version := settings["version"].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return version, nil
+	case float64:
+		return int(version), nil
+	case string:
+		v, err := strconv.Atoi(version)
+		if err != nil {
+			return 0, fmt.Errorf("invalid configuration version '%s'", version)
+		}
+
+		return v, nil
+	default:
+		return 0, fmt.Errorf("invalid configuration version '%v'", version)
+	}
+}
+
+// MigrateSettings upgrades the raw settings of a configuration file to ConfigVersion and returns the migrations
+// which were applied.
+func MigrateSettings(settings map[string]interface{}) ([]ConfigMigration, error) {
+	version, err := getSettingsVersion(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if version > ConfigVersion {
+		return nil, fmt.Errorf(
+			"configuration version %d is newer than the supported version %d, update wildfire",
+			version,
+			ConfigVersion,
+		)
+	}
+
+	var applied []ConfigMigration
+	for _, migration := range configMigrations {
+		if migration.Version <= version {
+			continue
+		}
+
+		if err := migration.Migrate(settings); err != nil {
+			return applied, fmt.Errorf("failed to migrate configuration to version %d: %s", migration.Version, err)
+		}
+
+		settings["version"] = migration.Version
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+// MigrateConfigFile upgrades a configuration file to ConfigVersion. It returns the applied migrations and a diff of
+// the changes. When dryRun is set the file is not written.
+func MigrateConfigFile(path string, dryRun bool) ([]ConfigMigration, string, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+
+		return nil, "", fmt.Errorf("failed to read configuration '%s': %s", path, err)
+	}
+
+	settings := v.AllSettings()
+	before, err := yaml.Marshal(settings)
+	if err != nil {
+		return nil, "", err
+	}
+
+	applied, err := MigrateSettings(settings)
+	if err != nil || len(applied) == 0 {
+		return applied, "", err
+	}
+
+	after, err := yaml.Marshal(settings)
+	if err != nil {
+		return nil, "", err
+	}
+
+	diff := DiffLines(string(before), string(after))
+	if dryRun {
+		return applied, diff, nil
+	}
+
+	lock, err := LockConfig(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer lock.Unlock()
+
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+
+	return applied, diff, writeConfigFile(path, v.WriteConfigAs)
+}
+
+func decodeSettings(settings map[string]interface{}, config *WildFireConfig) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           config,
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(settings)
+}
+
+// migrateProjectNamesAndTypes sets the name of projects which only have a key and lower cases project types.
+// Unknown project types are kept and reported by the validation of the configuration.
+func migrateProjectNamesAndTypes(settings map[string]interface{}) error {
+	projects, ok := settings["projects"].(map[string]interface{})
+	if ok == false {
+		return nil
+	}
+
+	for name, value := range projects {
+		project, ok := value.(map[string]interface{})
+		if ok == false {
+			continue
+		}
+
+		if projectName, _ := project["name"].(string); projectName == "" {
+			project["name"] = name
+		}
+
+		if projectType, ok := project["type"].(string); ok {
+			project["type"] = strings.ToLower(strings.TrimSpace(projectType))
+		}
+	}
+
+	return nil
+}
+
+// migrateGroupObjects converts groups stored as a list of project names to group objects holding the list in
+// their projects.
+func migrateGroupObjects(settings map[string]interface{}) error {
+	groups, ok := settings["groups"].(map[string]interface{})
+	if ok == false {
+		return nil
+	}
+
+	for name, value := range groups {
+		switch group := value.(type) {
+		case nil:
+			groups[name] = map[string]interface{}{"projects": []interface{}{}}
+		case []interface{}:
+			groups[name] = map[string]interface{}{"projects": group}
+		case map[string]interface{}:
+			continue
+		default:
+			return fmt.Errorf("group '%s' has an invalid format", name)
+		}
+	}
+
+	return nil
+}
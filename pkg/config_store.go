@@ -0,0 +1,200 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"gopkg.in/yaml.v2"
+	"sync"
+)
+
+// ConfigStore loads and saves the configuration used by the commands.
+type ConfigStore interface {
+	// Load returns the configuration. Changes made to it are only stored by Save.
+	Load() (*WildFireConfig, error)
+	// Save stores the configuration.
+	Save(config *WildFireConfig) error
+	// Lock prevents other users of the store from updating the configuration until the lock is released.
+	Lock() (ConfigUnlocker, error)
+	// Path returns the file of the local configuration, empty when the configuration is not stored in a file.
+	Path() string
+}
+
+type ConfigUnlocker interface {
+	Unlock() error
+}
+
+// FileConfigStore stores the configuration in a YAML file, merged with the layers of the store. A missing file is
+// loaded as an empty configuration and created when the configuration is saved.
+type FileConfigStore struct {
+	path       string
+	layers     []ConfigLayer
+	writeLayer string
+}
+
+func NewFileConfigStore(path string) *FileConfigStore {
+	return &FileConfigStore{path: path, writeLayer: ConfigLayerLocal}
+}
+
+func (s *FileConfigStore) Load() (*WildFireConfig, error) {
+	if s.path == "" {
+		return nil, errors.New("a configuration file is required")
+	}
+
+	return s.setLayers(&WildFireConfig{}).load()
+}
+
+func (s *FileConfigStore) Save(config *WildFireConfig) error {
+	if s.path == "" {
+		return errors.New("a configuration file is required")
+	}
+
+	return s.setLayers(config).SaveConfig()
+}
+
+func (s *FileConfigStore) Lock() (ConfigUnlocker, error) {
+	if s.path == "" {
+		return nil, errors.New("a configuration file is required")
+	}
+
+	return LockConfig(s.path)
+}
+
+func (s *FileConfigStore) Path() string {
+	return s.path
+}
+
+// SetPath changes the file of the store.
+func (s *FileConfigStore) SetPath(path string) {
+	s.path = path
+}
+
+// SetLayers sets the layers merged beneath the file of the store, lowest precedence first.
+func (s *FileConfigStore) SetLayers(layers ...ConfigLayer) {
+	s.layers = layers
+}
+
+// SetWriteLayer sets the layer to which new projects, groups and workspaces are written, ConfigLayerLocal by
+// default.
+func (s *FileConfigStore) SetWriteLayer(name string) {
+	s.writeLayer = name
+}
+
+// Layers returns the layers of the store followed by its file, lowest precedence first.
+func (s *FileConfigStore) Layers() []ConfigLayer {
+	return getConfigLayers(s.path, s.layers)
+}
+
+func (s *FileConfigStore) setLayers(config *WildFireConfig) *WildFireConfig {
+	config.localPath = s.path
+	config.lowerLayers = s.layers
+	config.writeLayer = s.writeLayer
+
+	return config
+}
+
+// MemoryConfigStore keeps the configuration in memory. Load and Save copy the configuration, so changes are only
+// visible once saved.
+type MemoryConfigStore struct {
+	data  []byte
+	mutex sync.Mutex
+	lock  sync.Mutex
+}
+
+// NewMemoryConfigStore creates a store containing a copy of the configuration, an empty configuration when it is nil.
+func NewMemoryConfigStore(config *WildFireConfig) *MemoryConfigStore {
+	store := &MemoryConfigStore{}
+	if config != nil {
+		_ = store.Save(config)
+	}
+
+	return store
+}
+
+func (s *MemoryConfigStore) Load() (*WildFireConfig, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	config := &WildFireConfig{}
+	if err := yaml.Unmarshal(s.data, config); err != nil {
+		return nil, err
+	}
+
+	config.Version = ConfigVersion
+	if config.Projects == nil {
+		config.Projects = make(map[string]*ProjectConfig)
+	}
+	if config.Groups == nil {
+		config.Groups = make(map[string]*GroupConfig)
+	}
+	if config.Workspaces == nil {
+		config.Workspaces = make(map[string]*WorkspaceConfig)
+	}
+
+	return config, nil
+}
+
+func (s *MemoryConfigStore) Save(config *WildFireConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.data = data
+
+	return nil
+}
+
+func (s *MemoryConfigStore) Lock() (ConfigUnlocker, error) {
+	s.lock.Lock()
+
+	return memoryConfigLock{&s.lock}, nil
+}
+
+func (s *MemoryConfigStore) Path() string {
+	return ""
+}
+
+type memoryConfigLock struct {
+	mutex *sync.Mutex
+}
+
+func (l memoryConfigLock) Unlock() error {
+	l.mutex.Unlock()
+
+	return nil
+}
+
+type configStoreKey struct{}
+
+// WithConfigStore returns a context in which the commands use the store.
+func WithConfigStore(ctx context.Context, store ConfigStore) context.Context {
+	return context.WithValue(ctx, configStoreKey{}, store)
+}
+
+// GetConfigStore returns the store of the context. Without a store in the context a FileConfigStore without a file is
+// returned, which fails to load or save the configuration.
+func GetConfigStore(ctx context.Context) ConfigStore {
+	if ctx != nil {
+		if store, ok := ctx.Value(configStoreKey{}).(ConfigStore); ok {
+			return store
+		}
+	}
+
+	return NewFileConfigStore("")
+}
+
+// GetStoreConfigLayers returns the configuration layers of the store, lowest precedence first.
+func GetStoreConfigLayers(store ConfigStore) []ConfigLayer {
+	if fileStore, ok := store.(*FileConfigStore); ok {
+		return fileStore.Layers()
+	}
+
+	if store.Path() == "" {
+		return nil
+	}
+
+	return getConfigLayers(store.Path(), nil)
+}
@@ -0,0 +1,190 @@
+package pkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var substitutionPattern = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// SubstituteValue replaces the references in a configuration value. ${VAR} is replaced with the value of the
+// environment variable VAR and ${file:path} with the content of the file, without trailing new lines. Relative file
+// paths are resolved against baseDir. $${ is replaced with a literal ${.
+func SubstituteValue(value string, baseDir string) (string, error) {
+	var substitutionErr error
+
+	res := substitutionPattern.ReplaceAllStringFunc(value, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		reference := substitutionPattern.FindStringSubmatch(match)[1]
+
+		if strings.HasPrefix(reference, "file:") {
+			path := strings.TrimPrefix(reference, "file:")
+			if filepath.IsAbs(path) == false {
+				path = filepath.Join(baseDir, path)
+			}
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil && substitutionErr == nil {
+				substitutionErr = fmt.Errorf("failed to read '%s': %s", path, err)
+			}
+
+			return strings.TrimRight(string(data), "\r\n")
+		}
+
+		variable, ok := os.LookupEnv(reference)
+		if ok == false && substitutionErr == nil {
+			substitutionErr = fmt.Errorf("environment variable '%s' is not set", reference)
+		}
+
+		return variable
+	})
+
+	if substitutionErr != nil {
+		return value, substitutionErr
+	}
+
+	return res, nil
+}
+
+// checkSubstitutions reports the project URLs and the group paths and branches which can not be substituted. The
+// configuration keeps the raw values, so references to secrets are neither displayed nor written back resolved. The
+// values are substituted where they are used, see ResolveProject and ResolveGroup.
+func (config *WildFireConfig) checkSubstitutions() {
+	config.substitutionIssues = nil
+
+	for _, name := range NewProjectService(config).GetProjectNames() {
+		project := config.Projects[name]
+		if project == nil {
+			continue
+		}
+
+		if _, err := config.substitute("projects", name, string(project.URL)); err != nil {
+			config.addSubstitutionIssue(fmt.Sprintf("project '%s' url", name), err)
+		}
+	}
+
+	for _, name := range NewGroupService(config).GetGroupNames() {
+		group := config.Groups[name]
+		if group == nil {
+			continue
+		}
+
+		if _, err := config.substitute("groups", name, group.Path); err != nil {
+			config.addSubstitutionIssue(fmt.Sprintf("group '%s' path", name), err)
+		}
+		if _, err := config.substitute("groups", name, group.Branch); err != nil {
+			config.addSubstitutionIssue(fmt.Sprintf("group '%s' branch", name), err)
+		}
+	}
+}
+
+func (config *WildFireConfig) addSubstitutionIssue(description string, err error) {
+	config.substitutionIssues = append(config.substitutionIssues, ConfigIssue{
+		Message: fmt.Sprintf("%s can not be substituted: %s", description, err),
+	})
+}
+
+// substitute substitutes the value of the entry of the section, relative files being resolved against the directory
+// of the layer the entry was loaded from.
+func (config *WildFireConfig) substitute(section string, name string, value string) (string, error) {
+	if strings.Contains(value, "${") == false {
+		return value, nil
+	}
+
+	return SubstituteValue(value, config.sourceDir(config.Source(section, name)))
+}
+
+// ResolveProject returns a copy of the project with the references in its URL substituted, see SubstituteValue.
+func (config *WildFireConfig) ResolveProject(project *ProjectConfig) (*ProjectConfig, error) {
+	url, err := config.substitute("projects", project.Name, string(project.URL))
+	if err != nil {
+		return nil, fmt.Errorf("project '%s' url can not be substituted: %s", project.Name, err)
+	}
+
+	res := *project
+	res.URL = ProjectPath(url)
+
+	return &res, nil
+}
+
+// ResolveGroup returns a copy of the group with the name with the references in its path and branch substituted, see
+// SubstituteValue.
+func (config *WildFireConfig) ResolveGroup(name string, group *GroupConfig) (*GroupConfig, error) {
+	path, err := config.substitute("groups", name, group.Path)
+	if err != nil {
+		return nil, fmt.Errorf("group '%s' path can not be substituted: %s", name, err)
+	}
+
+	branch, err := config.substitute("groups", name, group.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("group '%s' branch can not be substituted: %s", name, err)
+	}
+
+	res := *group
+	res.Path, res.Branch = path, branch
+
+	return &res, nil
+}
+
+// ResolveValues returns a copy of the configuration with the values which can be substituted replaced, e.g. to
+// display the configuration as it is used. Values which can not be substituted are kept raw.
+func (config *WildFireConfig) ResolveValues() *WildFireConfig {
+	res := *config
+	res.Projects = make(map[string]*ProjectConfig, len(config.Projects))
+	res.Groups = make(map[string]*GroupConfig, len(config.Groups))
+
+	for name, project := range config.Projects {
+		res.Projects[name] = project
+		if project == nil {
+			continue
+		}
+
+		if resolved, err := config.ResolveProject(project); err == nil {
+			res.Projects[name] = resolved
+		}
+	}
+
+	for name, group := range config.Groups {
+		res.Groups[name] = group
+		if group == nil {
+			continue
+		}
+
+		if resolved, err := config.ResolveGroup(name, group); err == nil {
+			res.Groups[name] = resolved
+		}
+	}
+
+	return &res
+}
+
+func (config *WildFireConfig) sourceDir(source string) string {
+	if path := config.SourcePath(source); path != "" {
+		return filepath.Dir(path)
+	}
+
+	return "."
+}
+
+// substitute returns the include with its location substituted.
+func (i IncludeConfig) substitute(baseDir string) (IncludeConfig, error) {
+	name := i.SourceName()
+
+	for _, value := range []*string{&i.Path, &i.URL, &i.Git, &i.Ref, &i.File} {
+		resolved, err := SubstituteValue(*value, baseDir)
+		if err != nil {
+			return i, fmt.Errorf("include '%s' can not be substituted: %s", name, err)
+		}
+
+		*value = resolved
+	}
+
+	return i, nil
+}
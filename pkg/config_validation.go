@@ -0,0 +1,215 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"sort"
+	"strings"
+)
+
+// ConfigIssue describes a problem found in the configuration. Issues which can be repaired
+// without user input carry a fix which is applied by ConfigIssue.Fix.
+type ConfigIssue struct {
+	Message string
+	fix     func()
+}
+
+func (i ConfigIssue) Fixable() bool {
+	return i.fix != nil
+}
+
+func (i ConfigIssue) Fix() bool {
+	if i.fix == nil {
+		return false
+	}
+
+	i.fix()
+
+	return true
+}
+
+func (i ConfigIssue) String() string {
+	return i.Message
+}
+
+// Validate checks the configuration for inconsistencies such as project names which do not match their keys,
+// invalid project types and URLs, dependencies on missing projects or cycles of dependencies, duplicate group members
+// and groups referencing missing projects.
+func (config *WildFireConfig) Validate() []ConfigIssue {
+	var issues []ConfigIssue
+
+	projectNames := make([]string, 0, len(config.Projects))
+	for name := range config.Projects {
+		projectNames = append(projectNames, name)
+	}
+	sort.Strings(projectNames)
+
+	for _, name := range projectNames {
+		issues = append(issues, config.validateProject(name)...)
+	}
+
+	if cycle := GetProjectDependencies(config, projectNames).Cycle(); cycle != nil {
+		issues = append(issues, ConfigIssue{Message: (&DependencyCycleError{Cycle: cycle}).Error()})
+	}
+
+	groupNames := make([]string, 0, len(config.Groups))
+	for name := range config.Groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+
+	for _, name := range groupNames {
+		issues = append(issues, config.validateGroup(name)...)
+	}
+
+	issues = append(issues, config.substitutionIssues...)
+
+	return issues
+}
+
+func (config *WildFireConfig) validateProject(name string) []ConfigIssue {
+	project := config.Projects[name]
+	if project == nil {
+		return []ConfigIssue{{
+			Message: fmt.Sprintf("project '%s' has no configuration", name),
+			fix: func() {
+				delete(config.Projects, name)
+			},
+		}}
+	}
+
+	var issues []ConfigIssue
+
+	if project.Name != name {
+		issues = append(issues, ConfigIssue{
+			Message: fmt.Sprintf("project '%s' has mismatching name '%s'", name, project.Name),
+			fix: func() {
+				project.Name = name
+			},
+		})
+	}
+
+	if project.Type.ValidType() == false {
+		issues = append(issues, ConfigIssue{
+			Message: fmt.Sprintf("project '%s' has invalid type '%s'", name, project.Type),
+		})
+	}
+
+	if project.URL == "" {
+		issues = append(issues, ConfigIssue{
+			Message: fmt.Sprintf("project '%s' has no URL", name),
+		})
+	} else if url, err := config.substitute("projects", name, string(project.URL)); err == nil {
+		// Values which can not be substituted are reported by checkSubstitutions. The error of a substituted URL is
+		// not reported as it could contain the substituted secrets.
+		if _, err := transport.NewEndpoint(url); err != nil && url == string(project.URL) {
+			issues = append(issues, ConfigIssue{
+				Message: fmt.Sprintf("project '%s' has invalid URL '%s': %s", name, project.URL, err),
+			})
+		} else if err != nil {
+			issues = append(issues, ConfigIssue{
+				Message: fmt.Sprintf("project '%s' has invalid URL '%s' once substituted", name, project.URL),
+			})
+		}
+	}
+
+	for _, dependency := range project.DependsOn {
+		if dependency != name && config.Projects[dependency] != nil {
+			continue
+		}
+		dependency := dependency
+
+		message := fmt.Sprintf("project '%s' depends on project '%s' which does not exist", name, dependency)
+		if dependency == name {
+			message = fmt.Sprintf("project '%s' depends on itself", name)
+		}
+
+		issues = append(issues, ConfigIssue{
+			Message: message,
+			fix: func() {
+				project.DependsOn = removeName(project.DependsOn, dependency)
+			},
+		})
+	}
+
+	return issues
+}
+
+func (config *WildFireConfig) validateGroup(name string) []ConfigIssue {
+	group := config.Groups[name]
+	if group == nil {
+		return nil
+	}
+
+	var issues []ConfigIssue
+	seen := map[string]bool{}
+	reported := map[string]bool{}
+
+	for _, projectName := range group.Projects {
+		if seen[projectName] && !reported[projectName] {
+			reported[projectName] = true
+			projectName := projectName
+
+			issues = append(issues, ConfigIssue{
+				Message: fmt.Sprintf("group '%s' contains project '%s' more than once", name, projectName),
+				fix: func() {
+					var res []string
+					found := false
+					for _, groupProjectName := range group.Projects {
+						if groupProjectName == projectName {
+							if found {
+								continue
+							}
+							found = true
+						}
+
+						res = append(res, groupProjectName)
+					}
+					group.Projects = res
+				},
+			})
+		}
+		seen[projectName] = true
+	}
+
+	groupService := &Group{config}
+	for _, includedGroup := range group.Groups {
+		if config.Groups[includedGroup] != nil {
+			continue
+		}
+		includedGroup := includedGroup
+
+		issues = append(issues, ConfigIssue{
+			Message: fmt.Sprintf("group '%s' includes group '%s' which does not exist", name, includedGroup),
+			fix: func() {
+				groupService.ExcludeGroup(group, includedGroup)
+			},
+		})
+	}
+
+	if cycle := groupService.includeCycle(name, nil); len(cycle) != 0 && cycle[0] == name {
+		issues = append(issues, ConfigIssue{
+			Message: fmt.Sprintf("group '%s' includes itself: %s", name, strings.Join(cycle, " -> ")),
+		})
+	}
+
+	missing := map[string]bool{}
+	for _, projectName := range groupService.GetMissingProjects(group) {
+		if missing[projectName] {
+			continue
+		}
+		missing[projectName] = true
+		projectName := projectName
+
+		issues = append(issues, ConfigIssue{
+			Message: fmt.Sprintf("group '%s' references project '%s' which does not exist", name, projectName),
+			fix: func() {
+				for groupService.HasProject(group, projectName) {
+					group = groupService.RemoveProject(group, projectName)
+				}
+			},
+		})
+	}
+
+	return issues
+}
@@ -0,0 +1,104 @@
+package pkg
+
+import (
+	"strings"
+)
+
+const diffContextLines = 3
+
+type diffLine struct {
+	prefix string
+	text   string
+}
+
+// DiffLines returns a line based diff of two texts. Changed lines are prefixed with '-' and '+' and are surrounded
+// by up to 3 unchanged lines. An empty string is returned when the texts are equal.
+func DiffLines(before string, after string) string {
+	if before == after {
+		return ""
+	}
+
+	lines := diffTextLines(splitLines(before), splitLines(after))
+
+	var sb strings.Builder
+	lastPrinted := -1
+	for index, line := range lines {
+		if isDiffContext(lines, index) == false {
+			continue
+		}
+
+		if index != lastPrinted+1 {
+			sb.WriteString("@@\n")
+		}
+
+		sb.WriteString(line.prefix + " " + line.text + "\n")
+		lastPrinted = index
+	}
+
+	return sb.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffTextLines aligns the lines of both texts through their longest common subsequence.
+func diffTextLines(before []string, after []string) []diffLine {
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var res []diffLine
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			res = append(res, diffLine{" ", before[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, diffLine{"-", before[i]})
+			i++
+		default:
+			res = append(res, diffLine{"+", after[j]})
+			j++
+		}
+	}
+
+	for ; i < len(before); i++ {
+		res = append(res, diffLine{"-", before[i]})
+	}
+
+	for ; j < len(after); j++ {
+		res = append(res, diffLine{"+", after[j]})
+	}
+
+	return res
+}
+
+func isDiffContext(lines []diffLine, index int) bool {
+	for offset := -diffContextLines; offset <= diffContextLines; offset++ {
+		if index+offset >= 0 && index+offset < len(lines) && lines[index+offset].prefix != " " {
+			return true
+		}
+	}
+
+	return false
+}
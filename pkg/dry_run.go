@@ -0,0 +1,183 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// RunOptions control how the commands and the operations of the wildfire client run in a context, see
+// WithRunOptions. The options are read when an operation runs, so the CLI sets them once the flags have been parsed.
+type RunOptions struct {
+	// DryRun reports what would change in the configuration, in the clones and in the remotes instead of changing
+	// them. It is set with the --dry-run flag. Dry runs are neither recorded in the history nor checkpointed.
+	DryRun bool
+}
+
+type runOptionsKey struct{}
+
+// WithRunOptions returns a context in which the commands and the operations of the wildfire client use the options.
+func WithRunOptions(ctx context.Context, options *RunOptions) context.Context {
+	return context.WithValue(ctx, runOptionsKey{}, options)
+}
+
+// IsDryRun reports whether the options of the context make a dry run, see RunOptions.DryRun.
+func IsDryRun(ctx context.Context) bool {
+	if ctx != nil {
+		if options, ok := ctx.Value(runOptionsKey{}).(*RunOptions); ok && options != nil {
+			return options.DryRun
+		}
+	}
+
+	return false
+}
+
+type PlannedAction string
+
+const (
+	PlannedActionClone PlannedAction = "clone"
+	PlannedActionSkip  PlannedAction = "skip"
+	PlannedActionRun   PlannedAction = "run"
+	PlannedActionPush  PlannedAction = "push"
+	PlannedActionMove  PlannedAction = "move"
+)
+
+// PlannedChange is a change a dry run would make to the clone of a project.
+type PlannedChange struct {
+	Clone
+	Action PlannedAction
+	Detail string
+}
+
+// ConfigDiff returns the changes made to the configuration compared to the configuration of the store, as a line
+// based diff of their YAML, see DiffLines. An empty string is returned when nothing changed.
+func ConfigDiff(store ConfigStore, config *WildFireConfig) (string, error) {
+	stored, err := store.Load()
+	if err != nil {
+		return "", err
+	}
+
+	before, err := yaml.Marshal(stored)
+	if err != nil {
+		return "", err
+	}
+
+	after, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	return DiffLines(string(before), string(after)), nil
+}
+
+// PlanClone returns the change cloning the project to the clone path on the branch would make. Clones which already
+// exist and projects missing from the configuration are skipped.
+func PlanClone(clone Clone, project *ProjectConfig, branch string) PlannedChange {
+	if project == nil {
+		return PlannedChange{Clone: clone, Action: PlannedActionSkip, Detail: "does not exist in configuration"}
+	}
+
+	if _, err := os.Stat(clone.Path); err == nil {
+		return PlannedChange{Clone: clone, Action: PlannedActionSkip, Detail: "already cloned"}
+	}
+
+	detail := string(project.URL)
+	if branch != "" {
+		detail = fmt.Sprintf("%s on branch '%s'", detail, branch)
+	}
+
+	return PlannedChange{Clone: clone, Action: PlannedActionClone, Detail: detail}
+}
+
+// PlanCommand returns the changes running the command in the clone would make. When the command is a git push the
+// branches it would push are added.
+func PlanCommand(clone Clone, command string, args ...string) []PlannedChange {
+	res := []PlannedChange{{Clone: clone, Action: PlannedActionRun, Detail: strings.Join(append([]string{command}, args...), " ")}}
+
+	if push := planPush(clone.Path, command, args); push != "" {
+		res = append(res, PlannedChange{Clone: clone, Action: PlannedActionPush, Detail: push})
+	}
+
+	return res
+}
+
+// planPush describes what git push would push from the clone in dir, empty when the command is not a git push.
+func planPush(dir string, command string, args []string) string {
+	if command != "git" || len(args) == 0 || args[0] != "push" {
+		return ""
+	}
+
+	var positional []string
+	all, tags := false, false
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "--all" || args[i] == "--mirror":
+			all = true
+		case args[i] == "--tags":
+			tags = true
+		case args[i] == "-o" || args[i] == "--push-option" || args[i] == "--repo":
+			i++
+		case strings.HasPrefix(args[i], "-"):
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	branch := currentBranch(dir)
+	remote := "origin"
+	if len(positional) != 0 {
+		remote = positional[0]
+	} else if configured, err := gitOutput(dir, "config", "branch."+branch+".remote"); err == nil && strings.TrimSpace(configured) != "" {
+		remote = strings.TrimSpace(configured)
+	}
+
+	var refs []string
+	switch {
+	case all:
+		refs = append(refs, "every branch")
+	case len(positional) > 1:
+		for _, refspec := range positional[1:] {
+			source := strings.SplitN(strings.TrimPrefix(refspec, "+"), ":", 2)
+			switch {
+			case source[0] == "" && len(source) == 2:
+				refs = append(refs, fmt.Sprintf("deletion of '%s'", source[1]))
+			case source[0] == "HEAD":
+				refs = append(refs, fmt.Sprintf("branch '%s'", branch))
+			default:
+				refs = append(refs, fmt.Sprintf("'%s'", source[0]))
+			}
+		}
+	case tags == false:
+		refs = append(refs, fmt.Sprintf("branch '%s'", branch))
+	}
+	if tags {
+		refs = append(refs, "every tag")
+	}
+
+	return fmt.Sprintf("%s to '%s'", strings.Join(refs, ", "), remote)
+}
+
+// currentBranch returns the branch checked out in the clone, HEAD when it can not be determined.
+func currentBranch(dir string) string {
+	branch, err := gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil || strings.TrimSpace(branch) == "" {
+		return "HEAD"
+	}
+
+	return strings.TrimSpace(branch)
+}
+
+// WritePlan writes the planned changes as a table.
+func WritePlan(out io.Writer, changes []PlannedChange) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tACTION\tPATH\tDETAIL")
+	for _, change := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", change.Project, change.Action, change.Path, change.Detail)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// Editor changes the files of a clone in-process, e.g. to set a key of a configuration file or to bump the version
+// of a dependency. Edit returns the files, relative to the clone and using forward slashes, which have been changed.
+// Editors must only write files whose content changes, so running them again is a no-op. With dryRun the changed
+// files are returned without writing them.
+type Editor interface {
+	Edit(dir string, dryRun bool) ([]string, error)
+	String() string
+}
+
+// EditResult is the outcome of running an editor in a clone.
+type EditResult struct {
+	ExecResult
+	Changed []string
+}
+
+// EditClones runs the editor in parallel in every clone which matches the predicates. The returned results are in
+// the order of the clones. With dryRun the clones are left unchanged.
+func EditClones(clones []Clone, predicates []ClonePredicate, editor Editor, dryRun bool) []EditResult {
+	res := make([]EditResult, len(clones))
+
+	var wg sync.WaitGroup
+	wg.Add(len(clones))
+
+	for i, clone := range clones {
+		res[i].Clone = clone
+
+		go func(result *EditResult) {
+			defer wg.Done()
+
+			if filterClone(&result.ExecResult, predicates) == false {
+				return
+			}
+
+			result.Changed, result.Err = editor.Edit(result.Path, dryRun)
+		}(&res[i])
+	}
+
+	wg.Wait()
+
+	return res
+}
+
+// writeFile replaces the content of the file, keeping its permissions. It returns whether the content changed. With
+// dryRun the file is left unchanged.
+func writeFile(path string, data []byte, dryRun bool) (bool, error) {
+	mode := os.FileMode(0644)
+
+	if info, err := os.Stat(path); err == nil {
+		current, err := ioutil.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+
+		if string(current) == string(data) {
+			return false, nil
+		}
+
+		mode = info.Mode()
+	}
+
+	if dryRun {
+		return true, nil
+	}
+
+	return true, ioutil.WriteFile(path, data, mode)
+}
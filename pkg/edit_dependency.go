@@ -0,0 +1,188 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DependencyEditor bumps the version of a dependency in the go.mod, package.json, requirements.txt and pom.xml
+// files at the root of the clone. Maven dependencies are named artifactId or groupId:artifactId.
+type DependencyEditor struct {
+	Name    string
+	Version string
+}
+
+type dependencyManifest struct {
+	file string
+	bump func(content string, name string, version string) string
+}
+
+var dependencyManifests = []dependencyManifest{
+	{"go.mod", bumpGoModule},
+	{"package.json", bumpNodePackage},
+	{"requirements.txt", bumpPythonRequirement},
+	{"pom.xml", bumpMavenDependency},
+}
+
+var (
+	nodeDependenciesPattern = regexp.MustCompile(
+		`"(?:dependencies|devDependencies|peerDependencies|optionalDependencies)"\s*:\s*\{[^}]*\}`,
+	)
+	mavenDependencyPattern = regexp.MustCompile(`(?s)<dependency>.*?</dependency>`)
+	mavenVersionPattern    = regexp.MustCompile(`<version>\s*([^<]*?)\s*</version>`)
+)
+
+func (e *DependencyEditor) String() string {
+	return fmt.Sprintf("bump %s to %s", e.Name, e.Version)
+}
+
+func (e *DependencyEditor) Edit(dir string, dryRun bool) ([]string, error) {
+	if e.Name == "" || e.Version == "" {
+		return nil, errors.New("a dependency name and version are required")
+	}
+
+	var changed []string
+
+	for _, manifest := range dependencyManifests {
+		path := filepath.Join(dir, manifest.file)
+
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return changed, err
+		}
+
+		updated, err := writeFile(path, []byte(manifest.bump(string(data), e.Name, e.Version)), dryRun)
+		if err != nil {
+			return changed, err
+		}
+		if updated {
+			changed = append(changed, manifest.file)
+		}
+	}
+
+	return changed, nil
+}
+
+// bumpGoModule only changes require directives, so the versions of replace and exclude directives are kept.
+func bumpGoModule(content string, name string, version string) string {
+	if strings.HasPrefix(version, "v") == false {
+		version = "v" + version
+	}
+
+	requirePattern := regexp.MustCompile(`^(\s*require\s+` + regexp.QuoteMeta(name) + `\s+)v\S+`)
+	blockPattern := regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(name) + `\s+)v\S+`)
+	replacement := "${1}" + escapeReplacement(version)
+
+	lines := strings.SplitAfter(content, "\n")
+	block := ""
+	for index, line := range lines {
+		fields := strings.Fields(line)
+		switch {
+		case block != "" && len(fields) != 0 && fields[0] == ")":
+			block = ""
+		case block == "require":
+			lines[index] = blockPattern.ReplaceAllString(line, replacement)
+		case block == "" && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+		case block == "":
+			lines[index] = requirePattern.ReplaceAllString(line, replacement)
+		}
+	}
+
+	return strings.Join(lines, "")
+}
+
+// bumpNodePackage keeps the range operator of the dependency, e.g. ^, unless the version has one.
+func bumpNodePackage(content string, name string, version string) string {
+	pattern := regexp.MustCompile(`("` + regexp.QuoteMeta(name) + `"\s*:\s*")([~^]?)[^"]*(")`)
+
+	return nodeDependenciesPattern.ReplaceAllStringFunc(content, func(dependencies string) string {
+		return pattern.ReplaceAllStringFunc(dependencies, func(dependency string) string {
+			match := pattern.FindStringSubmatch(dependency)
+
+			operator := match[2]
+			if strings.ContainsAny(version[:1], "~^<>=*") {
+				operator = ""
+			}
+
+			return match[1] + operator + version + match[3]
+		})
+	})
+}
+
+// bumpPythonRequirement keeps the version operator of the requirement. Project names are compared as normalized by
+// pip, so -, _ and . are equivalent.
+func bumpPythonRequirement(content string, name string, version string) string {
+	var namePattern strings.Builder
+	for _, part := range regexp.MustCompile(`[-_.]+`).Split(name, -1) {
+		if namePattern.Len() != 0 {
+			namePattern.WriteString(`[-_.]+`)
+		}
+		namePattern.WriteString(regexp.QuoteMeta(part))
+	}
+
+	pattern := regexp.MustCompile(
+		`(?mi)^(\s*` + namePattern.String() + `(?:\[[^\]]*\])?\s*(?:===|==|~=|>=|<=|!=|>|<)\s*)[^\s;#,]+`,
+	)
+
+	return pattern.ReplaceAllString(content, "${1}"+escapeReplacement(version))
+}
+
+// bumpMavenDependency updates the version of the dependency, or the property it references.
+func bumpMavenDependency(content string, name string, version string) string {
+	groupID, artifactID := "", name
+	if parts := strings.SplitN(name, ":", 2); len(parts) == 2 {
+		groupID, artifactID = parts[0], parts[1]
+	}
+
+	var properties []string
+
+	content = mavenDependencyPattern.ReplaceAllStringFunc(content, func(dependency string) string {
+		if xmlElement(dependency, "artifactId") != artifactID {
+			return dependency
+		}
+		if groupID != "" && xmlElement(dependency, "groupId") != groupID {
+			return dependency
+		}
+
+		current := mavenVersionPattern.FindStringSubmatch(dependency)
+		if current == nil {
+			return dependency
+		}
+
+		if strings.HasPrefix(current[1], "${") && strings.HasSuffix(current[1], "}") {
+			properties = append(properties, current[1][2:len(current[1])-1])
+			return dependency
+		}
+
+		return mavenVersionPattern.ReplaceAllString(dependency, "<version>"+escapeReplacement(version)+"</version>")
+	})
+
+	for _, property := range properties {
+		pattern := regexp.MustCompile(`(<` + regexp.QuoteMeta(property) + `>)[^<]*(</` + regexp.QuoteMeta(property) + `>)`)
+		content = pattern.ReplaceAllString(content, "${1}"+escapeReplacement(version)+"${2}")
+	}
+
+	return content
+}
+
+func xmlElement(content string, name string) string {
+	match := regexp.MustCompile(`<` + name + `>\s*([^<]*?)\s*</` + name + `>`).FindStringSubmatch(content)
+	if match == nil {
+		return ""
+	}
+
+	return match[1]
+}
+
+func escapeReplacement(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
@@ -0,0 +1,241 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var indentPattern = regexp.MustCompile(`(?m)^([ \t]+)\S`)
+
+// SetKeyEditor sets the value of a key in a YAML or JSON file, the format being chosen by the extension of the file.
+// Clones without the file are left unchanged.
+// Key is a dot separated path in which numbers index sequences, e.g. spec.containers.0.image. Mappings missing from
+// the path are created. Value is parsed as YAML, so "true" is a boolean while "'true'" is a string, and can be a flow
+// mapping or sequence. Unquoted values replacing a string stay strings. The order of the keys is kept, as are the
+// comments of YAML files.
+type SetKeyEditor struct {
+	File  string
+	Key   string
+	Value string
+}
+
+func (e *SetKeyEditor) String() string {
+	return fmt.Sprintf("set %s in %s to %s", e.Key, e.File, e.Value)
+}
+
+func (e *SetKeyEditor) Edit(dir string, dryRun bool) ([]string, error) {
+	format := strings.ToLower(path.Ext(e.File))
+	if format != ".json" && format != ".yaml" && format != ".yml" {
+		return nil, fmt.Errorf("unsupported file '%s', only YAML and JSON files can be edited", e.File)
+	}
+
+	filePath := filepath.Join(dir, filepath.FromSlash(e.File))
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %s", e.File, err)
+	}
+	if len(document.Content) == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	var value yaml.Node
+	if err := yaml.Unmarshal([]byte(e.Value), &value); err != nil || len(value.Content) == 0 {
+		value = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Value: e.Value}}}
+	}
+
+	changed, err := setNode(document.Content[0], strings.Split(e.Key, "."), value.Content[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to set '%s' in '%s': %s", e.Key, e.File, err)
+	}
+	if changed == false {
+		return nil, nil
+	}
+
+	indent := detectIndent(data)
+
+	var res bytes.Buffer
+	if format == ".json" {
+		if err := writeJSONNode(&res, document.Content[0], indent, ""); err != nil {
+			return nil, err
+		}
+		res.WriteString("\n")
+	} else {
+		encoder := yaml.NewEncoder(&res)
+		encoder.SetIndent(len(indent))
+		if err := encoder.Encode(&document); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := writeFile(filePath, res.Bytes(), dryRun); err != nil {
+		return nil, err
+	}
+
+	return []string{e.File}, nil
+}
+
+// setNode sets the value at the path inside node. It returns whether the node changed.
+func setNode(node *yaml.Node, keyPath []string, value *yaml.Node) (bool, error) {
+	key := keyPath[0]
+
+	var child **yaml.Node
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			if node.Content[i].Value == key {
+				child = &node.Content[i+1]
+				break
+			}
+		}
+
+		if child == nil {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, nil)
+			child = &node.Content[len(node.Content)-1]
+		}
+	case yaml.SequenceNode:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(node.Content) {
+			return false, fmt.Errorf("'%s' is not an index of the sequence", key)
+		}
+
+		child = &node.Content[index]
+	default:
+		return false, fmt.Errorf("'%s' can not be set on a scalar value", key)
+	}
+
+	if len(keyPath) > 1 {
+		if *child == nil {
+			*child = &yaml.Node{Kind: yaml.MappingNode}
+		}
+
+		return setNode(*child, keyPath[1:], value)
+	}
+
+	// The comments and quoting of the replaced value are kept, and unquoted values replacing a string stay strings.
+	if current := *child; current != nil {
+		value.HeadComment, value.LineComment, value.FootComment = current.HeadComment, current.LineComment, current.FootComment
+
+		if current.Kind == yaml.ScalarNode && value.Kind == yaml.ScalarNode && current.ShortTag() == "!!str" {
+			if value.Style == 0 || value.ShortTag() == "!!str" {
+				value.Tag, value.Style = "!!str", current.Style
+			}
+		}
+
+		if sameNode(current, value) {
+			return false, nil
+		}
+	}
+
+	*child = value
+
+	return true, nil
+}
+
+func sameNode(a *yaml.Node, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Value != b.Value || a.ShortTag() != b.ShortTag() || len(a.Content) != len(b.Content) {
+		return false
+	}
+
+	for i := range a.Content {
+		if sameNode(a.Content[i], b.Content[i]) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+func detectIndent(data []byte) string {
+	match := indentPattern.FindSubmatch(data)
+	if match == nil {
+		return "  "
+	}
+
+	return string(match[1])
+}
+
+// writeJSONNode writes the node as JSON, keeping the order of the mapping keys.
+func writeJSONNode(w *bytes.Buffer, node *yaml.Node, indent string, prefix string) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return writeJSONNode(w, node.Content[0], indent, prefix)
+	case yaml.AliasNode:
+		return writeJSONNode(w, node.Alias, indent, prefix)
+	case yaml.MappingNode, yaml.SequenceNode:
+		open, close, step := "[", "]", 1
+		if node.Kind == yaml.MappingNode {
+			open, close, step = "{", "}", 2
+		}
+
+		if len(node.Content) == 0 {
+			w.WriteString(open + close)
+			return nil
+		}
+
+		w.WriteString(open + "\n")
+		for i := 0; i < len(node.Content); i += step {
+			w.WriteString(prefix + indent)
+
+			if node.Kind == yaml.MappingNode {
+				if err := writeJSONString(w, node.Content[i].Value); err != nil {
+					return err
+				}
+				w.WriteString(": ")
+			}
+
+			if err := writeJSONNode(w, node.Content[i+step-1], indent, prefix+indent); err != nil {
+				return err
+			}
+
+			if i+step < len(node.Content) {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(prefix + close)
+
+		return nil
+	}
+
+	switch node.ShortTag() {
+	case "!!null":
+		w.WriteString("null")
+	case "!!bool", "!!int", "!!float":
+		w.WriteString(node.Value)
+	default:
+		return writeJSONString(w, node.Value)
+	}
+
+	return nil
+}
+
+func writeJSONString(w *bytes.Buffer, value string) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	// Encode terminates the value with a new line.
+	w.Truncate(w.Len() - 1)
+
+	return nil
+}
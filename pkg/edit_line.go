@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LineEditor adds a line to the end of a file, unless the file already contains it, or removes every occurrence of
+// the line from the file when Remove is set. The file is created when a line is added to a file which does not exist.
+type LineEditor struct {
+	File   string
+	Line   string
+	Remove bool
+}
+
+func (e *LineEditor) String() string {
+	if e.Remove {
+		return fmt.Sprintf("remove '%s' from %s", e.Line, e.File)
+	}
+
+	return fmt.Sprintf("add '%s' to %s", e.Line, e.File)
+}
+
+func (e *LineEditor) Edit(dir string, dryRun bool) ([]string, error) {
+	path := filepath.Join(dir, filepath.FromSlash(e.File))
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && e.Remove {
+		return nil, nil
+	}
+	if err != nil && os.IsNotExist(err) == false {
+		return nil, err
+	}
+
+	content := string(data)
+	lines := strings.SplitAfter(content, "\n")
+
+	var res strings.Builder
+	found := false
+
+	for _, line := range lines {
+		if strings.TrimRight(line, "\r\n") != e.Line || line == "" {
+			res.WriteString(line)
+			continue
+		}
+
+		found = true
+		if e.Remove == false {
+			res.WriteString(line)
+		}
+	}
+
+	if e.Remove == false && found == false {
+		if content != "" && strings.HasSuffix(content, "\n") == false {
+			res.WriteString("\n")
+		}
+		res.WriteString(e.Line + "\n")
+	}
+
+	if dryRun == false {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	changed, err := writeFile(path, []byte(res.String()), dryRun)
+	if err != nil || changed == false {
+		return nil, err
+	}
+
+	return []string{e.File}, nil
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ReplaceEditor replaces the matches of a regular expression in the files of the clone matching a glob pattern.
+// The replacement can reference the groups of the expression, e.g. $1. Binary files are left unchanged.
+type ReplaceEditor struct {
+	Glob        string
+	Pattern     *regexp.Regexp
+	Replacement string
+	globExpr    *regexp.Regexp
+}
+
+func NewReplaceEditor(glob string, pattern string, replacement string) (*ReplaceEditor, error) {
+	globExpr, err := compileGlob(glob)
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern '%s': %s", glob, err)
+	}
+
+	expr, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %s", pattern, err)
+	}
+
+	return &ReplaceEditor{Glob: glob, Pattern: expr, Replacement: replacement, globExpr: globExpr}, nil
+}
+
+func (e *ReplaceEditor) String() string {
+	return fmt.Sprintf("replace '%s' with '%s' in %s", e.Pattern, e.Replacement, e.Glob)
+}
+
+func (e *ReplaceEditor) Edit(dir string, dryRun bool) ([]string, error) {
+	var changed []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.Mode().IsRegular() == false || e.globExpr.MatchString(rel) == false {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if isBinary(data) {
+			return nil
+		}
+
+		updated, err := writeFile(path, e.Pattern.ReplaceAll(data, []byte(e.Replacement)), dryRun)
+		if updated {
+			changed = append(changed, rel)
+		}
+
+		return err
+	})
+
+	return changed, err
+}
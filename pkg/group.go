@@ -2,6 +2,8 @@ package pkg
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 type GroupService interface {
@@ -9,9 +11,16 @@ type GroupService interface {
 	GetGroupNames() []string
 	CreateGroup(name string) (*GroupConfig, error)
 	DeleteGroup(name string)
+	RenameGroup(oldName string, newName string) error
 	HasProject(group *GroupConfig, projectName string) bool
 	AddProject(group *GroupConfig, projectName string) (*GroupConfig, error)
 	RemoveProject(group *GroupConfig, projectName string) *GroupConfig
+	GetProjectGroups(projectName string) []string
+	GetMissingProjects(group *GroupConfig) []string
+	IncludeGroup(group *GroupConfig, groupName string) (*GroupConfig, error)
+	ExcludeGroup(group *GroupConfig, groupName string) *GroupConfig
+	GetGroupProjects(name string) ([]string, error)
+	ValidateIncludes(name string) error
 }
 
 type Group struct {
@@ -46,12 +55,66 @@ func (g *Group) CreateGroup(name string) (*GroupConfig, error) {
 	return g.Config.Groups[name], nil
 }
 
+// DeleteGroup removes the group and its references from the groups which include it.
 func (g *Group) DeleteGroup(name string) {
 	delete(g.Config.Groups, name)
+
+	for _, group := range g.Config.Groups {
+		g.ExcludeGroup(group, name)
+	}
+}
+
+// RenameGroup renames the group and the workspace cloned from it.
+func (g *Group) RenameGroup(oldName string, newName string) error {
+	group := g.GetGroup(oldName)
+	if group == nil {
+		return fmt.Errorf("group with name '%s' does not exist", oldName)
+	}
+
+	if _, ok := g.Config.Groups[newName]; ok == true {
+		return fmt.Errorf("group with name '%s' already exists", newName)
+	}
+
+	workspaceService := NewWorkspaceService(g.Config)
+	workspace := workspaceService.GetWorkspace(oldName)
+	renameWorkspace := workspace != nil && workspace.Group == oldName && workspaceService.GetWorkspace(newName) == nil
+
+	if err := g.Config.renameSource("groups", oldName, newName); err != nil {
+		return err
+	}
+
+	if renameWorkspace {
+		if err := g.Config.renameSource("workspaces", oldName, newName); err != nil {
+			_ = g.Config.renameSource("groups", newName, oldName)
+			return err
+		}
+
+		workspaceService.DeleteWorkspace(oldName)
+		workspaceService.SetWorkspace(newName, workspace)
+	}
+
+	delete(g.Config.Groups, oldName)
+	g.Config.Groups[newName] = group
+
+	for _, w := range g.Config.Workspaces {
+		if w.Group == oldName {
+			w.Group = newName
+		}
+	}
+
+	for _, grp := range g.Config.Groups {
+		for index, includedGroup := range grp.Groups {
+			if includedGroup == oldName {
+				grp.Groups[index] = newName
+			}
+		}
+	}
+
+	return nil
 }
 
 func (g *Group) HasProject(group *GroupConfig, projectName string) bool {
-	for _, project := range *group {
+	for _, project := range group.Projects {
 		if project == projectName {
 			return true
 		}
@@ -66,20 +129,89 @@ func (g *Group) AddProject(group *GroupConfig, projectName string) (*GroupConfig
 		return nil, fmt.Errorf("project with name '%s' does not exist", projectName)
 	}
 
-	grp := *group
-	grp = append(grp, projectName)
-	*group = grp
+	if g.HasProject(group, projectName) {
+		return group, nil
+	}
+
+	group.Projects = append(group.Projects, projectName)
 
 	return group, nil
 }
 
 func (g *Group) RemoveProject(group *GroupConfig, projectName string) *GroupConfig {
 
-	for index, groupProjectName := range *group {
+	for index, groupProjectName := range group.Projects {
 		if groupProjectName == projectName {
-			grp := *group
-			grp = append(grp[:index], grp[index+1:]...)
-			*group = grp
+			group.Projects = append(group.Projects[:index], group.Projects[index+1:]...)
+
+			return group
+		}
+	}
+
+	return group
+}
+
+// GetProjectGroups returns the sorted names of the groups which contain the project.
+func (g *Group) GetProjectGroups(projectName string) []string {
+	var res []string
+
+	for name, group := range g.Config.Groups {
+		if g.HasProject(group, projectName) {
+			res = append(res, name)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// GetMissingProjects returns the names of the group members which no longer exist in the configuration projects.
+func (g *Group) GetMissingProjects(group *GroupConfig) []string {
+	var res []string
+
+	for _, projectName := range group.Projects {
+		if _, ok := g.Config.Projects[projectName]; ok == false {
+			res = append(res, projectName)
+		}
+	}
+
+	return res
+}
+
+// IncludeGroup adds the projects of the group with the provided name to the group. An error is returned if the group
+// does not exist or including it would create a cycle.
+func (g *Group) IncludeGroup(group *GroupConfig, groupName string) (*GroupConfig, error) {
+	if g.GetGroup(groupName) == nil {
+		return nil, fmt.Errorf("group with name '%s' does not exist", groupName)
+	}
+
+	for _, includedGroup := range group.Groups {
+		if includedGroup == groupName {
+			return group, nil
+		}
+	}
+
+	group.Groups = append(group.Groups, groupName)
+
+	for name, grp := range g.Config.Groups {
+		if grp != group {
+			continue
+		}
+
+		if err := g.ValidateIncludes(name); err != nil {
+			group.Groups = group.Groups[:len(group.Groups)-1]
+			return nil, err
+		}
+	}
+
+	return group, nil
+}
+
+func (g *Group) ExcludeGroup(group *GroupConfig, groupName string) *GroupConfig {
+	for index, includedGroup := range group.Groups {
+		if includedGroup == groupName {
+			group.Groups = append(group.Groups[:index], group.Groups[index+1:]...)
 
 			return group
 		}
@@ -87,3 +219,97 @@ func (g *Group) RemoveProject(group *GroupConfig, projectName string) *GroupConf
 
 	return group
 }
+
+// GetGroupProjects returns the projects of the group followed by the projects of the groups it includes. Projects
+// which are included more than once are only returned the first time.
+func (g *Group) GetGroupProjects(name string) ([]string, error) {
+	if err := g.ValidateIncludes(name); err != nil {
+		return nil, err
+	}
+
+	var res []string
+	seenProjects := map[string]bool{}
+	seenGroups := map[string]bool{}
+
+	var flatten func(name string)
+	flatten = func(name string) {
+		if seenGroups[name] {
+			return
+		}
+		seenGroups[name] = true
+
+		group := g.GetGroup(name)
+		for _, projectName := range group.Projects {
+			if seenProjects[projectName] == false {
+				seenProjects[projectName] = true
+				res = append(res, projectName)
+			}
+		}
+
+		for _, includedGroup := range group.Groups {
+			flatten(includedGroup)
+		}
+	}
+	flatten(name)
+
+	return res, nil
+}
+
+// ValidateIncludes returns an error if the group, or a group it includes, does not exist or includes itself.
+func (g *Group) ValidateIncludes(name string) error {
+	if g.GetGroup(name) == nil {
+		return fmt.Errorf("group with name '%s' does not exist", name)
+	}
+
+	if cycle := g.includeCycle(name, nil); cycle != nil {
+		return fmt.Errorf("group '%s' includes itself: %s", cycle[0], strings.Join(cycle, " -> "))
+	}
+
+	seen := map[string]bool{}
+
+	var validate func(name string) error
+	validate = func(name string) error {
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+
+		for _, includedGroup := range g.GetGroup(name).Groups {
+			if g.GetGroup(includedGroup) == nil {
+				return fmt.Errorf("group '%s' includes group '%s' which does not exist", name, includedGroup)
+			}
+
+			if err := validate(includedGroup); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return validate(name)
+}
+
+// includeCycle returns the names of the groups which form a cycle of includes reachable from the group, starting and
+// ending with the same group, or nil if there is none. Groups which do not exist are ignored.
+func (g *Group) includeCycle(name string, path []string) []string {
+	for index, groupName := range path {
+		if groupName == name {
+			return append(append([]string{}, path[index:]...), name)
+		}
+	}
+
+	group := g.GetGroup(name)
+	if group == nil {
+		return nil
+	}
+
+	path = append(path, name)
+	for _, includedGroup := range group.Groups {
+		if cycle := g.includeCycle(includedGroup, path); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
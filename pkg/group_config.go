@@ -1,3 +1,23 @@
 package pkg
 
-type GroupConfig []string
+type GroupConfig struct {
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Owner       string   `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Path        string   `json:"path,omitempty" yaml:"path,omitempty"`
+	Branch      string   `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Command     string   `json:"command,omitempty" yaml:"command,omitempty"`
+	Labels      []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Projects    []string `json:"projects" yaml:"projects"`
+	// Groups are the names of the groups whose projects are included in the group.
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+func (g *GroupConfig) HasLabel(label string) bool {
+	for _, groupLabel := range g.Labels {
+		if groupLabel == label {
+			return true
+		}
+	}
+
+	return false
+}
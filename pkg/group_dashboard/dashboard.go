@@ -0,0 +1,367 @@
+package group_dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"wildfire/pkg"
+	"wildfire/pkg/project_repository"
+)
+
+type CloneStatus string
+
+const (
+	CloneStatusMissing CloneStatus = "not cloned"
+	CloneStatusCloning CloneStatus = "cloning"
+	CloneStatusCloned  CloneStatus = "cloned"
+	CloneStatusFailed  CloneStatus = "clone failed"
+)
+
+type RunStatus string
+
+const (
+	RunStatusIdle      RunStatus = ""
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// ProjectState is the clone and run state of a project displayed in the dashboard.
+type ProjectState struct {
+	Name     string
+	Selected bool
+	Clone    CloneStatus
+	Run      RunStatus
+	Err      error
+
+	lastCommand string
+	output      bytes.Buffer
+	mutex       sync.Mutex
+}
+
+// Output returns the output of the last clone or command of the project.
+func (p *ProjectState) Output() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.output.String()
+}
+
+func (p *ProjectState) Write(data []byte) (int, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.output.Write(data)
+}
+
+func (p *ProjectState) resetOutput() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.output.Reset()
+}
+
+// Dashboard tracks the clones of the projects of a group and the commands run in them. Clone, Run and
+// RetryFailed block until all projects are done and report every state change through OnChange, so a view can
+// call them from a goroutine and redraw on change.
+type Dashboard struct {
+	Group    string
+	Path     string
+	Projects []*ProjectState
+	// OnChange is called whenever the state or output of a project changes. It is called concurrently for
+	// projects which are cloned or run in parallel.
+	OnChange func(project *ProjectState)
+	// Record receives the history entry of every clone, run and retry with the outcome of every project once they
+	// are all done. Nothing is recorded when it is not set.
+	Record func(entry *pkg.HistoryEntry)
+
+	projectService pkg.ProjectService
+	repoService    project_repository.ProjectRepositoryService
+	mutex          sync.Mutex
+}
+
+func NewDashboard(
+	group string,
+	path string,
+	projectNames []string,
+	projectService pkg.ProjectService,
+	repoService project_repository.ProjectRepositoryService,
+) *Dashboard {
+	d := &Dashboard{
+		Group:          group,
+		Path:           path,
+		OnChange:       func(project *ProjectState) {},
+		projectService: projectService,
+		repoService:    repoService,
+	}
+
+	for _, name := range projectNames {
+		state := &ProjectState{Name: name, Clone: CloneStatusMissing}
+		if _, err := os.Stat(d.ClonePath(name)); err == nil {
+			state.Clone = CloneStatusCloned
+		}
+
+		d.Projects = append(d.Projects, state)
+	}
+
+	return d
+}
+
+func (d *Dashboard) ClonePath(projectName string) string {
+	return filepath.Join(d.Path, projectName)
+}
+
+// Toggle changes whether the project at the index is selected.
+func (d *Dashboard) Toggle(index int) {
+	if index < 0 || index >= len(d.Projects) {
+		return
+	}
+
+	d.Projects[index].Selected = !d.Projects[index].Selected
+	d.OnChange(d.Projects[index])
+}
+
+// SelectAll selects every project, or clears the selection if every project is already selected.
+func (d *Dashboard) SelectAll() {
+	selected := true
+	for _, project := range d.Projects {
+		selected = selected && project.Selected
+	}
+
+	for _, project := range d.Projects {
+		project.Selected = !selected
+		d.OnChange(project)
+	}
+}
+
+// Targets returns the selected projects, or all projects if none are selected.
+func (d *Dashboard) Targets() []*ProjectState {
+	var res []*ProjectState
+
+	for _, project := range d.Projects {
+		if project.Selected {
+			res = append(res, project)
+		}
+	}
+
+	if len(res) == 0 {
+		return d.Projects
+	}
+
+	return res
+}
+
+// GetClonedProjects returns the names of the projects which have been cloned.
+func (d *Dashboard) GetClonedProjects() []string {
+	var res []string
+
+	for _, project := range d.Projects {
+		if project.Clone == CloneStatusCloned {
+			res = append(res, project.Name)
+		}
+	}
+
+	return res
+}
+
+// Clone clones the projects which have not been cloned yet.
+func (d *Dashboard) Clone(projects []*ProjectState) {
+	entry := d.newHistoryEntry("clone", "")
+
+	d.forEach(projects, func(project *ProjectState) {
+		if project.Clone == CloneStatusCloned || project.Clone == CloneStatusCloning {
+			return
+		}
+
+		d.clone(project, entry)
+	})
+
+	d.record(entry)
+}
+
+// Run runs the command in the clones of the projects. Projects which have not been cloned are skipped.
+func (d *Dashboard) Run(command string, projects []*ProjectState) error {
+	if _, _, err := pkg.ParseCommand(command); err != nil {
+		return err
+	}
+
+	entry := d.newHistoryEntry("exec", command)
+
+	d.forEach(projects, func(project *ProjectState) {
+		if project.Clone != CloneStatusCloned || project.Run == RunStatusRunning {
+			return
+		}
+
+		d.run(project, command, entry)
+	})
+
+	d.record(entry)
+
+	return nil
+}
+
+// RetryFailed clones the projects which failed to clone again and runs the last command again in the projects in
+// which it failed. The clones and every command are recorded as separate runs.
+func (d *Dashboard) RetryFailed() {
+	entries := []*pkg.HistoryEntry{d.newHistoryEntry("clone", "")}
+	commands := map[string]*pkg.HistoryEntry{}
+	var mutex sync.Mutex
+
+	runEntry := func(command string) *pkg.HistoryEntry {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if commands[command] == nil {
+			commands[command] = d.newHistoryEntry("exec", command)
+			entries = append(entries, commands[command])
+		}
+
+		return commands[command]
+	}
+
+	d.forEach(d.Projects, func(project *ProjectState) {
+		if project.Clone == CloneStatusFailed {
+			d.clone(project, entries[0])
+			return
+		}
+
+		if project.Run == RunStatusFailed {
+			d.run(project, project.lastCommand, runEntry(project.lastCommand))
+		}
+	})
+
+	for _, entry := range entries {
+		d.record(entry)
+	}
+}
+
+func (d *Dashboard) forEach(projects []*ProjectState, fn func(project *ProjectState)) {
+	var wg sync.WaitGroup
+	wg.Add(len(projects))
+
+	for _, project := range projects {
+		go func(project *ProjectState) {
+			defer wg.Done()
+			fn(project)
+		}(project)
+	}
+
+	wg.Wait()
+}
+
+// ReadState calls fn while the state of the projects can not change, so the view can read a consistent state.
+func (d *Dashboard) ReadState(fn func()) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	fn()
+}
+
+func (d *Dashboard) setState(project *ProjectState, update func()) {
+	d.mutex.Lock()
+	update()
+	d.mutex.Unlock()
+
+	d.OnChange(project)
+}
+
+// newHistoryEntry creates the entry recording a clone or a command run in the projects of the group.
+func (d *Dashboard) newHistoryEntry(kind string, command string) *pkg.HistoryEntry {
+	entry := pkg.NewHistoryEntry(kind, d.Group)
+	entry.Group = d.Group
+	entry.Command = command
+
+	return entry
+}
+
+func (d *Dashboard) record(entry *pkg.HistoryEntry) {
+	if d.Record != nil && len(entry.Projects) != 0 {
+		d.Record(entry)
+	}
+}
+
+func (d *Dashboard) clone(project *ProjectState, entry *pkg.HistoryEntry) {
+	project.resetOutput()
+	d.setState(project, func() {
+		project.Clone = CloneStatusCloning
+		project.Err = nil
+	})
+
+	clone := pkg.Clone{Project: project.Name, Path: d.ClonePath(project.Name)}
+
+	config := d.projectService.GetProject(project.Name)
+	if config == nil {
+		err := fmt.Errorf("project '%s' does not exist in configuration", project.Name)
+		entry.AddProject(clone, pkg.HistoryStatusFailed, "", err)
+		d.fail(project, err)
+		return
+	}
+
+	if err := d.repoService.PullProject(clone.Path, config); err != nil {
+		_ = os.RemoveAll(clone.Path)
+		entry.AddProject(clone, pkg.HistoryStatusFailed, "", err)
+		d.fail(project, err)
+		return
+	}
+
+	entry.AddProject(clone, pkg.HistoryStatusSucceeded, "", nil)
+	d.setState(project, func() {
+		project.Clone = CloneStatusCloned
+	})
+}
+
+func (d *Dashboard) run(project *ProjectState, command string, entry *pkg.HistoryEntry) {
+	project.resetOutput()
+	d.setState(project, func() {
+		project.Run = RunStatusRunning
+		project.lastCommand = command
+		project.Err = nil
+	})
+
+	name, args, _ := pkg.ParseCommand(command)
+	cmd := exec.Command(name, args...)
+	cmd.Dir = d.ClonePath(project.Name)
+	output := &changeWriter{writer: project, onWrite: func() { d.OnChange(project) }}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err := cmd.Run()
+	entry.AddProject(pkg.Clone{Project: project.Name, Path: cmd.Dir}, pkg.HistoryStatusSucceeded, "", err)
+	if err != nil {
+		d.setState(project, func() {
+			project.Run = RunStatusFailed
+			project.Err = err
+		})
+		return
+	}
+
+	d.setState(project, func() {
+		project.Run = RunStatusSucceeded
+	})
+}
+
+func (d *Dashboard) fail(project *ProjectState, err error) {
+	_, _ = fmt.Fprintln(project, err)
+
+	d.setState(project, func() {
+		project.Clone = CloneStatusFailed
+		project.Err = err
+	})
+}
+
+// changeWriter calls onWrite after every write, so output can be streamed to the view.
+type changeWriter struct {
+	writer  io.Writer
+	onWrite func()
+}
+
+func (w *changeWriter) Write(data []byte) (int, error) {
+	n, err := w.writer.Write(data)
+	w.onWrite()
+
+	return n, err
+}
@@ -0,0 +1,341 @@
+package pkg
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HistoryFileVariable is the environment variable overriding the file the runs are recorded in.
+const HistoryFileVariable = "WILDFIRE_HISTORY"
+
+type HistoryStatus string
+
+const (
+	HistoryStatusSucceeded HistoryStatus = "succeeded"
+	HistoryStatusChanged   HistoryStatus = "changed"
+	HistoryStatusSkipped   HistoryStatus = "skipped"
+	HistoryStatusFailed    HistoryStatus = "failed"
+)
+
+// HistoryProject is the outcome of a run for a project. ExitCode is the exit status of the command run in the
+// clone, 1 when the project failed without a command exiting. Commit is the commit checked out in the clone once the
+// run completed.
+type HistoryProject struct {
+	Project  string        `json:"project"`
+	Path     string        `json:"path,omitempty"`
+	Status   HistoryStatus `json:"status"`
+	ExitCode int           `json:"exit_code"`
+	Message  string        `json:"message,omitempty"`
+	Commit   string        `json:"commit,omitempty"`
+}
+
+// HistoryEntry records a run of a command on the projects of a group or workspace. Args are the arguments the
+// command has been run with from Dir, used to run it again.
+type HistoryEntry struct {
+	ID         string           `json:"id"`
+	Time       time.Time        `json:"time"`
+	User       string           `json:"user"`
+	Kind       string           `json:"kind"`
+	Target     string           `json:"target"`
+	Group      string           `json:"group,omitempty"`
+	Command    string           `json:"command,omitempty"`
+	Dir        string           `json:"dir"`
+	ConfigFile string           `json:"config_file,omitempty"`
+	Args       []string         `json:"args"`
+	Projects   []HistoryProject `json:"projects"`
+
+	mutex sync.Mutex
+}
+
+// NewHistoryEntry creates the entry of a run started now by the current user.
+func NewHistoryEntry(kind string, target string) *HistoryEntry {
+	id := make([]byte, 6)
+	_, _ = rand.Read(id)
+
+	username := os.Getenv("USER")
+	if current, err := user.Current(); err == nil {
+		username = current.Username
+	}
+
+	return &HistoryEntry{
+		ID:       hex.EncodeToString(id),
+		Time:     time.Now(),
+		User:     username,
+		Kind:     kind,
+		Target:   target,
+		Projects: []HistoryProject{},
+	}
+}
+
+// AddProject records the outcome of the run for the project of the clone and returns it. The status is failed when
+// err is set. It is safe for concurrent use.
+func (e *HistoryEntry) AddProject(clone Clone, status HistoryStatus, message string, err error) HistoryProject {
+	project := HistoryProject{Project: clone.Project, Path: clone.Path, Status: status, Message: message}
+
+	if err != nil {
+		project.Status = HistoryStatusFailed
+		project.ExitCode = 1
+		project.Message = err.Error()
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			project.ExitCode = exitErr.ExitCode()
+		}
+	}
+
+	if clone.Path != "" && project.Status != HistoryStatusSkipped {
+		if commit, err := gitOutput(clone.Path, "rev-parse", "HEAD"); err == nil {
+			project.Commit = strings.TrimSpace(commit)
+		}
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.Projects = append(e.Projects, project)
+
+	return project
+}
+
+// GetProjects returns the projects which had the status, every project when status is empty.
+func (e *HistoryEntry) GetProjects(status HistoryStatus) []string {
+	var res []string
+	for _, project := range e.Projects {
+		if status == "" || project.Status == status {
+			res = append(res, project.Project)
+		}
+	}
+
+	return res
+}
+
+// HistoryStore appends the runs to a file containing a JSON entry per line.
+type HistoryStore struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func NewHistoryStore(path string) *HistoryStore {
+	return &HistoryStore{path: path}
+}
+
+// DefaultHistoryPath returns the file set with the WILDFIRE_HISTORY environment variable, or history.jsonl next to
+// the global configuration. It is empty when there is no user configuration directory.
+func DefaultHistoryPath() string {
+	if path := os.Getenv(HistoryFileVariable); path != "" {
+		return path
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(configDir, "wildfire", "history.jsonl")
+}
+
+func (s *HistoryStore) Path() string {
+	return s.path
+}
+
+// Append adds the entry to the end of the history.
+func (s *HistoryStore) Append(entry *HistoryEntry) error {
+	entry.mutex.Lock()
+	data, err := json.Marshal(entry)
+	entry.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// List returns the entries of the history, oldest first. A missing history is empty.
+func (s *HistoryStore) List() ([]*HistoryEntry, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var res []*HistoryEntry
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		entry := &HistoryEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, fmt.Errorf("invalid entry on line %d of history '%s': %s", line, s.path, err)
+		}
+		res = append(res, entry)
+	}
+
+	return res, scanner.Err()
+}
+
+// Get returns the entry whose ID is, or uniquely starts with, id.
+func (s *HistoryStore) Get(id string) (*HistoryEntry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var res *HistoryEntry
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+
+		if id != "" && strings.HasPrefix(entry.ID, id) {
+			if res != nil {
+				return nil, fmt.Errorf("run id '%s' is ambiguous", id)
+			}
+			res = entry
+		}
+	}
+
+	if res == nil {
+		return nil, fmt.Errorf("run '%s' does not exist in history", id)
+	}
+
+	return res, nil
+}
+
+type historyKey struct{}
+
+type historyRecorder struct {
+	store *HistoryStore
+	args  []string
+}
+
+// WithHistory returns a context in which the runs of the commands are recorded in the store. Args are the arguments
+// the commands have been run with.
+func WithHistory(ctx context.Context, store *HistoryStore, args []string) context.Context {
+	return context.WithValue(ctx, historyKey{}, historyRecorder{store: store, args: append([]string{}, args...)})
+}
+
+// GetHistoryStore returns the store of the context, nil when runs are not recorded.
+func GetHistoryStore(ctx context.Context) *HistoryStore {
+	if ctx != nil {
+		if recorder, ok := ctx.Value(historyKey{}).(historyRecorder); ok {
+			return recorder.store
+		}
+	}
+
+	return nil
+}
+
+// RecordHistory completes the entry with the arguments, directory and configuration file of the command and
+// appends it to the history of the context. Nothing is recorded when the context has no history.
+func RecordHistory(ctx context.Context, entry *HistoryEntry) error {
+	if ctx == nil {
+		return nil
+	}
+
+	recorder, ok := ctx.Value(historyKey{}).(historyRecorder)
+	if !ok {
+		return nil
+	}
+
+	entry.Args = recorder.args
+	entry.ConfigFile = GetConfigStore(ctx).Path()
+	if dir, err := os.Getwd(); err == nil {
+		entry.Dir = dir
+	}
+
+	return recorder.store.Append(entry)
+}
+
+type projectSelectionKey struct{}
+
+// WithProjectSelection returns a context in which the commands only run on the projects, used to run a command
+// again for some of the projects it ran on.
+func WithProjectSelection(ctx context.Context, projects []string) context.Context {
+	return context.WithValue(ctx, projectSelectionKey{}, append([]string{}, projects...))
+}
+
+// SelectProjects returns the projects selected with WithProjectSelection, every project when there is no selection.
+func SelectProjects(ctx context.Context, projects []string) []string {
+	if ctx == nil {
+		return projects
+	}
+
+	selection, ok := ctx.Value(projectSelectionKey{}).([]string)
+	if !ok {
+		return projects
+	}
+
+	var res []string
+	for _, project := range projects {
+		for _, selected := range selection {
+			if project == selected {
+				res = append(res, project)
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// SelectClones returns the clones of the projects selected with WithProjectSelection, every clone when there is no
+// selection.
+func SelectClones(ctx context.Context, clones []Clone) []Clone {
+	var projects []string
+	for _, clone := range clones {
+		projects = append(projects, clone.Project)
+	}
+
+	selected := map[string]bool{}
+	for _, project := range SelectProjects(ctx, projects) {
+		selected[project] = true
+	}
+
+	var res []Clone
+	for _, clone := range clones {
+		if selected[clone.Project] {
+			res = append(res, clone)
+		}
+	}
+
+	return res
+}
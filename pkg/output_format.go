@@ -0,0 +1,21 @@
+package pkg
+
+type OutputFormat string
+
+const (
+	OutputFormatTable OutputFormat = "table"
+	OutputFormatJSON  OutputFormat = "json"
+)
+
+func (f *OutputFormat) ValidFormat() bool {
+	_, ok := f.GetAvailableFormats()[*f]
+
+	return ok
+}
+
+func (f OutputFormat) GetAvailableFormats() map[OutputFormat]OutputFormat {
+	return map[OutputFormat]OutputFormat{
+		OutputFormatTable: OutputFormatTable,
+		OutputFormatJSON:  OutputFormatJSON,
+	}
+}
@@ -0,0 +1,224 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+type PatchStatus string
+
+const (
+	PatchStatusApplied        PatchStatus = "applied"
+	PatchStatusApplicable     PatchStatus = "applies"
+	PatchStatusAlreadyApplied PatchStatus = "already applied"
+	PatchStatusConflict       PatchStatus = "conflict"
+	PatchStatusFailed         PatchStatus = "failed"
+)
+
+// PatchResult is the outcome of applying a patch to a clone. ThreeWay is set when the patch did not apply cleanly
+// and a 3-way merge with the blobs recorded in the patch was used instead.
+type PatchResult struct {
+	ExecResult
+	Status   PatchStatus
+	ThreeWay bool
+}
+
+// ApplyPatchToClones applies the patch file, either a unified diff or a git format-patch series, in parallel to every
+// clone which matches the predicates. With check the clones are left unchanged and only whether the patch applies is
+// reported. The returned results are in the order of the clones.
+func ApplyPatchToClones(clones []Clone, predicates []ClonePredicate, patchFile string, check bool) ([]PatchResult, error) {
+	data, err := ioutil.ReadFile(patchFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read patch '%s': %s", patchFile, err)
+	}
+
+	series := IsPatchSeries(data)
+	res := make([]PatchResult, len(clones))
+
+	var wg sync.WaitGroup
+	wg.Add(len(clones))
+
+	for i, clone := range clones {
+		res[i].Clone = clone
+
+		go func(result *PatchResult) {
+			defer wg.Done()
+
+			if filterClone(&result.ExecResult, predicates) == false {
+				return
+			}
+
+			applyPatch(result, patchFile, series, check)
+			if result.Status == PatchStatusConflict || result.Status == PatchStatusFailed {
+				result.Err = fmt.Errorf("patch %s: %s", result.Status, strings.TrimSpace(result.Output))
+			}
+		}(&res[i])
+	}
+
+	wg.Wait()
+
+	return res, nil
+}
+
+// IsPatchSeries reports whether the patch has been created by git format-patch.
+func IsPatchSeries(patch []byte) bool {
+	return bytes.HasPrefix(patch, []byte("From ")) && bytes.Contains(patch, []byte("\nSubject: "))
+}
+
+func applyPatch(result *PatchResult, patchFile string, series bool, check bool) {
+	if _, err := runGit(result.Path, "apply", "--check", "--reverse", patchFile); err == nil {
+		result.Status = PatchStatusAlreadyApplied
+		return
+	}
+
+	output, err := runGit(result.Path, "apply", "--check", patchFile)
+	if err != nil {
+		result.ThreeWay = true
+
+		// A 3-way check succeeds even if the merge conflicts, which is only reported in the output. Conflicts are
+		// left in the clone to be resolved when the patch is applied.
+		output, err = runGit(result.Path, "apply", "--check", "--3way", patchFile)
+		if err != nil || (check && strings.Contains(output, "with conflicts")) {
+			result.Status, result.Output = conflictStatus(output), output
+			return
+		}
+	}
+
+	if check {
+		result.Status = PatchStatusApplicable
+		return
+	}
+
+	switch {
+	case series:
+		output, err = runGit(result.Path, "am", "--3way", patchFile)
+		if err != nil && hasUnmergedFiles(result.Path) == false {
+			_, _ = runGit(result.Path, "am", "--abort")
+		} else if err != nil {
+			output = strings.TrimSpace(output) + "\nResolve the conflicts and run 'git am --continue' or 'git am --abort'."
+		}
+	case result.ThreeWay:
+		output, err = runGit(result.Path, "apply", "--3way", patchFile)
+		if err != nil && hasUnmergedFiles(result.Path) {
+			output = strings.TrimSpace(output) + "\nResolve the conflicts in the clone."
+		}
+	default:
+		output, err = runGit(result.Path, "apply", patchFile)
+	}
+
+	result.Output = output
+	if err != nil {
+		result.Status = conflictStatus(output)
+		if hasUnmergedFiles(result.Path) {
+			result.Status = PatchStatusConflict
+		}
+
+		return
+	}
+
+	result.Status = PatchStatusApplied
+}
+
+func conflictStatus(output string) PatchStatus {
+	if strings.Contains(output, "with conflicts") {
+		return PatchStatusConflict
+	}
+
+	return PatchStatusFailed
+}
+
+func hasUnmergedFiles(dir string) bool {
+	output, err := gitOutput(dir, "diff", "--name-only", "--diff-filter=U")
+
+	return err == nil && strings.TrimSpace(output) != ""
+}
+
+// CapturePatch returns the changes of the clone compared to HEAD as a unified diff, including the files which are not
+// tracked yet. With since the commits made after the revision are returned as a git format-patch series instead.
+func CapturePatch(dir string, since string) (string, error) {
+	if since != "" {
+		return gitOutput(dir, "format-patch", "--stdout", "--binary", since)
+	}
+
+	patch, err := gitOutput(dir, "diff", "--binary", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	untracked, err := gitOutput(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return "", err
+	}
+
+	for _, file := range strings.Split(untracked, "\x00") {
+		if file == "" {
+			continue
+		}
+
+		// git diff --no-index exits with status 1 when the files differ.
+		diff, err := gitOutput(dir, "diff", "--binary", "--no-index", "--", "/dev/null", file)
+		var exitErr *exec.ExitError
+		if err != nil && (errors.As(err, &exitErr) == false || exitErr.ExitCode() != 1) {
+			return "", err
+		}
+
+		patch += diff
+	}
+
+	return patch, nil
+}
+
+// runGit runs git in the directory and returns its output, including the messages written to stderr. On failure
+// the error contains the messages of git.
+func runGit(dir string, args ...string) (string, error) {
+	stdout, stderr, err := execGit(dir, args...)
+
+	return stdout + stderr, err
+}
+
+// gitOutput runs git in the directory and returns what it writes to stdout.
+func gitOutput(dir string, args ...string) (string, error) {
+	stdout, _, err := execGit(dir, args...)
+
+	return stdout, err
+}
+
+func execGit(dir string, args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = &gitError{ExitError: exitErr, output: strings.TrimSpace(stderr.String())}
+	}
+
+	return stdout.String(), stderr.String(), err
+}
+
+type gitError struct {
+	*exec.ExitError
+	output string
+}
+
+func (e *gitError) Error() string {
+	if e.output == "" {
+		return e.ExitError.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", e.ExitError, e.output)
+}
+
+func (e *gitError) Unwrap() error {
+	return e.ExitError
+}
@@ -0,0 +1,115 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// PluginPrefix is the prefix of the executables on PATH which are available as wildfire subcommands.
+const PluginPrefix = "wildfire-"
+
+// PluginAction runs an executable named wildfire-<name> as an action. The executable receives the arguments of the
+// action and the action context as JSON on stdin, and the WILDFIRE_CONFIG and WILDFIRE_PLUGIN environment variables.
+// It writes its results to stdout as a JSON array of ActionResult. What it writes to stderr is passed through.
+type PluginAction struct {
+	PluginName string
+	Path       string
+	Stderr     io.Writer
+}
+
+// DiscoverPlugins returns the plugin executables found in the directories of pathList, a list separated like PATH.
+// When a plugin exists in more than one directory the first one is used.
+func DiscoverPlugins(pathList string) []*PluginAction {
+	var res []*PluginAction
+	found := map[string]bool{}
+
+	for _, dir := range filepath.SplitList(pathList) {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			name, executable := pluginExecutable(file)
+			if strings.HasPrefix(name, PluginPrefix) == false || name == PluginPrefix || file.IsDir() {
+				continue
+			}
+			if executable == false || found[name] {
+				continue
+			}
+
+			found[name] = true
+			res = append(res, &PluginAction{
+				PluginName: strings.TrimPrefix(name, PluginPrefix),
+				Path:       filepath.Join(dir, file.Name()),
+			})
+		}
+	}
+
+	return res
+}
+
+// pluginExecutable returns the name of the command of the file and whether it is executable. On Windows executables
+// are recognized by their extension, which is not part of the name of the command.
+func pluginExecutable(file os.FileInfo) (string, bool) {
+	if runtime.GOOS != "windows" {
+		return file.Name(), file.Mode()&0111 != 0
+	}
+
+	extension := filepath.Ext(file.Name())
+	switch strings.ToLower(extension) {
+	case ".exe", ".bat", ".cmd":
+		return strings.TrimSuffix(file.Name(), extension), true
+	default:
+		return file.Name(), false
+	}
+}
+
+func (p *PluginAction) Name() string {
+	return p.PluginName
+}
+
+func (p *PluginAction) Description() string {
+	return fmt.Sprintf("Run the %s plugin (%s)", p.PluginName, p.Path)
+}
+
+func (p *PluginAction) Run(context ActionContext) ([]ActionResult, error) {
+	input, err := json.Marshal(context)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+
+	cmd := exec.Command(p.Path, context.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = p.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	cmd.Env = append(os.Environ(), "WILDFIRE_CONFIG="+context.ConfigFile, "WILDFIRE_PLUGIN="+p.PluginName)
+
+	runErr := cmd.Run()
+
+	var results []ActionResult
+	if output := bytes.TrimSpace(stdout.Bytes()); len(output) != 0 {
+		if err := json.Unmarshal(output, &results); err != nil {
+			return nil, fmt.Errorf("plugin '%s' returned invalid results: %s", p.PluginName, err)
+		}
+	}
+
+	if runErr != nil {
+		return results, fmt.Errorf("plugin '%s' failed: %s", p.PluginName, runErr)
+	}
+
+	return results, nil
+}
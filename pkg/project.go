@@ -3,6 +3,8 @@ package pkg
 import (
 	"errors"
 	"fmt"
+	"os"
+	"sort"
 )
 
 type ProjectService interface {
@@ -10,7 +12,11 @@ type ProjectService interface {
 	RemoveProject(name string)
 	HasProject(name string) bool
 	GetProject(name string) *ProjectConfig
+	GetProjectNames() []string
+	FilterProjects(filter ProjectFilter) ([]*ProjectConfig, error)
 	UpdateOrCreate(project *ProjectConfig)
+	RenameProject(oldName string, newName string, dryRun bool) error
+	PlanRenameProject(oldName string, newName string) ([]PlannedChange, error)
 }
 
 type Project struct {
@@ -56,3 +62,176 @@ func (p *Project) GetProject(name string) *ProjectConfig {
 func (p *Project) UpdateOrCreate(project *ProjectConfig) {
 	p.Config.Projects[project.Name] = project
 }
+
+func (p *Project) GetProjectNames() []string {
+	var res []string
+
+	for name := range p.Config.Projects {
+		res = append(res, name)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func (p *Project) FilterProjects(filter ProjectFilter) ([]*ProjectConfig, error) {
+	groupService := NewGroupService(p.Config)
+	var res []*ProjectConfig
+
+	groupProjects := map[string]bool{}
+	for _, groupName := range filter.Groups {
+		projects, err := groupService.GetGroupProjects(groupName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, projectName := range projects {
+			groupProjects[projectName] = true
+		}
+	}
+
+	for _, name := range p.GetProjectNames() {
+		project := p.Config.Projects[name]
+
+		matched, err := filter.matchesName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matched || !filter.matchesType(project) || !filter.matchesLabels(project) {
+			continue
+		}
+
+		if len(filter.Groups) != 0 && groupProjects[name] == false {
+			continue
+		}
+
+		res = append(res, project)
+	}
+
+	return res, nil
+}
+
+// RenameProject renames the project and updates every group, workspace and project dependency which references it.
+// The workspace of the project cloned on its own is renamed with it. Existing clones of the project in workspaces are
+// moved to the new clone path, with dryRun they are left in place, see PlanRenameProject.
+func (p *Project) RenameProject(oldName string, newName string, dryRun bool) error {
+	project := p.GetProject(oldName)
+	if project == nil {
+		return fmt.Errorf("project with name '%s' does not exist", oldName)
+	}
+
+	if p.HasProject(newName) {
+		return fmt.Errorf("project with name '%s' already exists", newName)
+	}
+
+	if err := p.Config.renameSource("projects", oldName, newName); err != nil {
+		return err
+	}
+
+	workspaceService := NewWorkspaceService(p.Config)
+	if err := p.moveClones(workspaceService, oldName, newName, dryRun); err != nil {
+		_ = p.Config.renameSource("projects", newName, oldName)
+		return err
+	}
+
+	delete(p.Config.Projects, oldName)
+	project.Name = newName
+	p.Config.Projects[newName] = project
+
+	for _, group := range p.Config.Groups {
+		for index, projectName := range group.Projects {
+			if projectName == oldName {
+				group.Projects[index] = newName
+			}
+		}
+	}
+
+	for _, dependent := range p.Config.Projects {
+		for index, dependency := range dependent.DependsOn {
+			if dependency == oldName {
+				dependent.DependsOn[index] = newName
+			}
+		}
+	}
+
+	for _, workspaceName := range workspaceService.GetProjectWorkspaces(oldName) {
+		workspace := workspaceService.GetWorkspace(workspaceName)
+		for index, projectName := range workspace.Projects {
+			if projectName == oldName {
+				workspace.Projects[index] = newName
+			}
+		}
+	}
+
+	oldWorkspace, newWorkspace := ProjectWorkspaceName(oldName), ProjectWorkspaceName(newName)
+	if workspace := workspaceService.GetWorkspace(oldWorkspace); workspace != nil && workspaceService.GetWorkspace(newWorkspace) == nil {
+		if err := p.Config.renameSource("workspaces", oldWorkspace, newWorkspace); err != nil {
+			return err
+		}
+
+		workspaceService.DeleteWorkspace(oldWorkspace)
+		workspaceService.SetWorkspace(newWorkspace, workspace)
+	}
+
+	return nil
+}
+
+// PlanRenameProject returns the moves of the clones of the project renaming it would make, see RenameProject.
+func (p *Project) PlanRenameProject(oldName string, newName string) ([]PlannedChange, error) {
+	return p.cloneMoves(NewWorkspaceService(p.Config), oldName, newName)
+}
+
+// cloneMoves returns the moves of the clones of the project in all workspaces to the clone path of the new name. An
+// error is returned if a clone can not be moved because the new path already exists.
+func (p *Project) cloneMoves(workspaceService WorkspaceService, oldName string, newName string) ([]PlannedChange, error) {
+	var res []PlannedChange
+
+	for _, workspaceName := range workspaceService.GetProjectWorkspaces(oldName) {
+		workspace := workspaceService.GetWorkspace(workspaceName)
+		oldPath := workspaceService.GetClonePath(workspace, oldName)
+		newPath := workspaceService.GetClonePath(workspace, newName)
+
+		if _, err := os.Stat(oldPath); os.IsNotExist(err) {
+			continue
+		}
+
+		if _, err := os.Stat(newPath); err == nil {
+			return nil, fmt.Errorf("can not move clone '%s', path '%s' already exists", oldPath, newPath)
+		}
+
+		res = append(res, PlannedChange{Clone: Clone{Project: oldName, Path: oldPath}, Action: PlannedActionMove, Detail: newPath})
+	}
+
+	return res, nil
+}
+
+// moveClones moves the clones of the project in all workspaces to the clone path of the new name. If a clone can
+// not be moved the already moved clones are moved back. With dryRun the clones are left in place.
+func (p *Project) moveClones(workspaceService WorkspaceService, oldName string, newName string, dryRun bool) error {
+	moves, err := p.cloneMoves(workspaceService, oldName, newName)
+	if err != nil || dryRun {
+		return err
+	}
+
+	var moved []PlannedChange
+	for _, move := range moves {
+		if err := os.Rename(move.Path, move.Detail); err != nil {
+			RestoreClones(moved)
+			return err
+		}
+
+		moved = append(moved, move)
+	}
+
+	return nil
+}
+
+// RestoreClones moves the clones moved by a rename back to their previous path, see PlanRenameProject. It is used
+// when the renamed configuration can not be saved.
+func RestoreClones(moves []PlannedChange) {
+	for _, move := range moves {
+		_ = os.Rename(move.Detail, move.Path)
+	}
+}
@@ -1,7 +1,20 @@
 package pkg
 
 type ProjectConfig struct {
-	Name string
-	Type ProjectType
-	URL  ProjectPath
+	Name   string      `json:"name"`
+	Type   ProjectType `json:"type"`
+	URL    ProjectPath `json:"url"`
+	Labels []string    `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// DependsOn are the names of the projects a command has to succeed in before it runs in the project.
+	DependsOn []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty" mapstructure:"depends_on"`
+}
+
+func (p *ProjectConfig) HasLabel(label string) bool {
+	for _, projectLabel := range p.Labels {
+		if projectLabel == label {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,170 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ProjectDependencies maps projects to the projects they depend on, see ProjectConfig.DependsOn.
+type ProjectDependencies map[string][]string
+
+// GetProjectDependencies returns the dependencies of the projects on each other. A project depending on a project
+// which is not part of projects depends on the dependencies of that project instead, so projects depending on each
+// other through other projects keep their order.
+func GetProjectDependencies(config *WildFireConfig, projects []string) ProjectDependencies {
+	included := map[string]bool{}
+	for _, project := range projects {
+		included[project] = true
+	}
+
+	res := ProjectDependencies{}
+	for _, project := range projects {
+		visited := map[string]bool{project: true}
+
+		var visit func(name string)
+		visit = func(name string) {
+			projectConfig := config.Projects[name]
+			if projectConfig == nil {
+				return
+			}
+
+			for _, dependency := range projectConfig.DependsOn {
+				if visited[dependency] {
+					continue
+				}
+				visited[dependency] = true
+
+				if included[dependency] {
+					res[project] = appendMissingName(res[project], dependency)
+				} else {
+					visit(dependency)
+				}
+			}
+		}
+		visit(project)
+	}
+
+	return res
+}
+
+// Cycle returns projects which depend on each other, starting and ending with the same project, nil when there is no
+// cycle.
+func (d ProjectDependencies) Cycle() []string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := map[string]int{}
+	var path []string
+
+	var visit func(project string) []string
+	visit = func(project string) []string {
+		state[project] = visiting
+		path = append(path, project)
+
+		for _, dependency := range d[project] {
+			switch state[dependency] {
+			case visiting:
+				for index, name := range path {
+					if name == dependency {
+						return append(append([]string{}, path[index:]...), dependency)
+					}
+				}
+			case 0:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[project] = visited
+
+		return nil
+	}
+
+	projects := make([]string, 0, len(d))
+	for project := range d {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	for _, project := range projects {
+		if state[project] == 0 {
+			if cycle := visit(project); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// Sort returns the projects ordered so every project follows the projects it depends on. Projects otherwise keep
+// their order. An error is returned when projects depend on each other.
+func (d ProjectDependencies) Sort(projects []string) ([]string, error) {
+	if cycle := d.Cycle(); cycle != nil {
+		return nil, &DependencyCycleError{Cycle: cycle}
+	}
+
+	included := map[string]bool{}
+	for _, project := range projects {
+		included[project] = true
+	}
+
+	var res []string
+	added := map[string]bool{}
+
+	var add func(project string)
+	add = func(project string) {
+		if added[project] {
+			return
+		}
+		added[project] = true
+
+		for _, dependency := range d[project] {
+			if included[dependency] {
+				add(dependency)
+			}
+		}
+		res = append(res, project)
+	}
+
+	for _, project := range projects {
+		add(project)
+	}
+
+	return res, nil
+}
+
+// DependencyCycleError is returned when projects depend on each other.
+type DependencyCycleError struct {
+	Cycle []string
+}
+
+func (e *DependencyCycleError) Error() string {
+	return fmt.Sprintf("projects depend on each other: %s", strings.Join(e.Cycle, " -> "))
+}
+
+func appendMissingName(names []string, name string) []string {
+	for _, existing := range names {
+		if existing == name {
+			return names
+		}
+	}
+
+	return append(names, name)
+}
+
+func removeName(names []string, name string) []string {
+	var res []string
+	for _, existing := range names {
+		if existing != name {
+			res = append(res, existing)
+		}
+	}
+
+	return res
+}
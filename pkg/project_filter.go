@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+type ProjectSortField string
+
+const (
+	ProjectSortByName ProjectSortField = "name"
+	ProjectSortByType ProjectSortField = "type"
+	ProjectSortByURL  ProjectSortField = "url"
+)
+
+func (f *ProjectSortField) ValidField() bool {
+	_, ok := f.GetAvailableFields()[*f]
+
+	return ok
+}
+
+func (f ProjectSortField) GetAvailableFields() map[ProjectSortField]ProjectSortField {
+	return map[ProjectSortField]ProjectSortField{
+		ProjectSortByName: ProjectSortByName,
+		ProjectSortByType: ProjectSortByType,
+		ProjectSortByURL:  ProjectSortByURL,
+	}
+}
+
+// ProjectFilter describes which projects should be returned by ProjectService.FilterProjects.
+// Empty fields are not used when filtering. A project has to match every non-empty field.
+type ProjectFilter struct {
+	Types       []ProjectType
+	Labels      []string
+	NamePattern string
+	Groups      []string
+}
+
+func (f ProjectFilter) matchesType(project *ProjectConfig) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+
+	for _, projectType := range f.Types {
+		if project.Type == projectType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f ProjectFilter) matchesLabels(project *ProjectConfig) bool {
+	for _, label := range f.Labels {
+		if project.HasLabel(label) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f ProjectFilter) matchesName(name string) (bool, error) {
+	if f.NamePattern == "" {
+		return true, nil
+	}
+
+	matched, err := path.Match(f.NamePattern, name)
+	if err != nil {
+		return false, fmt.Errorf("invalid name pattern '%s'", f.NamePattern)
+	}
+
+	return matched, nil
+}
+
+func SortProjects(projects []*ProjectConfig, field ProjectSortField) {
+	value := func(project *ProjectConfig) string {
+		switch field {
+		case ProjectSortByType:
+			return string(project.Type)
+		case ProjectSortByURL:
+			return string(project.URL)
+		default:
+			return project.Name
+		}
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		if value(projects[i]) == value(projects[j]) {
+			return projects[i].Name < projects[j].Name
+		}
+
+		return value(projects[i]) < value(projects[j])
+	})
+}
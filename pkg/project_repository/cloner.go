@@ -1,7 +1,9 @@
 package project_repository
 
 import (
+	"context"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"io"
 	"wildfire/pkg"
 )
@@ -10,8 +12,26 @@ type Cloner interface {
 	CloneProject(path string, project *pkg.ProjectConfig) error
 }
 
+// ContextCloner is a Cloner which stops cloning once the context is done.
+type ContextCloner interface {
+	Cloner
+	CloneProjectContext(ctx context.Context, path string, project *pkg.ProjectConfig) error
+}
+
+// CloneProjectContext clones the project with the cloner. The clone is stopped once the context is done when the
+// cloner is a ContextCloner.
+func CloneProjectContext(ctx context.Context, cloner Cloner, path string, project *pkg.ProjectConfig) error {
+	if contextCloner, ok := cloner.(ContextCloner); ok {
+		return contextCloner.CloneProjectContext(ctx, path, project)
+	}
+
+	return cloner.CloneProject(path, project)
+}
+
 type GitCloner struct {
 	Output io.Writer
+	// Branch is checked out instead of the default branch of the repository when set.
+	Branch string
 }
 
 func NewCloner(output io.Writer) Cloner {
@@ -21,10 +41,45 @@ func NewCloner(output io.Writer) Cloner {
 }
 
 func (g *GitCloner) CloneProject(path string, project *pkg.ProjectConfig) error {
-	_, err := git.PlainClone(path, false, &git.CloneOptions{
+	return g.CloneProjectContext(context.Background(), path, project)
+}
+
+func (g *GitCloner) CloneProjectContext(ctx context.Context, path string, project *pkg.ProjectConfig) error {
+	options := &git.CloneOptions{
 		URL:      string(project.URL),
 		Progress: g.Output,
-	})
+	}
+
+	if g.Branch != "" {
+		options.ReferenceName = plumbing.NewBranchReferenceName(g.Branch)
+	}
+
+	_, err := git.PlainCloneContext(ctx, path, false, options)
 
 	return err
 }
+
+// resolvingCloner substitutes the references in the URL of the projects before they are cloned.
+type resolvingCloner struct {
+	config *pkg.WildFireConfig
+	cloner Cloner
+}
+
+// NewResolvingCloner returns a cloner which substitutes the references in the URL of the projects with
+// pkg.WildFireConfig.ResolveProject before cloning them with cloner.
+func NewResolvingCloner(config *pkg.WildFireConfig, cloner Cloner) Cloner {
+	return &resolvingCloner{config: config, cloner: cloner}
+}
+
+func (r *resolvingCloner) CloneProject(path string, project *pkg.ProjectConfig) error {
+	return r.CloneProjectContext(context.Background(), path, project)
+}
+
+func (r *resolvingCloner) CloneProjectContext(ctx context.Context, path string, project *pkg.ProjectConfig) error {
+	resolved, err := r.config.ResolveProject(project)
+	if err != nil {
+		return err
+	}
+
+	return CloneProjectContext(ctx, r.cloner, path, resolved)
+}
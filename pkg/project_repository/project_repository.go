@@ -45,7 +45,7 @@ func (p *ProjectRepository) PullProject(path string, project *pkg.ProjectConfig)
 func (p *ProjectRepository) PullGroup(path string, group *pkg.GroupConfig) error {
 	wg := &sync.WaitGroup{}
 
-	wg.Add(len(*group))
+	wg.Add(len(group.Projects))
 
 	pullErrorsLock := &sync.Mutex{}
 	var pullErrors []error
@@ -56,7 +56,7 @@ func (p *ProjectRepository) PullGroup(path string, group *pkg.GroupConfig) error
 		pullErrorsLock.Unlock()
 	}
 
-	for _, projectName := range *group {
+	for _, projectName := range group.Projects {
 		go func(projectName string) {
 			defer wg.Done()
 
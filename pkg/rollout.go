@@ -0,0 +1,145 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RolloutSize is the number of projects of a wave, either a count or a percentage of the projects of the rollout.
+type RolloutSize struct {
+	Count   int
+	Percent float64
+}
+
+// ParseRolloutSize parses a number of projects, N, or a percentage of the projects, N%.
+func ParseRolloutSize(value string) (RolloutSize, error) {
+	if strings.HasSuffix(value, "%") {
+		percent, err := ParsePercent(value)
+		if err != nil || percent == 0 {
+			return RolloutSize{}, fmt.Errorf("invalid percentage of projects '%s'", value)
+		}
+
+		return RolloutSize{Percent: percent}, nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		return RolloutSize{}, fmt.Errorf("invalid number of projects '%s'", value)
+	}
+
+	return RolloutSize{Count: count}, nil
+}
+
+// ParsePercent parses a percentage between 0 and 100, written with or without '%'.
+func ParsePercent(value string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("invalid percentage '%s'", value)
+	}
+
+	return percent, nil
+}
+
+// IsZero reports whether the size has not been set.
+func (s RolloutSize) IsZero() bool {
+	return s.Count == 0 && s.Percent == 0
+}
+
+// Of returns the number of projects out of total, rounded up so a wave contains at least one project.
+func (s RolloutSize) Of(total int) int {
+	res := s.Count
+	if s.Percent != 0 {
+		res = int(math.Ceil(float64(total) * s.Percent / 100))
+	}
+
+	if res > total {
+		return total
+	}
+	if res < 1 && total > 0 {
+		return 1
+	}
+
+	return res
+}
+
+func (s RolloutSize) String() string {
+	if s.Percent != 0 {
+		return strconv.FormatFloat(s.Percent, 'f', -1, 64) + "%"
+	}
+
+	return strconv.Itoa(s.Count)
+}
+
+// Rollout runs a command in waves: first in the canary wave, then in waves of WaveSize projects. The rollout stops
+// once the failure rate of a wave exceeds MaxFailureRate, a percentage.
+type Rollout struct {
+	Canary         RolloutSize
+	WaveSize       RolloutSize
+	MaxFailureRate float64
+}
+
+// RolloutWave is a wave of a rollout and the outcome of the command in its projects.
+type RolloutWave struct {
+	Number    int
+	Projects  []string
+	Succeeded int
+	Skipped   int
+	Failed    int
+}
+
+// Waves splits the projects into the waves of the rollout, in order. The canary wave defaults to the wave size.
+// Without a wave size the projects following the canary wave run in a single wave.
+func (r Rollout) Waves(projects []string) []*RolloutWave {
+	var res []*RolloutWave
+
+	size := r.Canary
+	if size.IsZero() {
+		size = r.WaveSize
+	}
+
+	for start := 0; start < len(projects); {
+		count := len(projects) - start
+		if size.IsZero() == false {
+			count = size.Of(len(projects))
+		}
+		if start+count > len(projects) {
+			count = len(projects) - start
+		}
+
+		res = append(res, &RolloutWave{Number: len(res) + 1, Projects: projects[start : start+count]})
+		start += count
+		size = r.WaveSize
+	}
+
+	return res
+}
+
+// AddResults counts the outcome of the command in the projects of the wave.
+func (w *RolloutWave) AddResults(results []ExecResult) {
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			w.Skipped++
+		case result.Err != nil:
+			w.Failed++
+		default:
+			w.Succeeded++
+		}
+	}
+}
+
+// FailureRate returns the percentage of the projects the command ran in for which it failed, 0 when it did not run.
+func (w *RolloutWave) FailureRate() float64 {
+	if w.Succeeded+w.Failed == 0 {
+		return 0
+	}
+
+	return float64(w.Failed) * 100 / float64(w.Succeeded+w.Failed)
+}
+
+// Exceeds reports whether the failure rate of the wave exceeds the maximum failure rate of the rollout.
+func (r Rollout) Exceeds(wave *RolloutWave) bool {
+	return wave.FailureRate() > r.MaxFailureRate
+}
@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// ProjectWorkspacePrefix prefixes the names of the workspaces created by cloning a single project, so they can not
+// clash with the workspaces named after the groups they have been cloned from.
+const ProjectWorkspacePrefix = "project:"
+
+// ProjectWorkspaceName returns the name of the workspace created by cloning the project on its own.
+func ProjectWorkspaceName(projectName string) string {
+	return ProjectWorkspacePrefix + projectName
+}
+
+type WorkspaceService interface {
+	GetWorkspace(name string) *WorkspaceConfig
+	FindWorkspace(name string) *WorkspaceConfig
+	GetWorkspaceNames() []string
+	SetWorkspace(name string, workspace *WorkspaceConfig)
+	DeleteWorkspace(name string)
+	GetProjectWorkspaces(projectName string) []string
+	GetClonePath(workspace *WorkspaceConfig, projectName string) string
+	GetClones(workspace *WorkspaceConfig) []Clone
+}
+
+type Workspace struct {
+	Config *WildFireConfig
+}
+
+func NewWorkspaceService(config *WildFireConfig) WorkspaceService {
+	return &Workspace{config}
+}
+
+func (w *Workspace) GetWorkspace(name string) *WorkspaceConfig {
+	return w.Config.Workspaces[name]
+}
+
+// FindWorkspace returns the workspace with the name or, if there is none, the first workspace cloned from the group
+// with the name.
+func (w *Workspace) FindWorkspace(name string) *WorkspaceConfig {
+	if workspace := w.GetWorkspace(name); workspace != nil {
+		return workspace
+	}
+
+	for _, workspaceName := range w.GetWorkspaceNames() {
+		if workspace := w.Config.Workspaces[workspaceName]; workspace != nil && workspace.Group == name {
+			return workspace
+		}
+	}
+
+	return nil
+}
+
+func (w *Workspace) GetWorkspaceNames() []string {
+	var res []string
+
+	for name := range w.Config.Workspaces {
+		res = append(res, name)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func (w *Workspace) SetWorkspace(name string, workspace *WorkspaceConfig) {
+	if w.Config.Workspaces == nil {
+		w.Config.Workspaces = make(map[string]*WorkspaceConfig)
+	}
+
+	w.Config.Workspaces[name] = workspace
+}
+
+func (w *Workspace) DeleteWorkspace(name string) {
+	delete(w.Config.Workspaces, name)
+}
+
+// GetProjectWorkspaces returns the names of the workspaces in which the project has been cloned.
+func (w *Workspace) GetProjectWorkspaces(projectName string) []string {
+	var res []string
+
+	for _, name := range w.GetWorkspaceNames() {
+		for _, workspaceProject := range w.Config.Workspaces[name].Projects {
+			if workspaceProject == projectName {
+				res = append(res, name)
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+func (w *Workspace) GetClonePath(workspace *WorkspaceConfig, projectName string) string {
+	return filepath.Join(workspace.Path, projectName)
+}
+
+// GetClones returns the clones of the projects of the workspace.
+func (w *Workspace) GetClones(workspace *WorkspaceConfig) []Clone {
+	var res []Clone
+
+	for _, projectName := range workspace.Projects {
+		res = append(res, Clone{Project: projectName, Path: w.GetClonePath(workspace, projectName)})
+	}
+
+	return res
+}
@@ -0,0 +1,7 @@
+package pkg
+
+type WorkspaceConfig struct {
+	Path     string   `json:"path"`
+	Group    string   `json:"group,omitempty" yaml:"group,omitempty"`
+	Projects []string `json:"projects"`
+}
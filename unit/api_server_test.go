@@ -0,0 +1,302 @@
+package unit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"wildfire/pkg"
+	"wildfire/pkg/api_server"
+	"wildfire/wildfire"
+)
+
+func newTestAPIServer(t *testing.T, token string) *httptest.Server {
+	return newRecordingTestAPIServer(t, token, nil)
+}
+
+func newRecordingTestAPIServer(t *testing.T, token string, history *pkg.HistoryStore) *httptest.Server {
+	workspace := t.TempDir()
+	for _, name := range []string{"foo", "bar"} {
+		_ = os.MkdirAll(filepath.Join(workspace, name), 0755)
+		writeTestFile(t, filepath.Join(workspace, name, "config.txt"), name)
+	}
+
+	store := pkg.NewMemoryConfigStore(&pkg.WildFireConfig{
+		Projects: map[string]*pkg.ProjectConfig{
+			"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/org/foo", Labels: []string{"go"}},
+			"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/org/bar"},
+		},
+		Groups: map[string]*pkg.GroupConfig{
+			"backend": {Description: "Backend services", Projects: []string{"foo", "bar"}},
+		},
+		Workspaces: map[string]*pkg.WorkspaceConfig{
+			"backend": {Path: workspace, Group: "backend", Projects: []string{"foo", "bar"}},
+		},
+	})
+
+	apiServer := api_server.NewServer(wildfire.NewClientFromStore(store), token)
+	apiServer.RecordRuns(history)
+	server := httptest.NewServer(apiServer)
+	t.Cleanup(func() {
+		server.Close()
+		apiServer.Close()
+	})
+
+	return server
+}
+
+func apiRequest(t *testing.T, server *httptest.Server, method string, path string, body string, value interface{}) int {
+	request, _ := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if method == http.MethodPost || method == http.MethodDelete {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response, err := server.Client().Do(request)
+	if err != nil {
+		t.Fatalf("Request %s %s failed. Error: %s", method, path, err)
+	}
+	defer response.Body.Close()
+
+	if value != nil {
+		if err := json.NewDecoder(response.Body).Decode(value); err != nil {
+			t.Fatalf("Failed to decode the response of %s %s. Error: %s", method, path, err)
+		}
+	}
+
+	return response.StatusCode
+}
+
+func waitForRun(t *testing.T, server *httptest.Server, id string) api_server.Run {
+	var run api_server.Run
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		apiRequest(t, server, http.MethodGet, "/api/runs/"+id, "", &run)
+		if run.Finished != nil {
+			return run
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Run '%s' did not finish. Last state %+v", id, run)
+
+	return run
+}
+
+func TestAPIServer(t *testing.T) {
+	t.Run("should list the projects matching the query", func(t *testing.T) {
+		server := newTestAPIServer(t, "")
+
+		var projects []pkg.ProjectConfig
+		if status := apiRequest(t, server, http.MethodGet, "/api/projects?label=go", "", &projects); status != http.StatusOK {
+			t.Fatalf("Invalid status. Expected 200 received %d", status)
+		}
+
+		if len(projects) != 1 || projects[0].Name != "foo" {
+			t.Errorf("Invalid projects. Received %+v", projects)
+		}
+	})
+
+	t.Run("should return the group", func(t *testing.T) {
+		server := newTestAPIServer(t, "")
+
+		var group api_server.GroupResponse
+		apiRequest(t, server, http.MethodGet, "/api/groups/backend", "", &group)
+
+		if group.Name != "backend" || group.Description != "Backend services" || len(group.Projects) != 2 {
+			t.Errorf("Invalid group. Received %+v", group)
+		}
+	})
+
+	t.Run("should return 404 for a group which does not exist", func(t *testing.T) {
+		server := newTestAPIServer(t, "")
+
+		if status := apiRequest(t, server, http.MethodGet, "/api/groups/unknown", "", nil); status != http.StatusNotFound {
+			t.Errorf("Invalid status. Expected 404 received %d", status)
+		}
+	})
+
+	t.Run("should require the token when one is set", func(t *testing.T) {
+		server := newTestAPIServer(t, "secret")
+
+		if status := apiRequest(t, server, http.MethodGet, "/api/groups", "", nil); status != http.StatusUnauthorized {
+			t.Errorf("Invalid status without token. Expected 401 received %d", status)
+		}
+
+		request, _ := http.NewRequest(http.MethodGet, server.URL+"/api/groups", nil)
+		request.Header.Set("Authorization", "Bearer secret")
+		response, err := server.Client().Do(request)
+		if err != nil {
+			t.Fatalf("Request failed. Error: %s", err)
+		}
+		_ = response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			t.Errorf("Invalid status with token. Expected 200 received %d", response.StatusCode)
+		}
+	})
+
+	t.Run("should reject the requests of other hosts and origins", func(t *testing.T) {
+		server := newTestAPIServer(t, "")
+
+		for _, header := range []map[string]string{
+			{"Host": "evil.example"},
+			{"Host": "evil.example:7420"},
+			{"Origin": "http://evil.example"},
+			{"Origin": "null"},
+		} {
+			request, _ := http.NewRequest(http.MethodGet, server.URL+"/api/groups", nil)
+			for name, value := range header {
+				request.Header.Set(name, value)
+			}
+			request.Host = request.Header.Get("Host")
+
+			response, err := server.Client().Do(request)
+			if err != nil {
+				t.Fatalf("Request failed. Error: %s", err)
+			}
+			_ = response.Body.Close()
+
+			if response.StatusCode != http.StatusForbidden {
+				t.Errorf("Invalid status for %v. Expected 403 received %d", header, response.StatusCode)
+			}
+		}
+
+		for _, host := range []string{"localhost:7420", "127.0.0.1", "[::1]:7420"} {
+			request, _ := http.NewRequest(http.MethodGet, server.URL+"/api/groups", nil)
+			request.Host = host
+			request.Header.Set("Origin", "http://"+host)
+
+			response, err := server.Client().Do(request)
+			if err != nil {
+				t.Fatalf("Request failed. Error: %s", err)
+			}
+			_ = response.Body.Close()
+
+			if response.StatusCode != http.StatusOK {
+				t.Errorf("Invalid status for host %s. Expected 200 received %d", host, response.StatusCode)
+			}
+		}
+	})
+
+	t.Run("should only start runs sent as JSON", func(t *testing.T) {
+		server := newTestAPIServer(t, "")
+
+		body := `{"type": "exec", "target": "backend", "command": ["touch", "created"]}`
+		response, err := server.Client().Post(server.URL+"/api/runs", "text/plain", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Request failed. Error: %s", err)
+		}
+		_ = response.Body.Close()
+
+		if response.StatusCode != http.StatusUnsupportedMediaType {
+			t.Errorf("Invalid status. Expected 415 received %d", response.StatusCode)
+		}
+
+		var runs []api_server.Run
+		if apiRequest(t, server, http.MethodGet, "/api/runs", "", &runs); len(runs) != 0 {
+			t.Errorf("No run should have been started. Received %+v", runs)
+		}
+	})
+
+	t.Run("should reject an invalid run", func(t *testing.T) {
+		server := newTestAPIServer(t, "")
+
+		for _, body := range []string{`{"type": "deploy", "target": "backend"}`, `{"type": "exec"}`, `{"type": "exec", "target": "backend", "where": ["invalid"]}`} {
+			if status := apiRequest(t, server, http.MethodPost, "/api/runs", body, nil); status != http.StatusBadRequest {
+				t.Errorf("Invalid status for %s. Expected 400 received %d", body, status)
+			}
+		}
+	})
+
+	t.Run("should run the command in the clones and report the results", func(t *testing.T) {
+		server := newTestAPIServer(t, "")
+
+		var run api_server.Run
+		body := `{"type": "exec", "target": "backend", "command": ["cat", "config.txt"]}`
+		if status := apiRequest(t, server, http.MethodPost, "/api/runs", body, &run); status != http.StatusAccepted {
+			t.Fatalf("Invalid status. Expected 202 received %d", status)
+		}
+
+		run = waitForRun(t, server, run.ID)
+		if run.Status != api_server.RunStatusSucceeded || len(run.Projects) != 2 {
+			t.Fatalf("Invalid run. Received %+v", run)
+		}
+
+		for _, result := range run.Projects {
+			if result.Status != wildfire.ProjectStatusSucceeded || result.Output != result.Project {
+				t.Errorf("Invalid result. Received %+v", result)
+			}
+		}
+	})
+
+	t.Run("should record the run in the history", func(t *testing.T) {
+		history := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+		server := newRecordingTestAPIServer(t, "", history)
+
+		var run api_server.Run
+		body := `{"type": "exec", "target": "backend", "command": ["cat", "missing.txt"], "where": ["file-exists:config.txt"]}`
+		apiRequest(t, server, http.MethodPost, "/api/runs", body, &run)
+		waitForRun(t, server, run.ID)
+
+		entries, err := history.List()
+		if err != nil || len(entries) != 1 {
+			t.Fatalf("The run should have been recorded. Error: %v Received %+v", err, entries)
+		}
+
+		entry := entries[0]
+		args := []string{"exec", "backend", "--where", "file-exists:config.txt", "--", "cat", "missing.txt"}
+		if entry.Kind != "exec" || entry.Group != "backend" || !reflect.DeepEqual(entry.Args, args) {
+			t.Errorf("Invalid entry. Received %+v", entry)
+		}
+		if failed := entry.GetProjects(pkg.HistoryStatusFailed); len(failed) != 2 {
+			t.Errorf("Both projects should have been recorded as failed. Received %+v", entry.Projects)
+		}
+	})
+
+	t.Run("should fail the run when the workspace does not exist", func(t *testing.T) {
+		server := newTestAPIServer(t, "")
+
+		var run api_server.Run
+		apiRequest(t, server, http.MethodPost, "/api/runs", `{"type": "sync", "target": "unknown"}`, &run)
+
+		if run = waitForRun(t, server, run.ID); run.Status != api_server.RunStatusFailed || run.Error == "" {
+			t.Errorf("Invalid run. Received %+v", run)
+		}
+	})
+
+	t.Run("should stream the results of the projects then the run", func(t *testing.T) {
+		server := newTestAPIServer(t, "")
+
+		var run api_server.Run
+		apiRequest(t, server, http.MethodPost, "/api/runs", `{"type": "exec", "target": "backend", "where": ["file-exists:config.txt"]}`, &run)
+
+		response, err := server.Client().Get(server.URL + "/api/runs/" + run.ID + "/events")
+		if err != nil {
+			t.Fatalf("Request failed. Error: %s", err)
+		}
+		defer response.Body.Close()
+
+		if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+			t.Errorf("Invalid content type. Received '%s'", contentType)
+		}
+
+		var events []string
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "event: ") {
+				events = append(events, strings.TrimPrefix(scanner.Text(), "event: "))
+			}
+		}
+
+		if strings.Join(events, ",") != "project,project,done" {
+			t.Errorf("Invalid events. Received %v", events)
+		}
+	})
+}
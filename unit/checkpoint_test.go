@@ -0,0 +1,119 @@
+package unit_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"wildfire/cmd/exec"
+	"wildfire/cmd/history"
+	"wildfire/pkg"
+)
+
+func TestCheckpoint(t *testing.T) {
+	ctx := pkg.WithHistory(context.Background(), pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl")), []string{"exec"})
+
+	t.Run("should not checkpoint runs without history", func(t *testing.T) {
+		checkpoint := pkg.StartCheckpoint(context.Background(), pkg.NewHistoryEntry("exec", "backend"), t.TempDir())
+		if checkpoint != nil {
+			t.Fatalf("No checkpoint should have been started. Received %+v", checkpoint)
+		}
+
+		step := checkpoint.Step("exec", "ls", []string{"foo"})
+		if err := checkpoint.Complete(step, pkg.HistoryProject{Project: "foo"}); err != nil {
+			t.Errorf("A nil checkpoint should record nothing. Received %v", err)
+		}
+	})
+
+	t.Run("should resume the steps in order and retry the failed projects", func(t *testing.T) {
+		workspace := t.TempDir()
+		checkpoint := pkg.StartCheckpoint(ctx, pkg.NewHistoryEntry("clone", "backend"), workspace)
+
+		clone := checkpoint.Step("clone", "", []string{"foo", "bar"})
+		_ = checkpoint.Complete(clone, pkg.HistoryProject{Project: "foo", Status: pkg.HistoryStatusSucceeded})
+		_ = checkpoint.Complete(clone, pkg.HistoryProject{Project: "bar", Status: pkg.HistoryStatusFailed})
+		command := checkpoint.Step("command", "make", []string{"foo"})
+		_ = checkpoint.Complete(command, pkg.HistoryProject{Project: "foo", Status: pkg.HistoryStatusSucceeded})
+
+		checkpoints := pkg.FindCheckpoints(workspace)
+		if len(checkpoints) != 1 {
+			t.Fatalf("The checkpoint should have been found. Received %+v", checkpoints)
+		}
+
+		loaded := checkpoints[0]
+		if completed, total := loaded.Progress(); completed != 1 || total != 2 {
+			t.Errorf("Invalid progress. Expected 1/2 received %d/%d", completed, total)
+		}
+
+		resumed := loaded.Step("clone", "", []string{"foo", "bar", "zaz"})
+		if incomplete := loaded.Incomplete(resumed); !reflect.DeepEqual(incomplete, []string{"bar"}) {
+			t.Errorf("Only the failed project should be incomplete. Received %v", incomplete)
+		}
+
+		remaining := loaded.Remaining()
+		if len(remaining) != 1 || remaining[0].Command != "make" {
+			t.Errorf("The command step should remain. Received %+v", remaining)
+		}
+
+		if err := loaded.Finish(); err != nil {
+			t.Fatalf("Failed to finish the checkpoint. Error: %s", err)
+		}
+		if checkpoints := pkg.FindCheckpoints(workspace); len(checkpoints) != 0 {
+			t.Errorf("The checkpoint should have been removed. Received %+v", checkpoints)
+		}
+	})
+}
+
+func TestResumeRun(t *testing.T) {
+	workspace := t.TempDir()
+	for _, name := range []string{"foo", "bar"} {
+		_ = os.MkdirAll(filepath.Join(workspace, name), 0755)
+	}
+
+	configStore := pkg.NewMemoryConfigStore(&pkg.WildFireConfig{
+		Projects: map[string]*pkg.ProjectConfig{
+			"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/org/foo"},
+			"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/org/bar"},
+		},
+		Workspaces: map[string]*pkg.WorkspaceConfig{
+			"backend": {Path: workspace, Projects: []string{"foo", "bar"}},
+		},
+	})
+	historyStore := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	args := []string{"exec", "backend", "--", "touch", "ran.txt"}
+
+	// The run has been interrupted once the command completed in 'foo'.
+	ctx := pkg.WithHistory(pkg.WithConfigStore(context.Background(), configStore), historyStore, args)
+	checkpoint := pkg.StartCheckpoint(ctx, pkg.NewHistoryEntry("exec", "backend"), workspace)
+	step := checkpoint.Step("exec", "touch ran.txt", []string{"foo", "bar"})
+	_ = checkpoint.Complete(step, pkg.HistoryProject{Project: "foo", Status: pkg.HistoryStatusSucceeded})
+
+	root := &cobra.Command{Use: "wildfire", SilenceErrors: true, SilenceUsage: true}
+	root.AddCommand(exec.NewExecCmd())
+	root.AddCommand(history.NewResumeCmd())
+	root.SetArgs([]string{"resume", checkpoint.ID[:6]})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+
+	if err := root.ExecuteContext(pkg.WithHistory(pkg.WithConfigStore(context.Background(), configStore), historyStore, nil)); err != nil {
+		t.Fatalf("Failed to resume the run. Error: %s", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(workspace, "foo", "ran.txt")); os.IsNotExist(err) == false {
+		t.Error("The command should not have run again in the completed project")
+	}
+	if _, err := os.Stat(filepath.Join(workspace, "bar", "ran.txt")); err != nil {
+		t.Errorf("The command should have run in the incomplete project. Error: %s", err)
+	}
+	if checkpoints := pkg.FindCheckpoints(workspace); len(checkpoints) != 0 {
+		t.Errorf("The checkpoint should have been removed. Received %+v", checkpoints)
+	}
+
+	entries, _ := historyStore.List()
+	if len(entries) != 1 || entries[0].ID != checkpoint.ID || len(entries[0].Projects) != 2 {
+		t.Errorf("The resumed run should have been recorded with every project. Received %+v", entries)
+	}
+}
@@ -0,0 +1,448 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"wildfire/pkg"
+	"wildfire/pkg/project_repository"
+	"wildfire/wildfire"
+)
+
+func newTestClient(t *testing.T) *wildfire.Client {
+	client, err := wildfire.NewClient(filepath.Join(t.TempDir(), ".wildfire.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to create client. Error: %s", err)
+	}
+
+	ctx := context.Background()
+	for _, name := range []string{"foo", "bar", "zaz"} {
+		project := pkg.ProjectConfig{Name: name, Type: pkg.ProjectTypeGit, URL: pkg.ProjectPath("github.com/org/" + name)}
+		if err := client.AddProject(ctx, project); err != nil {
+			t.Fatalf("Failed to add project '%s'. Error: %s", name, err)
+		}
+	}
+
+	return client
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Projects", func(t *testing.T) {
+		t.Run("should save the projects to the configuration file", func(t *testing.T) {
+			client := newTestClient(t)
+
+			config, err := pkg.LoadConfigFile(client.ConfigPath())
+			if err != nil {
+				t.Fatalf("Failed to load configuration. Error: %s", err)
+			}
+			if len(config.Projects) != 3 {
+				t.Errorf("Expected 3 projects in the configuration file, found %d", len(config.Projects))
+			}
+		})
+
+		t.Run("should list the projects matching the filter", func(t *testing.T) {
+			client := newTestClient(t)
+			_ = client.SetProject(ctx, pkg.ProjectConfig{Name: "zaz", Type: pkg.ProjectTypeGit, URL: "url", Labels: []string{"go"}})
+
+			projects, err := client.ListProjects(ctx, pkg.ProjectFilter{Labels: []string{"go"}})
+			if err != nil {
+				t.Fatalf("Failed to list projects. Error: %s", err)
+			}
+
+			if len(projects) != 1 || projects[0].Name != "zaz" || projects[0].URL != "url" {
+				t.Errorf("Invalid projects. Received %+v", projects)
+			}
+		})
+
+		t.Run("should not add a project which already exists", func(t *testing.T) {
+			client := newTestClient(t)
+
+			if err := client.AddProject(ctx, pkg.ProjectConfig{Name: "foo", Type: pkg.ProjectTypeGit}); err == nil {
+				t.Error("An error should have been returned")
+			}
+		})
+
+		t.Run("should return ErrNotFound for a project which does not exist", func(t *testing.T) {
+			client := newTestClient(t)
+
+			if _, err := client.GetProject(ctx, "unknown"); errors.Is(err, wildfire.ErrNotFound) == false {
+				t.Errorf("ErrNotFound should have been returned. Received %v", err)
+			}
+		})
+
+		t.Run("should remove the project from its groups", func(t *testing.T) {
+			client := newTestClient(t)
+			_ = client.CreateGroup(ctx, "backend", pkg.GroupConfig{Projects: []string{"foo", "bar"}})
+
+			groups, err := client.RemoveProject(ctx, "foo")
+			if err != nil {
+				t.Fatalf("Failed to remove project. Error: %s", err)
+			}
+			if !reflect.DeepEqual(groups, []string{"backend"}) {
+				t.Errorf("Invalid groups. Expected [backend] received %v", groups)
+			}
+
+			projects, _ := client.GetGroupProjects(ctx, "backend")
+			if !reflect.DeepEqual(projects, []string{"bar"}) {
+				t.Errorf("Project should have been removed from the group. Received %v", projects)
+			}
+		})
+
+		t.Run("should describe the groups and the clones of the project", func(t *testing.T) {
+			client := newTestClient(t)
+			_ = client.CreateGroup(ctx, "backend", pkg.GroupConfig{Projects: []string{"foo"}})
+			_ = client.CreateGroup(ctx, "all", pkg.GroupConfig{Projects: []string{"foo", "bar"}})
+			_ = client.SetWorkspace(ctx, "backend", pkg.WorkspaceConfig{Path: "/tmp/backend", Projects: []string{"foo"}})
+
+			details, err := client.DescribeProject(ctx, "foo")
+			if err != nil {
+				t.Fatalf("Failed to describe project. Error: %s", err)
+			}
+
+			if !reflect.DeepEqual(details.Groups, []string{"all", "backend"}) {
+				t.Errorf("Invalid groups. Expected [all backend] received %v", details.Groups)
+			}
+			expected := map[string]string{"backend": filepath.Join("/tmp/backend", "foo")}
+			if !reflect.DeepEqual(details.Workspaces, expected) {
+				t.Errorf("Invalid workspaces. Expected %v received %v", expected, details.Workspaces)
+			}
+		})
+	})
+
+	t.Run("Groups", func(t *testing.T) {
+		t.Run("should not create a group with a project which does not exist", func(t *testing.T) {
+			client := newTestClient(t)
+
+			err := client.CreateGroup(ctx, "backend", pkg.GroupConfig{Projects: []string{"foo", "unknown"}})
+			if errors.Is(err, wildfire.ErrNotFound) == false {
+				t.Errorf("ErrNotFound should have been returned. Received %v", err)
+			}
+
+			if groups, _ := client.ListGroups(ctx); len(groups) != 0 {
+				t.Errorf("The group should not have been created. Received %v", groups)
+			}
+		})
+
+		t.Run("should not add projects when one of them does not exist", func(t *testing.T) {
+			client := newTestClient(t)
+			_ = client.CreateGroup(ctx, "backend", pkg.GroupConfig{Projects: []string{"foo"}})
+
+			if err := client.AddProjectsToGroup(ctx, "backend", "bar", "unknown"); err == nil {
+				t.Error("An error should have been returned")
+			}
+
+			group, _ := client.GetGroup(ctx, "backend")
+			if !reflect.DeepEqual(group.Projects, []string{"foo"}) {
+				t.Errorf("The group should not have been updated. Received %v", group.Projects)
+			}
+		})
+
+		t.Run("should not include groups which include each other", func(t *testing.T) {
+			client := newTestClient(t)
+			_ = client.CreateGroup(ctx, "backend", pkg.GroupConfig{Projects: []string{"foo"}})
+			_ = client.CreateGroup(ctx, "all", pkg.GroupConfig{Groups: []string{"backend"}})
+
+			if err := client.SetGroup(ctx, "backend", pkg.GroupConfig{Groups: []string{"all"}}); err == nil {
+				t.Error("An error should have been returned")
+			}
+
+			group, _ := client.GetGroup(ctx, "backend")
+			if !reflect.DeepEqual(group.Projects, []string{"foo"}) || len(group.Groups) != 0 {
+				t.Errorf("The group should not have been updated. Received %+v", group)
+			}
+		})
+
+		t.Run("should delete the group", func(t *testing.T) {
+			client := newTestClient(t)
+			_ = client.CreateGroup(ctx, "backend", pkg.GroupConfig{})
+
+			if err := client.DeleteGroup(ctx, "backend"); err != nil {
+				t.Fatalf("Failed to delete group. Error: %s", err)
+			}
+			if err := client.DeleteGroup(ctx, "backend"); errors.Is(err, wildfire.ErrNotFound) == false {
+				t.Errorf("ErrNotFound should have been returned. Received %v", err)
+			}
+		})
+
+		t.Run("should rename the group and the groups which include it", func(t *testing.T) {
+			client := newTestClient(t)
+			_ = client.CreateGroup(ctx, "backend", pkg.GroupConfig{Projects: []string{"foo"}})
+			_ = client.CreateGroup(ctx, "all", pkg.GroupConfig{Groups: []string{"backend"}})
+
+			if err := client.RenameGroup(ctx, "backend", "api"); err != nil {
+				t.Fatalf("Failed to rename group. Error: %s", err)
+			}
+
+			if all, _ := client.GetGroup(ctx, "all"); !reflect.DeepEqual(all.Groups, []string{"api"}) {
+				t.Errorf("The include should have been renamed. Received %v", all.Groups)
+			}
+			if err := client.RenameGroup(ctx, "backend", "api"); errors.Is(err, wildfire.ErrNotFound) == false {
+				t.Errorf("ErrNotFound should have been returned. Received %v", err)
+			}
+		})
+
+		t.Run("should return the members and the tree of the group", func(t *testing.T) {
+			client := wildfire.NewClientFromConfig(&pkg.WildFireConfig{
+				Projects: map[string]*pkg.ProjectConfig{
+					"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/org/foo", DependsOn: []string{"bar"}},
+					"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/org/bar"},
+				},
+				Groups: map[string]*pkg.GroupConfig{
+					"all":     {Projects: []string{"foo", "gone"}, Groups: []string{"backend"}},
+					"backend": {Projects: []string{"bar", "foo"}},
+				},
+			})
+
+			members, err := client.GetGroupMembers(ctx, "all")
+			if err != nil {
+				t.Fatalf("Failed to get members. Error: %s", err)
+			}
+
+			expected := []wildfire.GroupMember{
+				{Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/org/foo", DependsOn: []string{"bar"}},
+				{Name: "gone", Missing: true},
+				{Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/org/bar"},
+			}
+			if !reflect.DeepEqual(members, expected) {
+				t.Errorf("Invalid members. Expected %+v received %+v", expected, members)
+			}
+
+			tree, err := client.GetGroupTree(ctx, "all")
+			if err != nil {
+				t.Fatalf("Failed to get tree. Error: %s", err)
+			}
+			if len(tree.Projects) != 2 || len(tree.Groups) != 1 || len(tree.Groups[0].Projects) != 2 {
+				t.Errorf("Invalid tree. Received %+v", tree)
+			}
+		})
+
+		t.Run("should remove the references to missing projects and groups", func(t *testing.T) {
+			config := &pkg.WildFireConfig{
+				Projects: map[string]*pkg.ProjectConfig{"foo": {Name: "foo", Type: pkg.ProjectTypeGit}},
+				Groups: map[string]*pkg.GroupConfig{
+					"backend": {Projects: []string{"foo", "gone"}, Groups: []string{"missing"}},
+				},
+			}
+			client := wildfire.NewClientFromConfig(config)
+
+			repairs, err := client.RepairGroups(ctx)
+			if err != nil {
+				t.Fatalf("Failed to repair groups. Error: %s", err)
+			}
+
+			expected := []wildfire.GroupRepair{
+				{Group: "backend", Project: "gone"},
+				{Group: "backend", IncludedGroup: "missing"},
+			}
+			if !reflect.DeepEqual(repairs, expected) {
+				t.Errorf("Invalid repairs. Expected %+v received %+v", expected, repairs)
+			}
+			if group := config.Groups["backend"]; !reflect.DeepEqual(group.Projects, []string{"foo"}) || len(group.Groups) != 0 {
+				t.Errorf("The references should have been removed. Received %+v", group)
+			}
+		})
+	})
+
+	t.Run("Clones", func(t *testing.T) {
+		for _, variable := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+			previous, set := os.LookupEnv(variable)
+			_ = os.Setenv(variable, "wildfire")
+			defer func(variable string) {
+				if set {
+					_ = os.Setenv(variable, previous)
+				} else {
+					_ = os.Unsetenv(variable)
+				}
+			}(variable)
+		}
+
+		origins := t.TempDir()
+		client := newTestClient(t)
+		client.NewCloner = func(branch string) project_repository.Cloner {
+			return &ClonerMock{StubCloneProject: func(path string, project *pkg.ProjectConfig) error {
+				if project.Name == "bar" {
+					return errors.New("unavailable")
+				}
+
+				gitCommand(t, origins, "clone", "-q", filepath.Join(origins, project.Name), path)
+				return nil
+			}}
+		}
+
+		for _, name := range []string{"foo", "bar", "zaz"} {
+			_ = os.MkdirAll(filepath.Join(origins, name), 0755)
+			initTestRepository(t, filepath.Join(origins, name), name)
+		}
+
+		workspace := filepath.Join(t.TempDir(), "backend")
+		_ = client.CreateGroup(ctx, "backend", pkg.GroupConfig{Projects: []string{"foo", "bar"}})
+
+		t.Run("should clone the group and save the workspace", func(t *testing.T) {
+			results, err := client.Clone(ctx, "backend", wildfire.CloneOptions{Path: workspace})
+			if err != nil {
+				t.Fatalf("Failed to clone group. Error: %s", err)
+			}
+
+			if len(results) != 2 || results[0].Cloned == false || results[1].Err == nil {
+				t.Errorf("Invalid results. Received %+v", results)
+			}
+			if _, err := os.Stat(filepath.Join(workspace, "bar")); os.IsNotExist(err) == false {
+				t.Error("The failed clone should have been removed")
+			}
+
+			config, _ := client.Config(ctx)
+			if !reflect.DeepEqual(config.Workspaces["backend"].Projects, []string{"foo"}) {
+				t.Errorf("Only the cloned projects should be in the workspace. Received %+v", config.Workspaces["backend"])
+			}
+		})
+
+		t.Run("should clone a project and save its workspace", func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "zaz")
+			result, err := client.CloneProject(ctx, "zaz", path)
+			if err != nil || result.Cloned == false {
+				t.Fatalf("Failed to clone project. Error: %v Result: %+v", err, result)
+			}
+
+			config, _ := client.Config(ctx)
+			workspace := config.Workspaces[pkg.ProjectWorkspaceName("zaz")]
+			if workspace == nil || workspace.Path != path || !reflect.DeepEqual(workspace.Projects, []string{"zaz"}) {
+				t.Errorf("The workspace of the project should have been saved. Received %+v", workspace)
+			}
+
+			if _, err := client.CloneProject(ctx, "unknown", path); errors.Is(err, wildfire.ErrNotFound) == false {
+				t.Errorf("ErrNotFound should have been returned. Received %v", err)
+			}
+		})
+
+		t.Run("should run the command in the clones", func(t *testing.T) {
+			results, err := client.Exec(ctx, "backend", nil, "cat", "config.txt")
+			if err != nil {
+				t.Fatalf("Failed to run command. Error: %s", err)
+			}
+
+			if len(results) != 1 || results[0].Output != "foo" || results[0].Err != nil {
+				t.Errorf("Invalid results. Received %+v", results)
+			}
+		})
+
+		t.Run("should search the clones", func(t *testing.T) {
+			results, err := client.Search(ctx, "backend", regexp.MustCompile("fo+"), "")
+			if err != nil {
+				t.Fatalf("Failed to search. Error: %s", err)
+			}
+
+			if len(results) != 1 || len(results[0].Matches) != 1 || results[0].Matches[0].File != "config.txt" {
+				t.Errorf("Invalid results. Received %+v", results)
+			}
+		})
+
+		t.Run("should pull the clones and clone the projects added to the group", func(t *testing.T) {
+			_ = client.AddProjectsToGroup(ctx, "backend", "zaz")
+			writeTestFile(t, filepath.Join(origins, "foo", "config.txt"), "updated")
+			gitCommand(t, filepath.Join(origins, "foo"), "commit", "-q", "-a", "-m", "update")
+
+			results, err := client.Sync(ctx, "backend")
+			if err != nil {
+				t.Fatalf("Failed to sync workspace. Error: %s", err)
+			}
+
+			if len(results) != 3 || results[0].Err != nil || results[1].Err == nil || results[2].Cloned == false {
+				t.Errorf("Invalid results. Received %+v", results)
+			}
+			if content := readTestFile(t, filepath.Join(workspace, "foo", "config.txt")); content != "updated" {
+				t.Errorf("The clone should have been updated. Received '%s'", content)
+			}
+
+			config, _ := client.Config(ctx)
+			if !reflect.DeepEqual(config.Workspaces["backend"].Projects, []string{"foo", "zaz"}) {
+				t.Errorf("The cloned project should have been added to the workspace. Received %+v", config.Workspaces["backend"])
+			}
+		})
+
+		t.Run("should not lock the configuration while the projects are cloned", func(t *testing.T) {
+			client := newTestClient(t)
+			other, _ := wildfire.NewClient(client.ConfigPath())
+			_ = client.CreateGroup(ctx, "backend", pkg.GroupConfig{Projects: []string{"foo"}})
+
+			client.NewCloner = func(branch string) project_repository.Cloner {
+				return &ClonerMock{StubCloneProject: func(path string, project *pkg.ProjectConfig) error {
+					if err := other.AddProject(ctx, pkg.ProjectConfig{Name: "other", Type: pkg.ProjectTypeGit, URL: "url"}); err != nil {
+						return err
+					}
+
+					return os.MkdirAll(path, 0755)
+				}}
+			}
+
+			results, err := client.Clone(ctx, "backend", wildfire.CloneOptions{Path: filepath.Join(t.TempDir(), "backend")})
+			if err != nil || results[0].Err != nil {
+				t.Fatalf("Failed to clone group. Error: %v Results: %+v", err, results)
+			}
+
+			config, _ := client.Config(ctx)
+			if config.Projects["other"] == nil || !reflect.DeepEqual(config.Workspaces["backend"].Projects, []string{"foo"}) {
+				t.Errorf("Both the workspace and the project added meanwhile should have been saved. Received %+v", config)
+			}
+		})
+
+		t.Run("should stop cloning once the context is done", func(t *testing.T) {
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+
+			path := filepath.Join(t.TempDir(), "foo")
+			project := &pkg.ProjectConfig{Name: "foo", URL: pkg.ProjectPath(filepath.Join(origins, "foo"))}
+			err := project_repository.CloneProjectContext(cancelled, &project_repository.GitCloner{}, path, project)
+			if errors.Is(err, context.Canceled) == false {
+				t.Errorf("The context error should have been returned. Received %v", err)
+			}
+		})
+
+		t.Run("should return ErrNotFound for a workspace which does not exist", func(t *testing.T) {
+			if _, err := client.Exec(ctx, "unknown", nil, "ls"); errors.Is(err, wildfire.ErrNotFound) == false {
+				t.Errorf("ErrNotFound should have been returned. Received %v", err)
+			}
+		})
+
+		t.Run("should not run the command when the context is done", func(t *testing.T) {
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+
+			if _, err := client.Exec(cancelled, "backend", nil, "ls"); err == nil || strings.Contains(err.Error(), "cancel") == false {
+				t.Errorf("The context error should have been returned. Received %v", err)
+			}
+		})
+	})
+	t.Run("DryRun", func(t *testing.T) {
+		dryRun := pkg.WithRunOptions(ctx, &pkg.RunOptions{DryRun: true})
+
+		t.Run("should not save the changes to the configuration file", func(t *testing.T) {
+			client := newTestClient(t)
+
+			if err := client.AddProject(dryRun, pkg.ProjectConfig{Name: "new", Type: pkg.ProjectTypeGit, URL: "url"}); err != nil {
+				t.Fatalf("AddProject should not have returned an error. Error: %s", err)
+			}
+
+			if _, err := client.GetProject(ctx, "new"); errors.Is(err, wildfire.ErrNotFound) == false {
+				t.Errorf("The project should not have been saved. Received %v", err)
+			}
+		})
+
+		t.Run("should not clone nor run commands", func(t *testing.T) {
+			client := newTestClient(t)
+
+			if _, err := client.CloneProject(dryRun, "foo", t.TempDir()); errors.Is(err, wildfire.ErrDryRun) == false {
+				t.Errorf("ErrDryRun should have been returned. Received %v", err)
+			}
+			if _, err := client.Exec(dryRun, "backend", nil, "ls"); errors.Is(err, wildfire.ErrDryRun) == false {
+				t.Errorf("ErrDryRun should have been returned. Received %v", err)
+			}
+		})
+	})
+}
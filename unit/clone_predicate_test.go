@@ -0,0 +1,161 @@
+package unit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"wildfire/pkg"
+)
+
+func TestClonePredicate(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module foo\n\nrequire github.com/foo/bar v1.0.0\n")
+	writeTestFile(t, filepath.Join(dir, "infra", "prod", "main.tf"), "")
+	writeTestFile(t, filepath.Join(dir, ".git", "config.tf"), "")
+
+	t.Run("ParseClonePredicate", func(t *testing.T) {
+		for _, predicate := range []string{
+			"file-exists",
+			"file-exists:",
+			"contains:go.mod",
+			"contains::text",
+			"cmd: ",
+			"size:10",
+		} {
+			if _, err := pkg.ParseClonePredicate(predicate); err == nil {
+				t.Errorf("ParseClonePredicate should have returned an error for '%s' instead it resolved", predicate)
+			}
+		}
+
+		predicate, err := pkg.ParseClonePredicate("contains:go.mod:github.com/foo/bar")
+		if err != nil {
+			t.Fatalf("ParseClonePredicate should not have returned an error. Error: %s", err)
+		}
+
+		if predicate.Path != "go.mod" || predicate.Text != "github.com/foo/bar" {
+			t.Errorf("Invalid predicate parsed. Received %+v", predicate)
+		}
+	})
+
+	t.Run("Match", func(t *testing.T) {
+		cases := map[string]bool{
+			"file-exists:go.mod":                   true,
+			"file-exists:infra/prod":               true,
+			"file-exists:package.json":             false,
+			"contains:go.mod:github.com/foo/bar":   true,
+			"contains:go.mod:github.com/foo/baz":   false,
+			"contains:package.json:github.com/foo": false,
+			"glob:**/*.tf":                         true,
+			"glob:infra/*/main.tf":                 true,
+			"glob:*.tf":                            false,
+			"glob:infra/*.tf":                      false,
+			"glob:config.tf":                       false,
+			"cmd:test -f go.mod":                   true,
+			"cmd:test -f package.json":             false,
+		}
+
+		for expression, expected := range cases {
+			predicate, err := pkg.ParseClonePredicate(expression)
+			if err != nil {
+				t.Fatalf("ParseClonePredicate should not have returned an error. Error: %s", err)
+			}
+
+			matched, err := predicate.Match(dir)
+			if err != nil {
+				t.Errorf("Match should not have returned an error for '%s'. Error: %s", expression, err)
+			}
+
+			if matched != expected {
+				t.Errorf("Invalid match for '%s'. Expected '%t' received '%t'", expression, expected, matched)
+			}
+		}
+	})
+
+	t.Run("should return an error if the command of a predicate can not be started", func(t *testing.T) {
+		predicate, _ := pkg.ParseClonePredicate("cmd:wildfire-missing-command")
+
+		if _, err := predicate.Match(dir); err == nil {
+			t.Error("Match should have returned an error instead it resolved")
+		}
+	})
+
+	t.Run("MatchClone", func(t *testing.T) {
+		predicates, err := pkg.ParseClonePredicates([]string{"file-exists:go.mod", "glob:**/*.yaml"})
+		if err != nil {
+			t.Fatalf("ParseClonePredicates should not have returned an error. Error: %s", err)
+		}
+
+		predicate, err := pkg.MatchClone(dir, predicates)
+		if err != nil {
+			t.Fatalf("MatchClone should not have returned an error. Error: %s", err)
+		}
+
+		if predicate == nil || predicate.String() != "glob:**/*.yaml" {
+			t.Errorf("Invalid predicate returned. Expected '%s' received '%v'", "glob:**/*.yaml", predicate)
+		}
+
+		if predicate, _ := pkg.MatchClone(dir, predicates[:1]); predicate != nil {
+			t.Errorf("All predicates should have matched. Received '%s'", predicate)
+		}
+	})
+}
+
+func TestExecInClones(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "foo", "package.json"), "{}")
+	_ = os.MkdirAll(filepath.Join(dir, "bar"), 0755)
+
+	clones := []pkg.Clone{
+		{Project: "foo", Path: filepath.Join(dir, "foo")},
+		{Project: "bar", Path: filepath.Join(dir, "bar")},
+		{Project: "zaz", Path: filepath.Join(dir, "zaz")},
+	}
+	predicates, _ := pkg.ParseClonePredicates([]string{"file-exists:package.json"})
+
+	t.Run("should only run the command in the clones matching the predicates", func(t *testing.T) {
+		results := pkg.ExecInClones(clones, predicates, "ls")
+
+		if results[0].Matched == false || results[0].Err != nil || results[0].Output != "package.json\n" {
+			t.Errorf("Invalid result for 'foo'. Received %+v", results[0])
+		}
+
+		if results[1].Skipped == false || results[1].Output != "" {
+			t.Errorf("Project 'bar' should have been skipped. Received %+v", results[1])
+		}
+
+		if results[2].Err == nil || results[2].Skipped {
+			t.Errorf("Project 'zaz' should have failed as it has not been cloned. Received %+v", results[2])
+		}
+	})
+
+	t.Run("should report the clones in which the command failed", func(t *testing.T) {
+		results := pkg.ExecInClones(clones[:2], nil, "test", "-f", "package.json")
+
+		if results[0].Err != nil || results[1].Err == nil || results[1].Matched == false {
+			t.Errorf("Invalid results. Received %+v", results)
+		}
+	})
+
+	t.Run("FilterClones should not run any command", func(t *testing.T) {
+		results := pkg.FilterClones(clones[:2], predicates)
+
+		if results[0].Matched == false || results[1].Skipped == false || results[0].Output != "" {
+			t.Errorf("Invalid results. Received %+v", results)
+		}
+	})
+}
+
+func TestParseCommand(t *testing.T) {
+	name, args, err := pkg.ParseCommand(`git commit -m "update dependencies"`)
+	if err != nil {
+		t.Fatalf("ParseCommand should not have returned an error. Error: %s", err)
+	}
+
+	if name != "git" || len(args) != 3 || args[2] != "update dependencies" {
+		t.Errorf("Invalid command parsed. Received '%s' %+v", name, args)
+	}
+
+	if _, _, err := pkg.ParseCommand("  "); err == nil {
+		t.Error("ParseCommand should have returned an error instead it resolved")
+	}
+}
@@ -0,0 +1,78 @@
+package unit_test
+
+import (
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+	"wildfire/pkg"
+)
+
+func TestSearchClones(t *testing.T) {
+	dir := t.TempDir()
+	fooPath := filepath.Join(dir, "foo")
+	barPath := filepath.Join(dir, "bar")
+
+	writeTestFile(t, filepath.Join(fooPath, "go.mod"), "module foo\n\nrequire github.com/foo/lib v1.0.0\n")
+	writeTestFile(t, filepath.Join(fooPath, "binary"), "github.com/foo/lib\x00")
+
+	repo, err := git.PlainInit(fooPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, _ := repo.Worktree()
+	_, _ = worktree.Add("go.mod")
+	_, err = worktree.Commit("init", &git.CommitOptions{
+		Author: &object.Signature{Name: "wildfire", Email: "wildfire@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestFile(t, filepath.Join(fooPath, "go.mod"), "module foo\n")
+	writeTestFile(t, filepath.Join(barPath, ".gitignore"), "# dependencies\nvendor/\n")
+	writeTestFile(t, filepath.Join(barPath, "vendor", "lib.go"), "github.com/foo/lib\n")
+	writeTestFile(t, filepath.Join(barPath, "cmd", ".gitignore"), "*.gen.go\n")
+	writeTestFile(t, filepath.Join(barPath, "cmd", "main.gen.go"), "github.com/foo/lib\n")
+	writeTestFile(t, filepath.Join(barPath, "cmd", "main.go"), "package main\n\nimport \"github.com/foo/lib\"\n")
+
+	clones := []pkg.Clone{{Project: "foo", Path: fooPath}, {Project: "bar", Path: barPath}}
+	pattern := regexp.MustCompile(`github\.com/foo/lib`)
+
+	t.Run("should search the working trees skipping ignored and binary files", func(t *testing.T) {
+		results := pkg.SearchClones(clones, pattern, "")
+
+		if results[0].Err != nil || len(results[0].Matches) != 0 {
+			t.Errorf("Invalid result for 'foo'. Received %+v", results[0])
+		}
+
+		expected := []pkg.SearchMatch{{File: "cmd/main.go", Line: 3, Text: "import \"github.com/foo/lib\""}}
+		if results[1].Err != nil || !reflect.DeepEqual(expected, results[1].Matches) {
+			t.Errorf("Invalid matches for 'bar'. Expected %+v received %+v", expected, results[1].Matches)
+		}
+	})
+
+	t.Run("should search the revision without checking it out", func(t *testing.T) {
+		results := pkg.SearchClones(clones, pattern, "HEAD")
+
+		expected := []pkg.SearchMatch{{File: "go.mod", Line: 3, Text: "require github.com/foo/lib v1.0.0"}}
+		if results[0].Err != nil || !reflect.DeepEqual(expected, results[0].Matches) {
+			t.Errorf("Invalid matches for 'foo'. Expected %+v received %+v", expected, results[0].Matches)
+		}
+
+		if results[1].Err == nil {
+			t.Error("Searching a clone which is not a repository should have failed")
+		}
+	})
+
+	t.Run("should fail if the clone does not exist", func(t *testing.T) {
+		results := pkg.SearchClones([]pkg.Clone{{Project: "zaz", Path: filepath.Join(dir, "zaz")}}, pattern, "")
+
+		if results[0].Err == nil {
+			t.Error("Searching a missing clone should have failed")
+		}
+	})
+}
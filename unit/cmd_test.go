@@ -1,10 +1,15 @@
 package unit_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	"os"
+	"path/filepath"
 	"testing"
+	"wildfire/cmd/clone"
 	"wildfire/pkg"
 )
 
@@ -20,7 +25,9 @@ func TestProjectFunc(t *testing.T) {
 			},
 		)
 
-		testFunc(&cobra.Command{RunE: testFunc}, []string{})
+		cmd := &cobra.Command{RunE: testFunc}
+		cmd.SetArgs([]string{})
+		_ = cmd.ExecuteContext(pkg.WithConfigStore(context.Background(), pkg.NewMemoryConfigStore(nil)))
 
 		if executed != true {
 			t.Error("Provided function is not executed")
@@ -30,15 +37,18 @@ func TestProjectFunc(t *testing.T) {
 	t.Run("should update the configuration if second returned argument is true", func(t *testing.T) {
 		cfgFile := getConfigFilePath("new.wildfire.yaml")
 		_ = deleteConfig(cfgFile)
-		_ = setConfig(cfgFile)
 
 		testFunc := pkg.ProjectFunc(
 			func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
-				return config, true, nil
+				_, err := pkg.NewProjectService(config).AddProject("foo", "github.com/foo", pkg.ProjectTypeGit)
+
+				return config, true, err
 			},
 		)
 
-		testFunc(&cobra.Command{RunE: testFunc}, []string{})
+		cmd := &cobra.Command{RunE: testFunc}
+		cmd.SetArgs([]string{})
+		_ = cmd.ExecuteContext(pkg.WithConfigStore(context.Background(), pkg.NewFileConfigStore(cfgFile)))
 
 		if _, err := os.Stat(cfgFile); os.IsNotExist(err) {
 			t.Error("Configuration was not created when 'true' was returned as second argument")
@@ -61,4 +71,105 @@ func TestProjectFunc(t *testing.T) {
 			t.Error("Test function should have returned an error, instead it returned nil")
 		}
 	})
+
+	runWithStore := func(store pkg.ConfigStore, fn pkg.CMDFunc) error {
+		cmd := &cobra.Command{RunE: pkg.ProjectFunc(fn)}
+		cmd.SetArgs([]string{})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+
+		return cmd.ExecuteContext(pkg.WithConfigStore(context.Background(), store))
+	}
+	addProject := func(config *pkg.WildFireConfig, name string, url string) {
+		pkg.NewProjectService(config).UpdateOrCreate(&pkg.ProjectConfig{Name: name, Type: pkg.ProjectTypeGit, URL: pkg.ProjectPath(url)})
+	}
+
+	t.Run("should not lock the configuration while the function runs", func(t *testing.T) {
+		store := pkg.NewFileConfigStore(filepath.Join(t.TempDir(), ".wildfire.yaml"))
+
+		err := runWithStore(store, func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			lock, err := store.Lock()
+			if err != nil {
+				return config, false, err
+			}
+
+			return config, false, lock.Unlock()
+		})
+		if err != nil {
+			t.Errorf("The configuration should not have been locked. Error: %s", err)
+		}
+	})
+
+	t.Run("should keep the changes saved by other commands while the function runs", func(t *testing.T) {
+		store := pkg.NewFileConfigStore(filepath.Join(t.TempDir(), ".wildfire.yaml"))
+
+		err := runWithStore(store, func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			other, _ := store.Load()
+			addProject(other, "other", "github.com/other")
+			if err := store.Save(other); err != nil {
+				return config, false, err
+			}
+
+			addProject(config, "mine", "github.com/mine")
+
+			return config, true, nil
+		})
+		if err != nil {
+			t.Fatalf("ProjectFunc should not have returned an error. Error: %s", err)
+		}
+
+		if config, _ := store.Load(); config.Projects["other"] == nil || config.Projects["mine"] == nil {
+			t.Errorf("Both changes should have been saved. Projects %+v", config.Projects)
+		}
+	})
+
+	t.Run("should return an error if an entry has been changed by another command", func(t *testing.T) {
+		config := &pkg.WildFireConfig{Projects: map[string]*pkg.ProjectConfig{}}
+		addProject(config, "foo", "github.com/foo")
+		store := pkg.NewMemoryConfigStore(config)
+
+		err := runWithStore(store, func(config *pkg.WildFireConfig, cmd *cobra.Command, args []string) (*pkg.WildFireConfig, bool, error) {
+			other, _ := store.Load()
+			addProject(other, "foo", "github.com/other/foo")
+			if err := store.Save(other); err != nil {
+				return config, false, err
+			}
+
+			addProject(config, "foo", "github.com/mine/foo")
+
+			return config, true, nil
+		})
+
+		var conflictErr *pkg.ConfigConflictError
+		if errors.As(err, &conflictErr) == false {
+			t.Fatalf("A conflict error should have been returned. Received %v", err)
+		}
+		if config, _ := store.Load(); config.Projects["foo"].URL != "github.com/other/foo" {
+			t.Errorf("The change of the other command should have been kept. Received %+v", config.Projects["foo"])
+		}
+	})
+}
+
+func TestCloneProjectCmd(t *testing.T) {
+	config := &pkg.WildFireConfig{Projects: map[string]*pkg.ProjectConfig{
+		"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: pkg.ProjectPath(filepath.Join(t.TempDir(), "missing"))},
+	}}
+	store := pkg.NewMemoryConfigStore(config)
+	dir := t.TempDir()
+
+	cmd := clone.NewPullProjectCmd()
+	cmd.SetArgs([]string{"foo", dir})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	if err := cmd.ExecuteContext(pkg.WithConfigStore(context.Background(), store)); err == nil {
+		t.Error("The error of the failed clone should have been returned")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "foo", "foo")); os.IsNotExist(err) == false {
+		t.Error("The failed clone should have been removed")
+	}
+	if loaded, _ := store.Load(); len(loaded.Workspaces) != 0 {
+		t.Errorf("No workspace should have been saved. Received %+v", loaded.Workspaces)
+	}
 }
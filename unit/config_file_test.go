@@ -0,0 +1,100 @@
+package unit_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"wildfire/pkg"
+)
+
+func TestConfigFile(t *testing.T) {
+	t.Run("SaveConfig", func(t *testing.T) {
+		t.Run("should keep the previous configuration versions as rotating backups", func(t *testing.T) {
+			cfgFile := getConfigFilePath("backup.wildfire.yaml")
+			_ = deleteConfig(cfgFile)
+			defer deleteConfig(cfgFile)
+
+			config := loadConfigFile(t, cfgFile)
+			for i := 0; i < pkg.ConfigBackupCount+3; i++ {
+				_, _ = pkg.NewProjectService(config).AddProject(fmt.Sprintf("project-%d", i), "github.com/foo", pkg.ProjectTypeGit)
+				if err := config.SaveConfig(); err != nil {
+					t.Errorf("Failed to save configuration. Error: %s", err)
+				}
+			}
+
+			backups, err := pkg.GetConfigBackups(cfgFile)
+			if err != nil {
+				t.Errorf("Failed to retrieve backups. Error: %s", err)
+			}
+
+			if len(backups) != pkg.ConfigBackupCount {
+				t.Errorf("Invalid number of backups. Expected '%d' received '%d'", pkg.ConfigBackupCount, len(backups))
+			}
+
+			for index, backup := range backups {
+				if backup.Number != index+1 {
+					t.Errorf("Backups are not ordered. Expected backup '%d' received '%d'", index+1, backup.Number)
+				}
+			}
+
+			leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(cfgFile), ".backup.wildfire.yaml.*"))
+			if len(leftovers) != 0 {
+				t.Errorf("Temporary configuration files were not removed. Found %v", leftovers)
+			}
+		})
+	})
+
+	t.Run("RestoreConfigBackup", func(t *testing.T) {
+		t.Run("should replace the configuration with the backup", func(t *testing.T) {
+			cfgFile := getConfigFilePath("restore.wildfire.yaml")
+			_ = deleteConfig(cfgFile)
+			defer deleteConfig(cfgFile)
+
+			config := loadConfigFile(t, cfgFile)
+			_, _ = pkg.NewProjectService(config).AddProject("foo", "github.com/foo", pkg.ProjectTypeGit)
+			_ = config.SaveConfig()
+			pkg.NewProjectService(config).RemoveProject("foo")
+			_ = config.SaveConfig()
+
+			err := pkg.RestoreConfigBackup(cfgFile, 1)
+			if err != nil {
+				t.Errorf("RestoreConfigBackup should not have returned an error. Error: %s", err)
+			}
+
+			if pkg.NewProjectService(loadConfigFile(t, cfgFile)).HasProject("foo") == false {
+				t.Error("Configuration was not restored from backup")
+			}
+		})
+
+		t.Run("should return an error if the backup does not exist", func(t *testing.T) {
+			err := pkg.RestoreConfigBackup(getConfigFilePath("missing.wildfire.yaml"), 1)
+			if err == nil {
+				t.Error("RestoreConfigBackup should have returned an error instead it resolved")
+			}
+		})
+	})
+
+	t.Run("LockConfig", func(t *testing.T) {
+		t.Run("should create the lock file and remove it on unlock", func(t *testing.T) {
+			cfgFile := getConfigFilePath("lock.wildfire.yaml")
+
+			lock, err := pkg.LockConfig(cfgFile)
+			if err != nil {
+				t.Errorf("LockConfig should not have returned an error. Error: %s", err)
+			}
+
+			if _, err := os.Stat(cfgFile + ".lock"); os.IsNotExist(err) {
+				t.Error("Lock file was not created")
+			}
+
+			if err := lock.Unlock(); err != nil {
+				t.Errorf("Unlock should not have returned an error. Error: %s", err)
+			}
+
+			if _, err := os.Stat(cfgFile + ".lock"); !os.IsNotExist(err) {
+				t.Error("Lock file was not removed")
+			}
+		})
+	})
+}
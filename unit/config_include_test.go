@@ -0,0 +1,231 @@
+package unit_test
+
+import (
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"wildfire/pkg"
+)
+
+const registryContent = `
+projects:
+  shared:
+    name: shared
+    type: git
+    url: github.com/platform/shared
+groups:
+  platform:
+  - shared
+`
+
+func createRegistryRepository(t *testing.T, dir string, content string) {
+	repository, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestFile(t, filepath.Join(dir, pkg.DefaultIncludeFile), content)
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := worktree.Add(pkg.DefaultIncludeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = worktree.Commit("registry", &git.CommitOptions{
+		Author: &object.Signature{Name: "wildfire", Email: "wildfire@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readConfigFile(t *testing.T, path string) *pkg.WildFireConfig {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config pkg.WildFireConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+
+	return &config
+}
+
+func TestConfigIncludes(t *testing.T) {
+	dir := t.TempDir()
+	pkg.SetIncludeCacheDir(filepath.Join(dir, "cache"))
+	defer pkg.SetIncludeCacheDir("")
+
+	t.Run("should include projects and groups from a local file", func(t *testing.T) {
+		cfgFile := filepath.Join(dir, "file.wildfire.yaml")
+		writeTestFile(t, filepath.Join(dir, "registry.yaml"), registryContent)
+		writeTestFile(t, cfgFile, `
+includes:
+- name: platform
+  path: registry.yaml
+projects:
+  local:
+    name: local
+    type: git
+    url: github.com/local/local
+`)
+		config, err := pkg.LoadConfigFile(cfgFile)
+		if err != nil {
+			t.Fatalf("LoadConfigFile should not have returned an error. Error: %s", err)
+		}
+
+		if len(config.Projects) != 2 || config.Groups["platform"] == nil {
+			t.Errorf("Included entries were not merged. Received projects %v groups %v", config.Projects, config.Groups)
+		}
+
+		if source := config.Source("projects", "shared"); source != "include:platform" {
+			t.Errorf("Invalid source of included project. Expected '%s' received '%s'", "include:platform", source)
+		}
+	})
+
+	t.Run("should include projects from a git repository", func(t *testing.T) {
+		repository := filepath.Join(dir, "registry-repository")
+		createRegistryRepository(t, repository, registryContent)
+
+		cfgFile := filepath.Join(dir, "git.wildfire.yaml")
+		writeTestFile(t, cfgFile, "includes:\n- git: "+repository+"\n")
+		config, err := pkg.LoadConfigFile(cfgFile)
+		if err != nil {
+			t.Fatalf("LoadConfigFile should not have returned an error. Error: %s", err)
+		}
+
+		if _, ok := config.Projects["shared"]; ok == false {
+			t.Errorf("Project was not included from git repository. Received %v", config.Projects)
+		}
+
+		if _, err := pkg.RefreshIncludes(pkg.NewFileConfigStore(cfgFile), false); err != nil {
+			t.Errorf("RefreshIncludes should not have returned an error. Error: %s", err)
+		}
+	})
+
+	t.Run("should include projects from a tag or a commit of a git repository", func(t *testing.T) {
+		repositoryDir := filepath.Join(dir, "ref-repository")
+		createRegistryRepository(t, repositoryDir, registryContent)
+
+		repository, err := git.PlainOpen(repositoryDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		head, err := repository.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repository.CreateTag("v1.0.0", head.Hash(), nil); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, ref := range []string{"v1.0.0", head.Hash().String()} {
+			include := pkg.IncludeConfig{Git: repositoryDir, Ref: ref}
+			path, err := include.Resolve(dir, true)
+			if err != nil {
+				t.Errorf("Resolve should not have returned an error for ref '%s'. Error: %s", ref, err)
+				continue
+			}
+
+			if data, _ := ioutil.ReadFile(path); string(data) != registryContent {
+				t.Errorf("The registry was not checked out from ref '%s'. Received %s", ref, data)
+			}
+		}
+
+		include := pkg.IncludeConfig{Git: repositoryDir, Ref: "missing"}
+		if _, err := include.Resolve(dir, true); err == nil {
+			t.Error("Resolve should have returned an error for a ref which does not exist")
+		}
+	})
+
+	t.Run("should include projects from a URL", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			_, _ = w.Write([]byte(registryContent))
+		}))
+		defer server.Close()
+
+		cfgFile := filepath.Join(dir, "url.wildfire.yaml")
+		writeTestFile(t, cfgFile, "includes:\n- url: "+server.URL+"/registry.yaml\n")
+		for i := 0; i < 2; i++ {
+			config, err := pkg.LoadConfigFile(cfgFile)
+			if err != nil {
+				t.Fatalf("LoadConfigFile should not have returned an error. Error: %s", err)
+			}
+
+			if _, ok := config.Projects["shared"]; ok == false {
+				t.Errorf("Project was not included from URL. Received %v", config.Projects)
+			}
+		}
+
+		if requests != 1 {
+			t.Errorf("The included file should have been downloaded once and then cached. Requests %d", requests)
+		}
+
+		include := pkg.IncludeConfig{URL: server.URL + "/registry.yaml"}
+		if _, err := include.Resolve(dir, true); err != nil || requests != 2 {
+			t.Errorf("The included file should have been downloaded again when refreshed. Requests %d Error: %v", requests, err)
+		}
+
+		cached, _ := filepath.Glob(filepath.Join(dir, "cache", "*"))
+		for _, file := range cached {
+			if info, err := os.Stat(file); err == nil && info.IsDir() == false && strings.HasSuffix(file, ".yaml") == false {
+				t.Errorf("Only the included files should have been cached. Found '%s'", file)
+			}
+		}
+	})
+
+	t.Run("should not write included entries unless they were changed", func(t *testing.T) {
+		cfgFile := filepath.Join(dir, "save.wildfire.yaml")
+		writeTestFile(t, filepath.Join(dir, "registry.yaml"), registryContent)
+		writeTestFile(t, cfgFile, "includes:\n- path: registry.yaml\n")
+		config, _ := pkg.LoadConfigFile(cfgFile)
+		_ = config.SaveConfig()
+
+		if saved := readConfigFile(t, cfgFile); len(saved.Groups) != 0 || len(saved.Projects) != 0 {
+			t.Errorf("Included entries should not have been written. Received %+v", saved)
+		}
+
+		config, _ = pkg.LoadConfigFile(cfgFile)
+		config.Projects["shared"].URL = "github.com/fork/shared"
+		_ = config.SaveConfig()
+
+		saved := readConfigFile(t, cfgFile)
+		if saved.Projects["shared"] == nil || saved.Projects["shared"].URL != "github.com/fork/shared" {
+			t.Errorf("Changed included project should have been written. Received %+v", saved.Projects)
+		}
+		if len(saved.Includes) != 1 {
+			t.Errorf("Includes should have been kept in the configuration. Received %+v", saved.Includes)
+		}
+	})
+
+	t.Run("should return an error if the include is invalid", func(t *testing.T) {
+		tests := []pkg.IncludeConfig{
+			{},
+			{Path: "registry.yaml", URL: "https://example.com/registry.yaml"},
+			{Path: "missing.yaml"},
+			{Git: filepath.Join(dir, "registry-repository"), File: "../../registry.yaml"},
+		}
+
+		for _, include := range tests {
+			if _, err := include.Resolve(dir, false); err == nil {
+				t.Errorf("Resolve should have returned an error for include %+v", include)
+			}
+		}
+	})
+}
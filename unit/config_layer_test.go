@@ -0,0 +1,189 @@
+package unit_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"wildfire/pkg"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigLayers(t *testing.T) {
+	dir := t.TempDir()
+	globalFile := filepath.Join(dir, "global.yaml")
+	teamFile := filepath.Join(dir, "team.yaml")
+	localFile := filepath.Join(dir, "local.yaml")
+
+	setup := func(t *testing.T) *pkg.FileConfigStore {
+		for _, file := range []string{globalFile, teamFile, localFile} {
+			_ = deleteConfig(file)
+		}
+
+		writeTestFile(t, globalFile, `
+projects:
+  foo:
+    name: foo
+    type: git
+    url: github.com/global/foo
+groups:
+  mine:
+  - foo
+`)
+		writeTestFile(t, teamFile, `
+projects:
+  foo:
+    name: foo
+    type: gitlab
+    url: gitlab.com/team/foo
+  bar:
+    name: bar
+    type: git
+    url: github.com/team/bar
+`)
+		writeTestFile(t, localFile, `
+projects:
+  zaz:
+    name: zaz
+    type: git
+    url: github.com/local/zaz
+`)
+		store := pkg.NewFileConfigStore(localFile)
+		store.SetLayers(
+			pkg.ConfigLayer{Name: pkg.ConfigLayerGlobal, Path: globalFile},
+			pkg.ConfigLayer{Name: pkg.ConfigLayerTeam, Path: teamFile},
+		)
+
+		return store
+	}
+
+	t.Run("Load", func(t *testing.T) {
+		t.Run("should merge the layers with later layers taking precedence", func(t *testing.T) {
+			config, err := setup(t).Load()
+			if err != nil {
+				t.Fatalf("Load should not have returned an error. Error: %s", err)
+			}
+
+			if len(config.Projects) != 3 {
+				t.Errorf("Invalid number of projects. Expected '%d' received '%d'", 3, len(config.Projects))
+			}
+
+			if config.Projects["foo"].Type != pkg.ProjectTypeGitLab {
+				t.Errorf("Project 'foo' should have been overridden by the team layer. Received '%+v'", config.Projects["foo"])
+			}
+
+			sources := []struct {
+				Section  string
+				Name     string
+				Expected string
+			}{
+				{"projects", "foo", pkg.ConfigLayerTeam},
+				{"projects", "bar", pkg.ConfigLayerTeam},
+				{"projects", "zaz", pkg.ConfigLayerLocal},
+				{"groups", "mine", pkg.ConfigLayerGlobal},
+			}
+			for _, test := range sources {
+				if source := config.Source(test.Section, test.Name); source != test.Expected {
+					t.Errorf("Invalid source for '%s.%s'. Expected '%s' received '%s'", test.Section, test.Name, test.Expected, source)
+				}
+			}
+		})
+	})
+
+	t.Run("SaveConfig", func(t *testing.T) {
+		t.Run("should write entries to the layer they were loaded from", func(t *testing.T) {
+			config, _ := setup(t).Load()
+			ps := pkg.NewProjectService(config)
+			gs := pkg.NewGroupService(config)
+			ps.RemoveProject("bar")
+			_, _ = gs.AddProject(gs.GetGroup("mine"), "zaz")
+			_, _ = ps.AddProject("new", "github.com/local/new", pkg.ProjectTypeGit)
+
+			if err := config.SaveConfig(); err != nil {
+				t.Fatalf("SaveConfig should not have returned an error. Error: %s", err)
+			}
+
+			global := loadLayer(t, globalFile)
+			if len(global.Groups["mine"].Projects) != 2 {
+				t.Errorf("Group 'mine' was not updated in the global layer. Received '%v'", *global.Groups["mine"])
+			}
+
+			team := loadLayer(t, teamFile)
+			if _, ok := team.Projects["bar"]; ok {
+				t.Error("Project 'bar' was not removed from the team layer")
+			}
+
+			local := loadLayer(t, localFile)
+			if _, ok := local.Projects["new"]; ok == false {
+				t.Error("New project was not written to the local layer")
+			}
+			if len(local.Groups) != 0 || len(local.Projects) != 2 {
+				t.Errorf("Local layer should only contain local entries. Received '%+v'", local)
+			}
+		})
+
+		t.Run("should write new entries to the selected write layer", func(t *testing.T) {
+			store := setup(t)
+			store.SetWriteLayer(pkg.ConfigLayerTeam)
+
+			config, _ := store.Load()
+			_, _ = pkg.NewProjectService(config).AddProject("new", "github.com/team/new", pkg.ProjectTypeGit)
+
+			if err := config.SaveConfig(); err != nil {
+				t.Fatalf("SaveConfig should not have returned an error. Error: %s", err)
+			}
+
+			if _, ok := loadLayer(t, teamFile).Projects["new"]; ok == false {
+				t.Error("New project was not written to the team layer")
+			}
+		})
+
+		t.Run("should not rewrite layers which did not change", func(t *testing.T) {
+			config, _ := setup(t).Load()
+			_ = config.SaveConfig()
+
+			if backups, _ := pkg.GetConfigBackups(globalFile); len(backups) != 0 {
+				t.Errorf("Unchanged global layer should not have been written. Found backups %v", backups)
+			}
+		})
+	})
+
+	t.Run("DiscoverConfigLayers", func(t *testing.T) {
+		t.Run("should find the team configuration in a parent directory", func(t *testing.T) {
+			repo := filepath.Join(dir, "repo")
+			sub := filepath.Join(repo, "a", "b")
+			writeTestFile(t, filepath.Join(repo, pkg.TeamConfigFileName), "projects: {}\n")
+			_ = os.MkdirAll(sub, 0755)
+
+			var team *pkg.ConfigLayer
+			for _, layer := range pkg.DiscoverConfigLayers(sub) {
+				if layer.Name == pkg.ConfigLayerTeam {
+					layer := layer
+					team = &layer
+				}
+			}
+
+			if team == nil || team.Path != filepath.Join(repo, pkg.TeamConfigFileName) {
+				t.Errorf("Team configuration was not discovered. Received '%+v'", team)
+			}
+		})
+	})
+}
+
+func loadLayer(t *testing.T, path string) *pkg.WildFireConfig {
+	config, err := pkg.LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load layer '%s'. Error: %s", path, err)
+	}
+
+	return config
+}
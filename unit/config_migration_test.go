@@ -0,0 +1,161 @@
+package unit_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"wildfire/pkg"
+)
+
+func TestConfigMigrations(t *testing.T) {
+	t.Run("should be ordered by version and end at the current version", func(t *testing.T) {
+		migrations := pkg.GetConfigMigrations()
+		for index, migration := range migrations {
+			if migration.Version != index+1 {
+				t.Errorf("Migration at index %d has version %d", index, migration.Version)
+			}
+		}
+
+		if migrations[len(migrations)-1].Version != pkg.ConfigVersion {
+			t.Errorf("Last migration does not upgrade to the current version %d", pkg.ConfigVersion)
+		}
+	})
+
+	t.Run("Version 1", func(t *testing.T) {
+		t.Run("should set missing project names and lower case project types", func(t *testing.T) {
+			settings := map[string]interface{}{
+				"projects": map[string]interface{}{
+					"foo": map[string]interface{}{"type": "Git", "url": "github.com/foo"},
+					"bar": map[string]interface{}{"name": "bar", "type": " BitBucket", "url": "bitbucket.org/bar"},
+					"zaz": map[string]interface{}{"name": "zaz", "type": "GitHub", "url": "github.com/zaz"},
+				},
+			}
+
+			applied, err := pkg.MigrateSettings(settings)
+			if err != nil {
+				t.Fatalf("MigrateSettings should not have returned an error. Error: %s", err)
+			}
+
+			if len(applied) == 0 || applied[0].Version != 1 {
+				t.Errorf("Migration to version 1 was not applied. Applied %+v", applied)
+			}
+
+			expected := map[string]interface{}{
+				"foo": map[string]interface{}{"name": "foo", "type": "git", "url": "github.com/foo"},
+				"bar": map[string]interface{}{"name": "bar", "type": "bitbucket", "url": "bitbucket.org/bar"},
+				"zaz": map[string]interface{}{"name": "zaz", "type": "github", "url": "github.com/zaz"},
+			}
+			if !reflect.DeepEqual(expected, settings["projects"]) {
+				t.Errorf("Invalid migrated projects. Expected %+v received %+v", expected, settings["projects"])
+			}
+		})
+	})
+
+	t.Run("Version 2", func(t *testing.T) {
+		t.Run("should convert group project lists to group objects", func(t *testing.T) {
+			settings := map[string]interface{}{
+				"version": 1,
+				"groups": map[string]interface{}{
+					"foo":   []interface{}{"bar", "zaz"},
+					"empty": nil,
+					"bar":   map[string]interface{}{"description": "bar", "projects": []interface{}{"zaz"}},
+				},
+			}
+
+			if _, err := pkg.MigrateSettings(settings); err != nil {
+				t.Fatalf("MigrateSettings should not have returned an error. Error: %s", err)
+			}
+
+			expected := map[string]interface{}{
+				"foo":   map[string]interface{}{"projects": []interface{}{"bar", "zaz"}},
+				"empty": map[string]interface{}{"projects": []interface{}{}},
+				"bar":   map[string]interface{}{"description": "bar", "projects": []interface{}{"zaz"}},
+			}
+			if !reflect.DeepEqual(expected, settings["groups"]) {
+				t.Errorf("Invalid migrated groups. Expected %+v received %+v", expected, settings["groups"])
+			}
+		})
+
+		t.Run("should return an error for groups with an invalid format", func(t *testing.T) {
+			settings := map[string]interface{}{
+				"version": 1,
+				"groups":  map[string]interface{}{"foo": "bar"},
+			}
+
+			if _, err := pkg.MigrateSettings(settings); err == nil {
+				t.Error("MigrateSettings should have returned an error instead it resolved")
+			}
+		})
+	})
+
+	t.Run("MigrateSettings", func(t *testing.T) {
+		t.Run("should not apply migrations to a current configuration", func(t *testing.T) {
+			settings := map[string]interface{}{"version": pkg.ConfigVersion}
+
+			applied, err := pkg.MigrateSettings(settings)
+			if err != nil || len(applied) != 0 {
+				t.Errorf("No migrations should have been applied. Applied %+v Error: %v", applied, err)
+			}
+		})
+
+		t.Run("should set the configuration version", func(t *testing.T) {
+			settings := map[string]interface{}{}
+
+			_, _ = pkg.MigrateSettings(settings)
+
+			if settings["version"] != pkg.ConfigVersion {
+				t.Errorf("Invalid configuration version. Expected %d received %v", pkg.ConfigVersion, settings["version"])
+			}
+		})
+
+		t.Run("should return an error if the configuration version is newer than supported", func(t *testing.T) {
+			_, err := pkg.MigrateSettings(map[string]interface{}{"version": pkg.ConfigVersion + 1})
+			if err == nil {
+				t.Error("MigrateSettings should have returned an error instead it resolved")
+			}
+		})
+	})
+
+	t.Run("MigrateConfigFile", func(t *testing.T) {
+		cfgFile := filepath.Join(t.TempDir(), ".wildfire.yaml")
+		content := "projects:\n  foo:\n    type: Git\n    url: github.com/foo\n"
+		writeTestFile(t, cfgFile, content)
+
+		t.Run("should only return the diff on dry run", func(t *testing.T) {
+			_, diff, err := pkg.MigrateConfigFile(cfgFile, true)
+			if err != nil {
+				t.Fatalf("MigrateConfigFile should not have returned an error. Error: %s", err)
+			}
+
+			expectedVersion := fmt.Sprintf("+ version: %d", pkg.ConfigVersion)
+			for _, expected := range []string{"-     type: Git", "+     type: git", "+     name: foo", expectedVersion} {
+				if strings.Contains(diff, expected) == false {
+					t.Errorf("Expected diff to contain '%s'. Diff:\n%s", expected, diff)
+				}
+			}
+
+			if data, _ := ioutil.ReadFile(cfgFile); string(data) != content {
+				t.Errorf("Configuration file should not have been changed. Received:\n%s", data)
+			}
+		})
+
+		t.Run("should write the migrated configuration", func(t *testing.T) {
+			applied, _, err := pkg.MigrateConfigFile(cfgFile, false)
+			if err != nil || len(applied) == 0 {
+				t.Fatalf("MigrateConfigFile should have applied migrations. Error: %v", err)
+			}
+
+			applied, _, _ = pkg.MigrateConfigFile(cfgFile, false)
+			if len(applied) != 0 {
+				t.Errorf("Migrated configuration should be up to date. Applied %+v", applied)
+			}
+
+			if backups, _ := pkg.GetConfigBackups(cfgFile); len(backups) != 1 {
+				t.Errorf("The original configuration should have been kept as a backup. Found %+v", backups)
+			}
+		})
+	})
+}
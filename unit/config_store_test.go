@@ -0,0 +1,113 @@
+package unit_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"wildfire/cmd/project"
+	"wildfire/pkg"
+)
+
+func TestConfigStore(t *testing.T) {
+	t.Run("MemoryConfigStore", func(t *testing.T) {
+		t.Run("should only store the changes which are saved", func(t *testing.T) {
+			store := pkg.NewMemoryConfigStore(nil)
+
+			config, err := store.Load()
+			if err != nil {
+				t.Fatalf("Failed to load configuration. Error: %s", err)
+			}
+			config.Projects["foo"] = &pkg.ProjectConfig{Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo"}
+
+			if loaded, _ := store.Load(); len(loaded.Projects) != 0 {
+				t.Error("Changes which have not been saved should not be loaded")
+			}
+
+			if err := store.Save(config); err != nil {
+				t.Fatalf("Failed to save configuration. Error: %s", err)
+			}
+			config.Projects["foo"].URL = "changed"
+
+			loaded, _ := store.Load()
+			if project := loaded.Projects["foo"]; project == nil || project.URL != "github.com/foo" {
+				t.Errorf("The saved configuration should have been loaded. Received %+v", project)
+			}
+		})
+	})
+
+	t.Run("FileConfigStore", func(t *testing.T) {
+		t.Run("should keep configurations of different files apart", func(t *testing.T) {
+			dir := t.TempDir()
+			first := pkg.NewFileConfigStore(filepath.Join(dir, "first.yaml"))
+			second := pkg.NewFileConfigStore(filepath.Join(dir, "second.yaml"))
+
+			config, _ := first.Load()
+			config.Projects["foo"] = &pkg.ProjectConfig{Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo"}
+			if err := first.Save(config); err != nil {
+				t.Fatalf("Failed to save configuration. Error: %s", err)
+			}
+
+			if loaded, _ := first.Load(); len(loaded.Projects) != 1 {
+				t.Errorf("The configuration should have been saved to the file. Received %+v", loaded.Projects)
+			}
+			if loaded, _ := second.Load(); len(loaded.Projects) != 0 {
+				t.Errorf("The configuration of another file should not have been changed. Received %+v", loaded.Projects)
+			}
+		})
+
+		t.Run("should merge the layers of the store and write new entries to its write layer", func(t *testing.T) {
+			dir := t.TempDir()
+			teamFile := filepath.Join(dir, "team.yaml")
+			writeTestFile(t, teamFile, "projects:\n  shared:\n    name: shared\n    type: git\n    url: github.com/shared\n")
+
+			layered := pkg.NewFileConfigStore(filepath.Join(dir, "layered.yaml"))
+			layered.SetLayers(pkg.ConfigLayer{Name: pkg.ConfigLayerTeam, Path: teamFile})
+			layered.SetWriteLayer(pkg.ConfigLayerTeam)
+			single := pkg.NewFileConfigStore(filepath.Join(dir, "single.yaml"))
+
+			config, _ := layered.Load()
+			config.Projects["new"] = &pkg.ProjectConfig{Name: "new", Type: pkg.ProjectTypeGit, URL: "github.com/new"}
+			if err := layered.Save(config); err != nil {
+				t.Fatalf("Failed to save configuration. Error: %s", err)
+			}
+
+			if team := readConfigFile(t, teamFile); team.Projects["shared"] == nil || team.Projects["new"] == nil {
+				t.Errorf("The new project should have been written to the team layer. Received %+v", team.Projects)
+			}
+			if loaded, _ := single.Load(); len(loaded.Projects) != 0 {
+				t.Errorf("The layers of another store should not have been merged. Received %+v", loaded.Projects)
+			}
+			if layers := pkg.GetStoreConfigLayers(layered); len(layers) != 2 || layers[0].Path != teamFile {
+				t.Errorf("Invalid layers of the store. Received %+v", layers)
+			}
+		})
+
+		t.Run("should return an error without a file", func(t *testing.T) {
+			store := pkg.NewFileConfigStore("")
+
+			if _, err := store.Lock(); err == nil {
+				t.Error("Lock should have returned an error")
+			}
+			if _, err := store.Load(); err == nil {
+				t.Error("Load should have returned an error")
+			}
+			if err := store.Save(&pkg.WildFireConfig{}); err == nil {
+				t.Error("Save should have returned an error")
+			}
+		})
+	})
+
+	t.Run("should run the commands with the store of their context", func(t *testing.T) {
+		store := pkg.NewMemoryConfigStore(nil)
+
+		cmd := project.NewAddProjectCmd()
+		cmd.SetArgs([]string{"foo", "git", "github.com/foo"})
+		if err := cmd.ExecuteContext(pkg.WithConfigStore(context.Background(), store)); err != nil {
+			t.Fatalf("Command should not have returned an error. Error: %s", err)
+		}
+
+		if config, _ := store.Load(); config.Projects["foo"] == nil {
+			t.Error("The project should have been saved to the store")
+		}
+	})
+}
@@ -1,19 +1,18 @@
 package unit_test
 
 import (
-	"fmt"
-	"github.com/spf13/viper"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"wildfire/pkg"
 )
 
 
 
-func TestGetConfig(t *testing.T) {
+func TestLoadConfigFile(t *testing.T) {
 	t.Run("should return a wildfire config even if file does not exist", func(t *testing.T) {
-		_ = setConfig("missing")
-		config := pkg.GetConfig()
+		config := loadConfigFile(t, getConfigFilePath("missing.wildfire.yaml"))
 
 		if len(config.Projects) > 0 {
 			t.Error("Expected config projects to be empty. Found ", len(config.Projects), " number of projects")
@@ -25,14 +24,7 @@ func TestGetConfig(t *testing.T) {
 	})
 
 	t.Run("should return a wildfire config with filled out projects", func(t *testing.T) {
-		cfgFile := getConfigFilePath("projects.wildfire.yaml")
-
-		err := setConfig(cfgFile)
-		if err != nil {
-			t.Error(err)
-		}
-
-		config := pkg.GetConfig()
+		config := loadConfigFile(t, getConfigFilePath("projects.wildfire.yaml"))
 
 		if len(config.Projects) != 5 {
 			t.Error("Invalid number of projects found. Expected 5 received ", len(config.Projects))
@@ -43,17 +35,25 @@ func TestGetConfig(t *testing.T) {
 		}
 	})
 
-	t.Run("should return an empty wildfire config if selected config is invalid", func(t *testing.T) {
-		fmt.Println(viper.ConfigFileUsed())
-		_ = setConfig("invalid.wildfire.yaml")
-		config := pkg.GetConfig()
+	t.Run("should return an error if the configuration can not be decoded", func(t *testing.T) {
+		cfgFile := filepath.Join(t.TempDir(), "invalid.wildfire.yaml")
+		writeTestFile(t, cfgFile, "projects: foo\n")
 
-		if len(config.Projects) > 0 {
-			t.Error("Expected config projects to be empty. Found ", len(config.Projects), " number of projects")
+		_, err := pkg.LoadConfigFile(cfgFile)
+		if err == nil {
+			t.Error("LoadConfigFile should have returned an error instead it resolved")
 		}
+	})
 
-		if len(config.Groups) > 0 {
-			t.Error("Expected config groups to be empty. Found ", len(config.Groups), " number of groups")
+	t.Run("should read groups stored as project lists", func(t *testing.T) {
+		cfgFile := filepath.Join(t.TempDir(), "groups.wildfire.yaml")
+		writeTestFile(t, cfgFile, "groups:\n  foo:\n  - bar\n  - zaz\n")
+
+		config := loadConfigFile(t, cfgFile)
+
+		expected := []string{"bar", "zaz"}
+		if !reflect.DeepEqual(expected, config.Groups["foo"].Projects) {
+			t.Errorf("Invalid group projects. Expected %+v received %+v", expected, config.Groups["foo"].Projects)
 		}
 	})
 }
@@ -68,11 +68,7 @@ func TestWildFireConfig(t *testing.T) {
 				t.Errorf("Failed to remove old test configuration file '%s'", cfgFile)
 			}
 
-			_ = setConfig(cfgFile)
-			config := &pkg.WildFireConfig{
-				Projects: map[string]*pkg.ProjectConfig{},
-				Groups:   map[string]*pkg.GroupConfig{},
-			}
+			config := loadConfigFile(t, cfgFile)
 
 			config.Groups["foo"] = &pkg.GroupConfig{}
 			config.Projects["example"] = &pkg.ProjectConfig{
@@ -87,7 +83,7 @@ func TestWildFireConfig(t *testing.T) {
 				t.Errorf("Failed to save configuration file '%s'. Error: %s", cfgFile, err)
 			}
 
-			updatedConfig := pkg.GetConfig()
+			updatedConfig := loadConfigFile(t, cfgFile)
 
 			if len(updatedConfig.Projects) != 1 {
 				t.Errorf(
@@ -106,5 +102,13 @@ func TestWildFireConfig(t *testing.T) {
 				t.Error("Failed to clear test env")
 			}
 		})
+
+		t.Run("should return an error if the configuration was not loaded from a file", func(t *testing.T) {
+			config := &pkg.WildFireConfig{Projects: map[string]*pkg.ProjectConfig{}}
+
+			if err := config.SaveConfig(); err == nil {
+				t.Error("SaveConfig should have returned an error instead it resolved")
+			}
+		})
 	})
 }
@@ -0,0 +1,138 @@
+package unit_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"wildfire/pkg"
+)
+
+func TestWildFireConfig_Validate(t *testing.T) {
+	t.Run("should not return issues for a valid configuration", func(t *testing.T) {
+		config := &pkg.WildFireConfig{
+			Projects: map[string]*pkg.ProjectConfig{
+				"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "git@github.com:example/foo.git"},
+				"bar": {Name: "bar", Type: pkg.ProjectTypeGitLab, URL: "https://gitlab.com/example/bar"},
+			},
+			Groups: map[string]*pkg.GroupConfig{
+				"foo": {Projects: []string{"foo", "bar"}},
+			},
+		}
+
+		if issues := config.Validate(); len(issues) != 0 {
+			t.Errorf("Expected no issues to be found. Found %v", issues)
+		}
+	})
+
+	t.Run("should report invalid projects", func(t *testing.T) {
+		tests := []struct {
+			Project  *pkg.ProjectConfig
+			Expected string
+			Fixable  bool
+		}{
+			{&pkg.ProjectConfig{Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/foo"}, "project 'foo' has mismatching name 'bar'", true},
+			{&pkg.ProjectConfig{Name: "foo", Type: "svn", URL: "github.com/foo"}, "project 'foo' has invalid type 'svn'", false},
+			{&pkg.ProjectConfig{Name: "foo", Type: pkg.ProjectTypeGit, URL: ""}, "project 'foo' has no URL", false},
+			{&pkg.ProjectConfig{Name: "foo", Type: pkg.ProjectTypeGit, URL: "https://[github.com/foo"}, "project 'foo' has invalid URL", false},
+			{nil, "project 'foo' has no configuration", true},
+		}
+
+		for _, test := range tests {
+			config := &pkg.WildFireConfig{
+				Projects: map[string]*pkg.ProjectConfig{"foo": test.Project},
+				Groups:   map[string]*pkg.GroupConfig{},
+			}
+
+			issues := config.Validate()
+			if len(issues) != 1 {
+				t.Errorf("Expected exactly one issue for '%s'. Found %v", test.Expected, issues)
+				continue
+			}
+
+			if strings.HasPrefix(issues[0].String(), test.Expected) == false {
+				t.Errorf("Unexpected issue found. Expected '%s' received '%s'", test.Expected, issues[0])
+			}
+
+			if issues[0].Fixable() != test.Fixable {
+				t.Errorf("Issue '%s' has invalid fixable state. Expected '%t'", issues[0], test.Fixable)
+			}
+		}
+	})
+
+	t.Run("should report duplicate and missing group members", func(t *testing.T) {
+		config := &pkg.WildFireConfig{
+			Projects: map[string]*pkg.ProjectConfig{
+				"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo"},
+			},
+			Groups: map[string]*pkg.GroupConfig{
+				"foo": {Projects: []string{"foo", "foo", "bar", "bar"}},
+			},
+		}
+
+		var messages []string
+		for _, issue := range config.Validate() {
+			messages = append(messages, issue.String())
+		}
+
+		expected := []string{
+			"group 'foo' contains project 'foo' more than once",
+			"group 'foo' contains project 'bar' more than once",
+			"group 'foo' references project 'bar' which does not exist",
+		}
+		if !reflect.DeepEqual(expected, messages) {
+			t.Errorf("Unexpected issues found. Expected %v received %v", expected, messages)
+		}
+	})
+
+	t.Run("should report included groups which do not exist or include each other", func(t *testing.T) {
+		config := &pkg.WildFireConfig{
+			Groups: map[string]*pkg.GroupConfig{
+				"foo": {Groups: []string{"bar", "missing"}},
+				"bar": {Groups: []string{"foo"}},
+			},
+		}
+
+		var messages []string
+		for _, issue := range config.Validate() {
+			messages = append(messages, issue.Message)
+		}
+
+		expected := []string{
+			"group 'bar' includes itself: bar -> foo -> bar",
+			"group 'foo' includes group 'missing' which does not exist",
+			"group 'foo' includes itself: foo -> bar -> foo",
+		}
+		if !reflect.DeepEqual(expected, messages) {
+			t.Errorf("Invalid issues returned. Expected %+v received %+v", expected, messages)
+		}
+	})
+
+	t.Run("should repair fixable issues", func(t *testing.T) {
+		config := &pkg.WildFireConfig{
+			Projects: map[string]*pkg.ProjectConfig{
+				"foo": {Name: "zaz", Type: pkg.ProjectTypeGit, URL: "github.com/foo"},
+				"baz": nil,
+			},
+			Groups: map[string]*pkg.GroupConfig{
+				"foo": {Projects: []string{"foo", "bar", "foo", "bar"}},
+			},
+		}
+
+		for _, issue := range config.Validate() {
+			issue.Fix()
+		}
+
+		if issues := config.Validate(); len(issues) != 0 {
+			t.Errorf("Expected all issues to be fixed. Found %v", issues)
+		}
+
+		if config.Projects["foo"].Name != "foo" {
+			t.Errorf("Project name was not fixed. Expected '%s' received '%s'", "foo", config.Projects["foo"].Name)
+		}
+
+		expectedGroup := pkg.GroupConfig{Projects: []string{"foo"}}
+		if !reflect.DeepEqual(expectedGroup, *config.Groups["foo"]) {
+			t.Errorf("Group was not fixed. Expected %v received %v", expectedGroup, *config.Groups["foo"])
+		}
+	})
+}
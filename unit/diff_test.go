@@ -0,0 +1,32 @@
+package unit_test
+
+import (
+	"testing"
+	"wildfire/pkg"
+)
+
+func TestDiffLines(t *testing.T) {
+	t.Run("should return an empty diff for equal texts", func(t *testing.T) {
+		if diff := pkg.DiffLines("foo\nbar\n", "foo\nbar\n"); diff != "" {
+			t.Errorf("Expected empty diff. Received:\n%s", diff)
+		}
+	})
+
+	t.Run("should mark removed and added lines", func(t *testing.T) {
+		diff := pkg.DiffLines("foo\nbar\nzaz\n", "foo\nbaz\nzaz\nfar\n")
+
+		expected := "  foo\n- bar\n+ baz\n  zaz\n+ far\n"
+		if diff != expected {
+			t.Errorf("Invalid diff. Expected:\n%s\nReceived:\n%s", expected, diff)
+		}
+	})
+
+	t.Run("should only include context around changes", func(t *testing.T) {
+		diff := pkg.DiffLines("1\n2\n3\n4\n5\n6\n7\n8\n9\n", "1\n2\n3\n4\n5\n6\n7\n8\nnine\n")
+
+		expected := "@@\n  6\n  7\n  8\n- 9\n+ nine\n"
+		if diff != expected {
+			t.Errorf("Invalid diff. Expected:\n%s\nReceived:\n%s", expected, diff)
+		}
+	})
+}
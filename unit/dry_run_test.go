@@ -0,0 +1,173 @@
+package unit_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"wildfire/cmd/edit"
+	"wildfire/cmd/exec"
+	"wildfire/cmd/project"
+	"wildfire/cmd/serve"
+	"wildfire/pkg"
+)
+
+func TestDryRun(t *testing.T) {
+	dryRun := pkg.WithRunOptions(context.Background(), &pkg.RunOptions{DryRun: true})
+
+	workspace := t.TempDir()
+	for _, name := range []string{"foo", "bar"} {
+		initTestRepository(t, filepath.Join(workspace, name), name)
+	}
+
+	newConfigStore := func() *pkg.MemoryConfigStore {
+		return pkg.NewMemoryConfigStore(&pkg.WildFireConfig{
+			Projects: map[string]*pkg.ProjectConfig{
+				"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/org/foo"},
+				"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/org/bar"},
+			},
+			Workspaces: map[string]*pkg.WorkspaceConfig{
+				"backend": {Path: workspace, Projects: []string{"foo", "bar"}},
+			},
+		})
+	}
+
+	t.Run("should show the configuration changes instead of saving them", func(t *testing.T) {
+		store := newConfigStore()
+
+		var out bytes.Buffer
+		cmd := project.NewAddProjectCmd()
+		cmd.SetArgs([]string{"zaz", "git", "github.com/org/zaz"})
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.ExecuteContext(pkg.WithConfigStore(dryRun, store)); err != nil {
+			t.Fatalf("Command should not have returned an error. Error: %s", err)
+		}
+
+		if config, _ := store.Load(); config.Projects["zaz"] != nil {
+			t.Error("The project should not have been saved to the store")
+		}
+		if strings.Contains(out.String(), "+     url: github.com/org/zaz") == false {
+			t.Errorf("The diff should contain the added project. Received:\n%s", out.String())
+		}
+	})
+
+	t.Run("should show the commands and pushes instead of running them", func(t *testing.T) {
+		historyStore := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+		args := []string{"backend", "--", "git", "push", "-u"}
+
+		var out bytes.Buffer
+		cmd := exec.NewExecCmd()
+		cmd.SetArgs(args)
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		ctx := pkg.WithHistory(pkg.WithConfigStore(dryRun, newConfigStore()), historyStore, args)
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			t.Fatalf("Command should not have returned an error. Error: %s", err)
+		}
+
+		branch, _ := osexec.Command("git", "-C", filepath.Join(workspace, "foo"), "rev-parse", "--abbrev-ref", "HEAD").Output()
+		plan := map[string]string{}
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n")[1:] {
+			fields := strings.Fields(line)
+			plan[fields[0]+" "+fields[1]] = strings.Join(fields[3:], " ")
+		}
+
+		expected := map[string]string{
+			"foo run":  "git push -u",
+			"foo push": "branch '" + strings.TrimSpace(string(branch)) + "' to 'origin'",
+			"bar run":  "git push -u",
+			"bar push": "branch '" + strings.TrimSpace(string(branch)) + "' to 'origin'",
+		}
+		if reflect.DeepEqual(plan, expected) == false {
+			t.Errorf("Invalid plan. Expected %v received %v", expected, plan)
+		}
+
+		if entries, _ := historyStore.List(); len(entries) != 0 {
+			t.Errorf("Dry runs should not be recorded. Received %+v", entries)
+		}
+		if checkpoints := pkg.FindCheckpoints(workspace); len(checkpoints) != 0 {
+			t.Errorf("Dry runs should not be checkpointed. Received %+v", checkpoints)
+		}
+	})
+
+	t.Run("should report the edited files without writing them", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := edit.NewAddLineCmd()
+		cmd.SetArgs([]string{"backend", "docs/notes.txt", "reviewed"})
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.ExecuteContext(pkg.WithConfigStore(dryRun, newConfigStore())); err != nil {
+			t.Fatalf("Command should not have returned an error. Error: %s", err)
+		}
+
+		if strings.Contains(out.String(), "foo: docs/notes.txt") == false {
+			t.Errorf("The file should have been reported as changed. Received:\n%s", out.String())
+		}
+		if _, err := os.Stat(filepath.Join(workspace, "foo", "docs")); os.IsNotExist(err) == false {
+			t.Error("Nothing should have been written to the clone")
+		}
+	})
+
+	t.Run("should list the clones a rename would move without moving them", func(t *testing.T) {
+		store := newConfigStore()
+
+		var out bytes.Buffer
+		cmd := project.NewRenameProjectCmd()
+		cmd.SetArgs([]string{"foo", "baz"})
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.ExecuteContext(pkg.WithConfigStore(dryRun, store)); err != nil {
+			t.Fatalf("Command should not have returned an error. Error: %s", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(workspace, "foo")); err != nil {
+			t.Errorf("The clone should not have been moved. Error: %s", err)
+		}
+		if _, err := os.Stat(filepath.Join(workspace, "baz")); os.IsNotExist(err) == false {
+			t.Error("The clone should not have been moved to the new path")
+		}
+		if config, _ := store.Load(); config.Projects["foo"] == nil {
+			t.Error("The project should not have been renamed in the store")
+		}
+
+		move := strings.Join([]string{"foo", "move", filepath.Join(workspace, "foo"), filepath.Join(workspace, "baz")}, " ")
+		lines := strings.Split(out.String(), "\n")
+		if len(lines) < 2 || strings.Join(strings.Fields(lines[1]), " ") != move {
+			t.Errorf("The move of the clone should have been planned. Received:\n%s", out.String())
+		}
+	})
+
+	t.Run("should not serve the API", func(t *testing.T) {
+		cmd := serve.NewServeCmd()
+		cmd.SetArgs([]string{"--addr", "127.0.0.1:0", "--no-token"})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.ExecuteContext(pkg.WithConfigStore(dryRun, newConfigStore())); err == nil {
+			t.Error("ServeCmd should have returned an error instead it served the API")
+		}
+	})
+
+	t.Run("should describe the refs pushed by git push", func(t *testing.T) {
+		clone := pkg.Clone{Project: "foo", Path: filepath.Join(workspace, "foo")}
+
+		for args, expected := range map[string]string{
+			"push upstream feature:main": "'feature' to 'upstream'",
+			"push --force origin :old":   "deletion of 'old' to 'origin'",
+			"push --tags":                "every tag to 'origin'",
+			"push -o ci.skip --all fork": "every branch to 'fork'",
+		} {
+			changes := pkg.PlanCommand(clone, "git", strings.Fields(args)...)
+			if len(changes) != 2 || changes[1].Action != pkg.PlannedActionPush || changes[1].Detail != expected {
+				t.Errorf("Invalid plan of 'git %s'. Expected %s received %+v", args, expected, changes)
+			}
+		}
+
+		if changes := pkg.PlanCommand(clone, "make", "push"); len(changes) != 1 {
+			t.Errorf("Only git push should push. Received %+v", changes)
+		}
+	})
+}
@@ -0,0 +1,311 @@
+package unit_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"wildfire/pkg"
+)
+
+func readTestFile(t *testing.T, path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestSetKeyEditor(t *testing.T) {
+	t.Run("should set the key of a YAML file keeping comments and quoting", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestFile(t, filepath.Join(dir, "values.yaml"), "# image\nimage:\n  tag: \"1.0\" # pinned\nports:\n  - 80\n")
+
+		editor := &pkg.SetKeyEditor{File: "values.yaml", Key: "image.tag", Value: "2.0"}
+		changed, err := editor.Edit(dir, false)
+		if err != nil {
+			t.Fatalf("Edit should not have returned an error. Error: %s", err)
+		}
+
+		expected := "# image\nimage:\n  tag: \"2.0\" # pinned\nports:\n  - 80\n"
+		if result := readTestFile(t, filepath.Join(dir, "values.yaml")); result != expected || len(changed) != 1 {
+			t.Errorf("Invalid file. Expected '%s' received '%s'", expected, result)
+		}
+
+		if changed, _ := editor.Edit(dir, false); len(changed) != 0 {
+			t.Errorf("Setting the same value again should not have changed the file. Received %+v", changed)
+		}
+	})
+
+	t.Run("should set keys of a JSON file keeping the order of the keys", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestFile(t, filepath.Join(dir, "package.json"), "{\n    \"name\": \"foo\",\n    \"private\": false\n}\n")
+
+		for _, editor := range []*pkg.SetKeyEditor{
+			{File: "package.json", Key: "private", Value: "true"},
+			{File: "package.json", Key: "scripts.test", Value: "go test <pkg>"},
+			{File: "package.json", Key: "files", Value: "[dist, '1']"},
+		} {
+			if _, err := editor.Edit(dir, false); err != nil {
+				t.Fatalf("Edit should not have returned an error. Error: %s", err)
+			}
+		}
+
+		expected := `{
+    "name": "foo",
+    "private": true,
+    "scripts": {
+        "test": "go test <pkg>"
+    },
+    "files": [
+        "dist",
+        "1"
+    ]
+}
+`
+		if result := readTestFile(t, filepath.Join(dir, "package.json")); result != expected {
+			t.Errorf("Invalid file. Expected '%s' received '%s'", expected, result)
+		}
+	})
+
+	t.Run("should return an error if the key can not be set", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestFile(t, filepath.Join(dir, "values.yaml"), "ports:\n  - 80\nname: foo\n")
+
+		for _, key := range []string{"ports.1", "ports.x", "name.first"} {
+			editor := &pkg.SetKeyEditor{File: "values.yaml", Key: key, Value: "1"}
+			if _, err := editor.Edit(dir, false); err == nil {
+				t.Errorf("Edit should have returned an error for '%s' instead it resolved", key)
+			}
+		}
+
+		editor := &pkg.SetKeyEditor{File: "values.toml", Key: "name", Value: "1"}
+		if _, err := editor.Edit(dir, false); err == nil {
+			t.Error("Edit should have returned an error for an unsupported file instead it resolved")
+		}
+	})
+
+	t.Run("should not change clones without the file", func(t *testing.T) {
+		editor := &pkg.SetKeyEditor{File: "values.yaml", Key: "name", Value: "1"}
+		if changed, err := editor.Edit(t.TempDir(), false); err != nil || len(changed) != 0 {
+			t.Errorf("Edit should not have changed anything. Received %+v %s", changed, err)
+		}
+	})
+}
+
+func TestReplaceEditor(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "main.tf"), "version = \"1.0\"\n")
+	writeTestFile(t, filepath.Join(dir, "modules", "db", "main.tf"), "version = \"1.2\"\n")
+	writeTestFile(t, filepath.Join(dir, "README.md"), "version = \"1.0\"\n")
+	writeTestFile(t, filepath.Join(dir, "modules", "state.tf"), "version = \"1.0\"\x00")
+
+	editor, err := pkg.NewReplaceEditor("**/*.tf", `version = "1\.(\d)"`, `version = "2.$1"`)
+	if err != nil {
+		t.Fatalf("NewReplaceEditor should not have returned an error. Error: %s", err)
+	}
+
+	changed, err := editor.Edit(dir, false)
+	if err != nil {
+		t.Fatalf("Edit should not have returned an error. Error: %s", err)
+	}
+
+	expected := []string{"main.tf", "modules/db/main.tf"}
+	if !reflect.DeepEqual(expected, changed) {
+		t.Errorf("Invalid changed files. Expected %+v received %+v", expected, changed)
+	}
+
+	if result := readTestFile(t, filepath.Join(dir, "modules", "db", "main.tf")); result != "version = \"2.2\"\n" {
+		t.Errorf("Invalid file. Received '%s'", result)
+	}
+
+	if result := readTestFile(t, filepath.Join(dir, "README.md")); result != "version = \"1.0\"\n" {
+		t.Errorf("Files not matching the glob should not have been changed. Received '%s'", result)
+	}
+
+	if _, err := pkg.NewReplaceEditor("*.tf", "(", ""); err == nil {
+		t.Error("NewReplaceEditor should have returned an error instead it resolved")
+	}
+}
+
+func TestDependencyEditor(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), `module foo
+
+require github.com/foo/lib v1.0.0
+
+require (
+	github.com/foo/lib/v2 v2.0.0
+	github.com/foo/lib v1.0.0 // indirect
+)
+
+replace github.com/foo/lib v1.0.0 => ../lib
+
+replace (
+	github.com/foo/lib v1.0.0 => github.com/fork/lib v1.0.2
+)
+`)
+	writeTestFile(t, filepath.Join(dir, "package.json"), `{
+  "name": "lib",
+  "dependencies": {
+    "lib": "^1.0.0"
+  },
+  "devDependencies": {
+    "lib-test": "1.0.0",
+    "lib": "~1.0.0"
+  }
+}
+`)
+	writeTestFile(t, filepath.Join(dir, "requirements.txt"), "Foo_Lib[extra]>=1.0.0 ; python_version > '3'\nfoo-lib-test==1.0.0\n")
+	writeTestFile(t, filepath.Join(dir, "pom.xml"), `<project>
+  <properties>
+    <lib.version>1.0.0</lib.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.foo</groupId>
+      <artifactId>lib</artifactId>
+      <version>${lib.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.bar</groupId>
+      <artifactId>lib</artifactId>
+      <version>1.0.0</version>
+    </dependency>
+  </dependencies>
+</project>
+`)
+
+	t.Run("should bump the dependency in every manifest", func(t *testing.T) {
+		changed, err := (&pkg.DependencyEditor{Name: "github.com/foo/lib", Version: "1.1.0"}).Edit(dir, false)
+		if err != nil || !reflect.DeepEqual([]string{"go.mod"}, changed) {
+			t.Errorf("Invalid changed files. Received %+v %s", changed, err)
+		}
+
+		expected := `module foo
+
+require github.com/foo/lib v1.1.0
+
+require (
+	github.com/foo/lib/v2 v2.0.0
+	github.com/foo/lib v1.1.0 // indirect
+)
+
+replace github.com/foo/lib v1.0.0 => ../lib
+
+replace (
+	github.com/foo/lib v1.0.0 => github.com/fork/lib v1.0.2
+)
+`
+		if result := readTestFile(t, filepath.Join(dir, "go.mod")); result != expected {
+			t.Errorf("Invalid go.mod. Expected '%s' received '%s'", expected, result)
+		}
+	})
+
+	t.Run("should keep the version operators", func(t *testing.T) {
+		changed, err := (&pkg.DependencyEditor{Name: "foo.lib", Version: "1.1.0"}).Edit(dir, false)
+		if err != nil || !reflect.DeepEqual([]string{"requirements.txt"}, changed) {
+			t.Errorf("Invalid changed files. Received %+v %s", changed, err)
+		}
+
+		expected := "Foo_Lib[extra]>=1.1.0 ; python_version > '3'\nfoo-lib-test==1.0.0\n"
+		if result := readTestFile(t, filepath.Join(dir, "requirements.txt")); result != expected {
+			t.Errorf("Invalid requirements.txt. Expected '%s' received '%s'", expected, result)
+		}
+
+		changed, err = (&pkg.DependencyEditor{Name: "lib", Version: "1.1.0"}).Edit(dir, false)
+		if err != nil || !reflect.DeepEqual([]string{"package.json", "pom.xml"}, changed) {
+			t.Errorf("Invalid changed files. Received %+v %s", changed, err)
+		}
+
+		expected = `{
+  "name": "lib",
+  "dependencies": {
+    "lib": "^1.1.0"
+  },
+  "devDependencies": {
+    "lib-test": "1.0.0",
+    "lib": "~1.1.0"
+  }
+}
+`
+		if result := readTestFile(t, filepath.Join(dir, "package.json")); result != expected {
+			t.Errorf("Invalid package.json. Expected '%s' received '%s'", expected, result)
+		}
+	})
+
+	t.Run("should bump maven dependencies by group and artifact", func(t *testing.T) {
+		if _, err := (&pkg.DependencyEditor{Name: "com.foo:lib", Version: "2.0.0"}).Edit(dir, false); err != nil {
+			t.Fatalf("Edit should not have returned an error. Error: %s", err)
+		}
+
+		result := readTestFile(t, filepath.Join(dir, "pom.xml"))
+		for _, expected := range []string{"<lib.version>2.0.0</lib.version>", "<version>${lib.version}</version>", "<version>1.1.0</version>"} {
+			if !strings.Contains(result, expected) {
+				t.Errorf("pom.xml should contain '%s'. Received '%s'", expected, result)
+			}
+		}
+	})
+}
+
+func TestLineEditor(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, ".gitignore"), "*.log\r\nbin")
+
+	t.Run("should add the line once", func(t *testing.T) {
+		editor := &pkg.LineEditor{File: ".gitignore", Line: "dist/"}
+		for i := 0; i < 2; i++ {
+			if _, err := editor.Edit(dir, false); err != nil {
+				t.Fatalf("Edit should not have returned an error. Error: %s", err)
+			}
+		}
+
+		if result := readTestFile(t, filepath.Join(dir, ".gitignore")); result != "*.log\r\nbin\ndist/\n" {
+			t.Errorf("Invalid file. Received '%s'", result)
+		}
+	})
+
+	t.Run("should remove every occurrence of the line", func(t *testing.T) {
+		changed, err := (&pkg.LineEditor{File: ".gitignore", Line: "*.log", Remove: true}).Edit(dir, false)
+		if err != nil || len(changed) != 1 {
+			t.Errorf("Invalid changed files. Received %+v %s", changed, err)
+		}
+
+		if result := readTestFile(t, filepath.Join(dir, ".gitignore")); result != "bin\ndist/\n" {
+			t.Errorf("Invalid file. Received '%s'", result)
+		}
+	})
+
+	t.Run("should create the file when adding a line", func(t *testing.T) {
+		changed, err := (&pkg.LineEditor{File: "config/.keep", Line: "keep"}).Edit(dir, false)
+		if err != nil || len(changed) != 1 || readTestFile(t, filepath.Join(dir, "config", ".keep")) != "keep\n" {
+			t.Errorf("File should have been created. Received %+v %s", changed, err)
+		}
+
+		changed, err = (&pkg.LineEditor{File: "missing", Line: "keep", Remove: true}).Edit(dir, false)
+		if err != nil || len(changed) != 0 {
+			t.Errorf("Removing a line from a missing file should not have changed anything. Received %+v %s", changed, err)
+		}
+	})
+}
+
+func TestEditClones(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "foo", "go.mod"), "module foo\n")
+	writeTestFile(t, filepath.Join(dir, "bar", "package.json"), "{}\n")
+
+	clones := []pkg.Clone{{Project: "foo", Path: filepath.Join(dir, "foo")}, {Project: "bar", Path: filepath.Join(dir, "bar")}}
+	predicates, _ := pkg.ParseClonePredicates([]string{"file-exists:go.mod"})
+
+	results := pkg.EditClones(clones, predicates, &pkg.LineEditor{File: "go.mod", Line: "go 1.16"}, false)
+
+	if results[0].Err != nil || !reflect.DeepEqual([]string{"go.mod"}, results[0].Changed) {
+		t.Errorf("Invalid result for 'foo'. Received %+v", results[0])
+	}
+
+	if results[1].Skipped == false || results[1].Changed != nil {
+		t.Errorf("Project 'bar' should have been skipped. Received %+v", results[1])
+	}
+}
@@ -0,0 +1,188 @@
+package unit_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"wildfire/pkg"
+	"wildfire/pkg/group_dashboard"
+	"wildfire/pkg/project_repository"
+)
+
+func TestGroupDashboard(t *testing.T) {
+	config := &pkg.WildFireConfig{
+		Projects: map[string]*pkg.ProjectConfig{
+			"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo"},
+			"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/bar"},
+		},
+		Groups: map[string]*pkg.GroupConfig{},
+	}
+	ps := pkg.NewProjectService(config)
+	gs := pkg.NewGroupService(config)
+
+	failClone := map[string]bool{}
+	cloner := &ClonerMock{
+		StubCloneProject: func(path string, project *pkg.ProjectConfig) error {
+			if failClone[project.Name] {
+				return errors.New("clone failed")
+			}
+
+			return os.MkdirAll(path, 0755)
+		},
+	}
+
+	newDashboard := func(t *testing.T) *group_dashboard.Dashboard {
+		repoService := project_repository.NewProjectRepositoryService(&ps, &gs, cloner)
+
+		return group_dashboard.NewDashboard("group", t.TempDir(), []string{"foo", "bar"}, ps, repoService)
+	}
+
+	t.Run("NewDashboard", func(t *testing.T) {
+		t.Run("should detect the projects which have already been cloned", func(t *testing.T) {
+			path := t.TempDir()
+			_ = os.Mkdir(filepath.Join(path, "bar"), 0755)
+			repoService := project_repository.NewProjectRepositoryService(&ps, &gs, cloner)
+
+			d := group_dashboard.NewDashboard("group", path, []string{"foo", "bar"}, ps, repoService)
+
+			if d.Projects[0].Clone != group_dashboard.CloneStatusMissing || d.Projects[1].Clone != group_dashboard.CloneStatusCloned {
+				t.Errorf("Invalid clone status. Received '%s' and '%s'", d.Projects[0].Clone, d.Projects[1].Clone)
+			}
+		})
+	})
+
+	t.Run("Targets", func(t *testing.T) {
+		t.Run("should return all projects if none are selected", func(t *testing.T) {
+			d := newDashboard(t)
+
+			if len(d.Targets()) != 2 {
+				t.Errorf("Invalid number of targets. Expected '%d' received '%d'", 2, len(d.Targets()))
+			}
+		})
+
+		t.Run("should return the selected projects", func(t *testing.T) {
+			d := newDashboard(t)
+			d.Toggle(1)
+
+			if targets := d.Targets(); len(targets) != 1 || targets[0].Name != "bar" {
+				t.Errorf("Invalid targets returned. Received %+v", targets)
+			}
+		})
+
+		t.Run("SelectAll should clear the selection if every project is selected", func(t *testing.T) {
+			d := newDashboard(t)
+			d.SelectAll()
+			d.SelectAll()
+
+			for _, project := range d.Projects {
+				if project.Selected {
+					t.Errorf("Project '%s' should not have been selected", project.Name)
+				}
+			}
+		})
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		t.Run("should clone the projects and report failures", func(t *testing.T) {
+			failClone["bar"] = true
+			defer delete(failClone, "bar")
+
+			d := newDashboard(t)
+			var changes int32
+			d.OnChange = func(project *group_dashboard.ProjectState) {
+				atomic.AddInt32(&changes, 1)
+			}
+
+			d.Clone(d.Projects)
+
+			if d.Projects[0].Clone != group_dashboard.CloneStatusCloned {
+				t.Errorf("Invalid clone status. Expected '%s' received '%s'", group_dashboard.CloneStatusCloned, d.Projects[0].Clone)
+			}
+
+			if d.Projects[1].Clone != group_dashboard.CloneStatusFailed || d.Projects[1].Err == nil {
+				t.Errorf("Invalid clone status. Expected '%s' received '%s'", group_dashboard.CloneStatusFailed, d.Projects[1].Clone)
+			}
+
+			if atomic.LoadInt32(&changes) == 0 {
+				t.Error("OnChange should have been called")
+			}
+
+			if expected := []string{"foo"}; !reflect.DeepEqual(expected, d.GetClonedProjects()) {
+				t.Errorf("Invalid cloned projects. Expected %+v received %+v", expected, d.GetClonedProjects())
+			}
+		})
+
+		t.Run("RetryFailed should clone failed projects again", func(t *testing.T) {
+			failClone["bar"] = true
+			d := newDashboard(t)
+			d.Clone(d.Projects)
+			delete(failClone, "bar")
+
+			d.RetryFailed()
+
+			if d.Projects[1].Clone != group_dashboard.CloneStatusCloned {
+				t.Errorf("Invalid clone status. Expected '%s' received '%s'", group_dashboard.CloneStatusCloned, d.Projects[1].Clone)
+			}
+		})
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		t.Run("should run the command in the cloned projects and keep their output", func(t *testing.T) {
+			d := newDashboard(t)
+			d.Clone(d.Projects[:1])
+
+			if err := d.Run(`echo "hello world"`, d.Projects); err != nil {
+				t.Fatalf("Run should not have returned an error. Error: %s", err)
+			}
+
+			if d.Projects[0].Run != group_dashboard.RunStatusSucceeded || strings.TrimSpace(d.Projects[0].Output()) != "hello world" {
+				t.Errorf("Invalid run result. Status '%s' output '%s'", d.Projects[0].Run, d.Projects[0].Output())
+			}
+
+			if d.Projects[1].Run != group_dashboard.RunStatusIdle {
+				t.Errorf("Command should not have run in a project which is not cloned. Status '%s'", d.Projects[1].Run)
+			}
+		})
+
+		t.Run("should mark projects in which the command failed", func(t *testing.T) {
+			d := newDashboard(t)
+			d.Clone(d.Projects)
+
+			_ = d.Run("false", d.Projects)
+
+			for _, project := range d.Projects {
+				if project.Run != group_dashboard.RunStatusFailed {
+					t.Errorf("Invalid run status for '%s'. Expected '%s' received '%s'", project.Name, group_dashboard.RunStatusFailed, project.Run)
+				}
+			}
+		})
+
+		t.Run("should record the clones and the runs in the history", func(t *testing.T) {
+			var entries []*pkg.HistoryEntry
+			d := newDashboard(t)
+			d.Record = func(entry *pkg.HistoryEntry) {
+				entries = append(entries, entry)
+			}
+
+			d.Clone(d.Projects[:1])
+			_ = d.Run("false", d.Projects)
+
+			if len(entries) != 2 || entries[0].Kind != "clone" || entries[1].Kind != "exec" || entries[1].Command != "false" {
+				t.Fatalf("Invalid entries. Received %+v", entries)
+			}
+			if projects := entries[1].GetProjects(pkg.HistoryStatusFailed); !reflect.DeepEqual(projects, []string{"foo"}) {
+				t.Errorf("Only the cloned project should have been recorded as failed. Received %v", projects)
+			}
+		})
+
+		t.Run("should return an error if no command is provided", func(t *testing.T) {
+			if err := newDashboard(t).Run(" ", nil); err == nil {
+				t.Error("Run should have returned an error instead it resolved")
+			}
+		})
+	})
+}
@@ -34,8 +34,8 @@ func TestProjectGroup(t *testing.T) {
 
 			group, _ := groupService.CreateGroup("foo")
 
-			if len(*group) != 0 {
-				t.Errorf("Group should have been returned empty. Group has length of %d", len(*group))
+			if len(group.Projects) != 0 {
+				t.Errorf("Group should have been returned empty. Group has length of %d", len(group.Projects))
 			}
 		})
 
@@ -61,7 +61,7 @@ func TestProjectGroup(t *testing.T) {
 
 	t.Run("GetGroup", func(t *testing.T) {
 		t.Run("should return the group if it exists", func(t *testing.T) {
-			group := &pkg.GroupConfig{"foo", "bar", "zaz"}
+			group := &pkg.GroupConfig{Projects: []string{"foo", "bar", "zaz"}}
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{},
 				Groups: map[string]*pkg.GroupConfig{"foo": group},
@@ -99,7 +99,7 @@ func TestProjectGroup(t *testing.T) {
 					Expected: []string{"foo", "bar", "zaz"},
 				},
 				{
-					Groups: map[string]*pkg.GroupConfig{"foo": {"A", "B", "C"}, "bar": {"D"}},
+					Groups: map[string]*pkg.GroupConfig{"foo": {Projects: []string{"A", "B", "C"}}, "bar": {Projects: []string{"D"}}},
 					Expected: []string{"foo", "bar"},
 				},
 				{
@@ -121,7 +121,7 @@ func TestProjectGroup(t *testing.T) {
 	t.Run("DeleteGroup", func(t *testing.T) {
 		t.Run("should remove the group from the provided configuration", func(t *testing.T) {
 			config := &pkg.WildFireConfig{Groups: map[string]*pkg.GroupConfig{
-				"foo": {"bar", "zaz"},
+				"foo": {Projects: []string{"bar", "zaz"}},
 			}}
 			groupService := pkg.NewGroupService(config)
 
@@ -131,13 +131,27 @@ func TestProjectGroup(t *testing.T) {
 				t.Error("Group was not removed from configuration")
 			}
 		})
+
+		t.Run("should remove the group from the groups which include it", func(t *testing.T) {
+			config := &pkg.WildFireConfig{Groups: map[string]*pkg.GroupConfig{
+				"foo": {},
+				"bar": {Groups: []string{"foo", "zaz"}},
+			}}
+			groupService := pkg.NewGroupService(config)
+
+			groupService.DeleteGroup("foo")
+
+			if expected := []string{"zaz"}; !reflect.DeepEqual(expected, config.Groups["bar"].Groups) {
+				t.Errorf("Invalid included groups. Expected %+v received %+v", expected, config.Groups["bar"].Groups)
+			}
+		})
 	})
 
 	t.Run("AddProject", func(t *testing.T) {
 		t.Run("should add the name of project to its collection", func(t *testing.T) {
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"foo", pkg.ProjectTypeGit, "https://github.com/foo"},
+					"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "https://github.com/foo"},
 				},
 				Groups: map[string]*pkg.GroupConfig{},
 			}
@@ -149,7 +163,7 @@ func TestProjectGroup(t *testing.T) {
 				t.Error("Failed to add project to group")
 			}
 
-			if len(*group) == 0 {
+			if len(group.Projects) == 0 {
 				t.Error("ProjectConfig was not added to GroupConfig collection")
 			}
 		})
@@ -170,14 +184,19 @@ func TestProjectGroup(t *testing.T) {
 
 		t.Run("should not add projects which are already in the group", func(t *testing.T) {
 			config := &pkg.WildFireConfig{
-				Projects: map[string]*pkg.ProjectConfig{"foo": {"", "", ""}},
+				Projects: map[string]*pkg.ProjectConfig{"foo": {Name: "", Type: "", URL: ""}},
 				Groups: map[string]*pkg.GroupConfig{},
 			}
 			groupService := pkg.NewGroupService(config)
 			group, _ := groupService.CreateGroup("foo")
 			updatedGroup, _ := groupService.AddProject(group, "foo")
 
-			if len(*updatedGroup) > 2 {
+			if len(updatedGroup.Projects) > 2 {
+				t.Error("ProjectConfig should not have been added to group twice.")
+			}
+
+			updatedGroup, _ = groupService.AddProject(updatedGroup, "foo")
+			if len(updatedGroup.Projects) != 1 {
 				t.Error("ProjectConfig should not have been added to group twice.")
 			}
 		})
@@ -187,9 +206,9 @@ func TestProjectGroup(t *testing.T) {
 		t.Run("should remove the project from the group", func(t *testing.T) {
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"", "", ""},
-					"bar": {"", "", ""},
-					"zaz": {"", "", ""},
+					"foo": {Name: "", Type: "", URL: ""},
+					"bar": {Name: "", Type: "", URL: ""},
+					"zaz": {Name: "", Type: "", URL: ""},
 				},
 				Groups: map[string]*pkg.GroupConfig{},
 			}
@@ -201,11 +220,11 @@ func TestProjectGroup(t *testing.T) {
 			result := groupService.RemoveProject(group, "bar")
 
 
-			if len(*result) != 2 {
-				t.Error(fmt.Sprintf("Group has invalid number of projects. Expected %d received %d", 2, len(*result)))
+			if len(result.Projects) != 2 {
+				t.Error(fmt.Sprintf("Group has invalid number of projects. Expected %d received %d", 2, len(result.Projects)))
 			}
 
-			expectedGroup := pkg.GroupConfig{"foo", "zaz"}
+			expectedGroup := pkg.GroupConfig{Projects: []string{"foo", "zaz"}}
 			if !reflect.DeepEqual(*result, expectedGroup) {
 				t.Errorf(
 					"Expected group does not match result. Expected %+v received %+v",
@@ -218,8 +237,8 @@ func TestProjectGroup(t *testing.T) {
 		t.Run("should return the same project group if project does not exist", func(t *testing.T) {
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"", "", ""},
-					"bar": {"", "", ""},
+					"foo": {Name: "", Type: "", URL: ""},
+					"bar": {Name: "", Type: "", URL: ""},
 				},
 				Groups: map[string]*pkg.GroupConfig{},
 			}
@@ -229,7 +248,7 @@ func TestProjectGroup(t *testing.T) {
 			group, _ = groupService.AddProject(group, "bar")
 			result := groupService.RemoveProject(group, "zaz")
 
-			expected := pkg.GroupConfig{"foo", "bar"}
+			expected := pkg.GroupConfig{Projects: []string{"foo", "bar"}}
 			if !reflect.DeepEqual(expected, *result) {
 				t.Errorf(
 					"Expected group does not match result. Expected %+v received %+v",
@@ -244,8 +263,8 @@ func TestProjectGroup(t *testing.T) {
 		t.Run("should return true if project exists in group", func(t *testing.T) {
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"", "", ""},
-					"bar": {"", "", ""},
+					"foo": {Name: "", Type: "", URL: ""},
+					"bar": {Name: "", Type: "", URL: ""},
 				},
 				Groups: map[string]*pkg.GroupConfig{},
 			}
@@ -263,8 +282,8 @@ func TestProjectGroup(t *testing.T) {
 		t.Run("should return false if project does not exist in group", func(t *testing.T) {
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"", "", ""},
-					"bar": {"", "", ""},
+					"foo": {Name: "", Type: "", URL: ""},
+					"bar": {Name: "", Type: "", URL: ""},
 				},
 				Groups: map[string]*pkg.GroupConfig{},
 			}
@@ -279,4 +298,204 @@ func TestProjectGroup(t *testing.T) {
 			}
 		})
 	})
+	t.Run("GetProjectGroups", func(t *testing.T) {
+		t.Run("should return the sorted names of the groups containing the project", func(t *testing.T) {
+			config := &pkg.WildFireConfig{
+				Groups: map[string]*pkg.GroupConfig{
+					"zaz": {Projects: []string{"foo", "bar"}},
+					"bar": {Projects: []string{"foo"}},
+					"foo": {Projects: []string{"bar"}},
+				},
+			}
+			groupService := pkg.NewGroupService(config)
+
+			result := groupService.GetProjectGroups("foo")
+
+			expected := []string{"bar", "zaz"}
+			if !reflect.DeepEqual(expected, result) {
+				t.Errorf("Invalid groups returned. Expected %+v received %+v", expected, result)
+			}
+		})
+	})
+	t.Run("GetMissingProjects", func(t *testing.T) {
+		t.Run("should return the group members which do not exist in configuration", func(t *testing.T) {
+			group := &pkg.GroupConfig{Projects: []string{"foo", "bar", "zaz"}}
+			config := &pkg.WildFireConfig{
+				Projects: map[string]*pkg.ProjectConfig{
+					"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/bar"},
+				},
+				Groups: map[string]*pkg.GroupConfig{"foo": group},
+			}
+			groupService := pkg.NewGroupService(config)
+
+			result := groupService.GetMissingProjects(group)
+
+			expected := []string{"foo", "zaz"}
+			if !reflect.DeepEqual(expected, result) {
+				t.Errorf("Invalid missing projects returned. Expected %+v received %+v", expected, result)
+			}
+		})
+
+		t.Run("should return nil if all group members exist", func(t *testing.T) {
+			group := &pkg.GroupConfig{Projects: []string{"bar"}}
+			config := &pkg.WildFireConfig{
+				Projects: map[string]*pkg.ProjectConfig{
+					"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/bar"},
+				},
+				Groups: map[string]*pkg.GroupConfig{"foo": group},
+			}
+			groupService := pkg.NewGroupService(config)
+
+			if result := groupService.GetMissingProjects(group); result != nil {
+				t.Errorf("Expected no missing projects. Received %+v", result)
+			}
+		})
+	})
+
+	t.Run("RenameGroup", func(t *testing.T) {
+		newConfig := func() *pkg.WildFireConfig {
+			return &pkg.WildFireConfig{
+				Groups: map[string]*pkg.GroupConfig{"foo": {Projects: []string{"bar"}}, "zaz": {}},
+				Workspaces: map[string]*pkg.WorkspaceConfig{
+					"foo": {Path: "/tmp/foo", Group: "foo", Projects: []string{"bar"}},
+				},
+			}
+		}
+
+		t.Run("should rename the group and its workspace", func(t *testing.T) {
+			config := newConfig()
+
+			if err := pkg.NewGroupService(config).RenameGroup("foo", "baz"); err != nil {
+				t.Fatalf("RenameGroup should not have returned an error. Error: %s", err)
+			}
+
+			if _, ok := config.Groups["foo"]; ok || config.Groups["baz"] == nil {
+				t.Errorf("Group was not renamed. Groups %+v", config.Groups)
+			}
+
+			workspace := config.Workspaces["baz"]
+			if _, ok := config.Workspaces["foo"]; ok || workspace == nil || workspace.Group != "baz" {
+				t.Errorf("Workspace was not renamed. Workspaces %+v", config.Workspaces)
+			}
+		})
+
+		t.Run("should return an error if the new name is taken", func(t *testing.T) {
+			config := newConfig()
+
+			err := pkg.NewGroupService(config).RenameGroup("foo", "zaz")
+			if err == nil {
+				t.Fatal("RenameGroup should have returned an error instead it resolved")
+			}
+
+			expectedErrorMessage := "group with name 'zaz' already exists"
+			if err.Error() != expectedErrorMessage {
+				t.Errorf("Unexpected error has been returned. Expected '%s' received '%s'", expectedErrorMessage, err)
+			}
+		})
+	})
+
+	t.Run("IncludeGroup", func(t *testing.T) {
+		newConfig := func() *pkg.WildFireConfig {
+			return &pkg.WildFireConfig{
+				Groups: map[string]*pkg.GroupConfig{
+					"backend":     {Groups: []string{"node_apps"}},
+					"node_apps":   {Projects: []string{"foo"}},
+					"go_services": {Projects: []string{"bar"}},
+				},
+			}
+		}
+
+		t.Run("should add the group to the included groups once", func(t *testing.T) {
+			config := newConfig()
+			groupService := pkg.NewGroupService(config)
+
+			group, _ := groupService.IncludeGroup(config.Groups["backend"], "go_services")
+			group, _ = groupService.IncludeGroup(group, "go_services")
+
+			expected := []string{"node_apps", "go_services"}
+			if !reflect.DeepEqual(expected, group.Groups) {
+				t.Errorf("Invalid included groups. Expected %+v received %+v", expected, group.Groups)
+			}
+		})
+
+		t.Run("should return an error if the group does not exist", func(t *testing.T) {
+			config := newConfig()
+
+			_, err := pkg.NewGroupService(config).IncludeGroup(config.Groups["backend"], "missing")
+			if err == nil {
+				t.Error("IncludeGroup should have returned an error instead it resolved")
+			}
+		})
+
+		t.Run("should return an error and not include the group if it would create a cycle", func(t *testing.T) {
+			config := newConfig()
+
+			_, err := pkg.NewGroupService(config).IncludeGroup(config.Groups["node_apps"], "backend")
+			if err == nil {
+				t.Fatal("IncludeGroup should have returned an error instead it resolved")
+			}
+
+			expectedErrorMessage := "group 'node_apps' includes itself: node_apps -> backend -> node_apps"
+			if err.Error() != expectedErrorMessage {
+				t.Errorf("Unexpected error has been returned. Expected '%s' received '%s'", expectedErrorMessage, err)
+			}
+
+			if len(config.Groups["node_apps"].Groups) != 0 {
+				t.Errorf("Group should not have been included. Received %+v", config.Groups["node_apps"].Groups)
+			}
+		})
+	})
+
+	t.Run("GetGroupProjects", func(t *testing.T) {
+		t.Run("should return the de-duplicated projects of the group and its included groups", func(t *testing.T) {
+			config := &pkg.WildFireConfig{
+				Groups: map[string]*pkg.GroupConfig{
+					"backend":     {Projects: []string{"zaz"}, Groups: []string{"node_apps", "go_services"}},
+					"node_apps":   {Projects: []string{"foo", "bar"}},
+					"go_services": {Projects: []string{"bar", "baz"}, Groups: []string{"node_apps"}},
+				},
+			}
+
+			result, err := pkg.NewGroupService(config).GetGroupProjects("backend")
+			if err != nil {
+				t.Fatalf("GetGroupProjects should not have returned an error. Error: %s", err)
+			}
+
+			expected := []string{"zaz", "foo", "bar", "baz"}
+			if !reflect.DeepEqual(expected, result) {
+				t.Errorf("Invalid projects returned. Expected %+v received %+v", expected, result)
+			}
+		})
+
+		t.Run("should return an error if the groups include each other", func(t *testing.T) {
+			config := &pkg.WildFireConfig{
+				Groups: map[string]*pkg.GroupConfig{
+					"foo": {Groups: []string{"bar"}},
+					"bar": {Groups: []string{"zaz"}},
+					"zaz": {Groups: []string{"bar"}},
+				},
+			}
+
+			_, err := pkg.NewGroupService(config).GetGroupProjects("foo")
+			if err == nil {
+				t.Fatal("GetGroupProjects should have returned an error instead it resolved")
+			}
+
+			expectedErrorMessage := "group 'bar' includes itself: bar -> zaz -> bar"
+			if err.Error() != expectedErrorMessage {
+				t.Errorf("Unexpected error has been returned. Expected '%s' received '%s'", expectedErrorMessage, err)
+			}
+		})
+
+		t.Run("should return an error if an included group does not exist", func(t *testing.T) {
+			config := &pkg.WildFireConfig{
+				Groups: map[string]*pkg.GroupConfig{"foo": {Groups: []string{"bar"}}},
+			}
+
+			if _, err := pkg.NewGroupService(config).GetGroupProjects("foo"); err == nil {
+				t.Error("GetGroupProjects should have returned an error instead it resolved")
+			}
+		})
+	})
 }
+
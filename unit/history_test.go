@@ -0,0 +1,208 @@
+package unit_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/spf13/cobra"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"wildfire/cmd/exec"
+	"wildfire/cmd/history"
+	"wildfire/cmd/plugin"
+	"wildfire/pkg"
+)
+
+func TestHistoryStore(t *testing.T) {
+	t.Run("should list the appended entries in order", func(t *testing.T) {
+		store := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "wildfire", "history.jsonl"))
+
+		for _, target := range []string{"backend", "frontend"} {
+			entry := pkg.NewHistoryEntry("exec", target)
+			entry.AddProject(pkg.Clone{Project: "foo"}, pkg.HistoryStatusSucceeded, "", nil)
+			if err := store.Append(entry); err != nil {
+				t.Fatalf("Failed to append entry. Error: %s", err)
+			}
+		}
+
+		entries, err := store.List()
+		if err != nil {
+			t.Fatalf("Failed to list entries. Error: %s", err)
+		}
+
+		if len(entries) != 2 || entries[0].Target != "backend" || entries[1].Target != "frontend" {
+			t.Errorf("Invalid entries. Received %+v", entries)
+		}
+		if entries[0].User == "" || entries[0].Time.IsZero() || len(entries[0].Projects) != 1 {
+			t.Errorf("Invalid entry. Received %+v", entries[0])
+		}
+	})
+
+	t.Run("should list nothing when the history does not exist", func(t *testing.T) {
+		store := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+
+		if entries, err := store.List(); err != nil || len(entries) != 0 {
+			t.Errorf("Expected no entries. Received %+v, %v", entries, err)
+		}
+	})
+
+	t.Run("should get an entry by the start of its id", func(t *testing.T) {
+		store := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+		entry := pkg.NewHistoryEntry("edit", "backend")
+		_ = store.Append(entry)
+
+		found, err := store.Get(entry.ID[:4])
+		if err != nil || found.ID != entry.ID {
+			t.Errorf("Expected entry '%s'. Received %+v, %v", entry.ID, found, err)
+		}
+
+		if _, err := store.Get("unknown"); err == nil {
+			t.Error("An error should have been returned for an unknown id")
+		}
+	})
+
+	t.Run("should record the exit code and commit of the projects", func(t *testing.T) {
+		dir := t.TempDir()
+		initTestRepository(t, dir, "foo")
+
+		entry := pkg.NewHistoryEntry("exec", "backend")
+		entry.AddProject(pkg.Clone{Project: "foo", Path: dir}, pkg.HistoryStatusSucceeded, "", osexec.Command("sh", "-c", "exit 3").Run())
+
+		project := entry.Projects[0]
+		if project.Status != pkg.HistoryStatusFailed || project.ExitCode != 3 || len(project.Commit) != 40 {
+			t.Errorf("Invalid project. Received %+v", project)
+		}
+	})
+}
+
+func TestProjectSelection(t *testing.T) {
+	ctx := pkg.WithProjectSelection(context.Background(), []string{"bar", "zaz"})
+
+	if projects := pkg.SelectProjects(ctx, []string{"foo", "bar", "zaz"}); !reflect.DeepEqual(projects, []string{"bar", "zaz"}) {
+		t.Errorf("Invalid projects. Received %v", projects)
+	}
+
+	clones := []pkg.Clone{{Project: "foo"}, {Project: "bar"}}
+	if selected := pkg.SelectClones(context.Background(), clones); !reflect.DeepEqual(selected, clones) {
+		t.Errorf("Every clone should be selected without a selection. Received %v", selected)
+	}
+}
+
+func TestRerunHistory(t *testing.T) {
+	workspace := t.TempDir()
+	for _, name := range []string{"foo", "bar"} {
+		_ = os.MkdirAll(filepath.Join(workspace, name), 0755)
+	}
+	writeTestFile(t, filepath.Join(workspace, "foo", "config.txt"), "foo")
+
+	configStore := pkg.NewMemoryConfigStore(&pkg.WildFireConfig{
+		Projects: map[string]*pkg.ProjectConfig{
+			"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/org/foo"},
+			"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/org/bar"},
+		},
+		Workspaces: map[string]*pkg.WorkspaceConfig{
+			"backend": {Path: workspace, Projects: []string{"foo", "bar"}},
+		},
+	})
+	historyStore := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+
+	run := func(args ...string) error {
+		root := &cobra.Command{Use: "wildfire", SilenceErrors: true, SilenceUsage: true}
+		root.AddCommand(exec.NewExecCmd())
+		root.AddCommand(history.NewRerunCmd())
+		root.SetArgs(args)
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+
+		ctx := pkg.WithHistory(pkg.WithConfigStore(context.Background(), configStore), historyStore, args)
+
+		return root.ExecuteContext(ctx)
+	}
+
+	if err := run("exec", "backend", "--", "cat", "config.txt"); err == nil {
+		t.Fatal("The command should have failed in 'bar'")
+	}
+
+	entries, _ := historyStore.List()
+	if len(entries) != 1 || !reflect.DeepEqual(entries[0].GetProjects(pkg.HistoryStatusFailed), []string{"bar"}) {
+		t.Fatalf("Invalid history. Received %+v", entries)
+	}
+
+	writeTestFile(t, filepath.Join(workspace, "bar", "config.txt"), "bar")
+	if err := run("rerun", entries[0].ID, "--failed"); err != nil {
+		t.Fatalf("Failed to run again. Error: %s", err)
+	}
+
+	entries, _ = historyStore.List()
+	if len(entries) != 2 {
+		t.Fatalf("The run should have been recorded. Received %+v", entries)
+	}
+	if !reflect.DeepEqual(entries[1].GetProjects(""), []string{"bar"}) || entries[1].Projects[0].Status != pkg.HistoryStatusSucceeded {
+		t.Errorf("Only the failed project should have run. Received %+v", entries[1].Projects)
+	}
+	if !reflect.DeepEqual(entries[1].Args, entries[0].Args) {
+		t.Errorf("The run should have the arguments of the original run. Received %v", entries[1].Args)
+	}
+
+	if err := run("rerun", entries[1].ID, "--failed"); err == nil {
+		t.Error("An error should have been returned for a run without failed projects")
+	}
+}
+
+func TestRerunActionHistory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Plugin scripts require a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "other.wildfire.yaml")
+	fileStore := pkg.NewFileConfigStore(cfgFile)
+	err := fileStore.Save(&pkg.WildFireConfig{Projects: map[string]*pkg.ProjectConfig{
+		"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/org/foo"},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to save configuration. Error: %s", err)
+	}
+
+	action := &pkg.PluginAction{
+		PluginName: "hello",
+		Path:       writeTestPlugin(t, dir, "hello", "cat > /dev/null\necho '[{\"project\": \"foo\", \"status\": \"succeeded\"}]'\n"),
+	}
+	historyStore := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+
+	run := func(store pkg.ConfigStore, args ...string) error {
+		root := &cobra.Command{Use: "wildfire", SilenceErrors: true, SilenceUsage: true}
+		root.PersistentFlags().String("config", "", "")
+		root.AddCommand(plugin.NewActionCmd(action))
+		root.AddCommand(history.NewRerunCmd())
+		root.SetArgs(args)
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+
+		ctx := pkg.WithHistory(pkg.WithConfigStore(context.Background(), store), historyStore, args)
+
+		return root.ExecuteContext(ctx)
+	}
+
+	if err := run(fileStore, "hello", "foo"); err != nil {
+		t.Fatalf("The action should have succeeded. Error: %s", err)
+	}
+
+	entries, _ := historyStore.List()
+	if len(entries) != 1 || entries[0].ConfigFile != cfgFile {
+		t.Fatalf("Invalid history. Received %+v", entries)
+	}
+
+	// The run is repeated with the configuration file it was recorded with, not the one of the rerun command.
+	if err := run(pkg.NewMemoryConfigStore(nil), "rerun", entries[0].ID); err != nil {
+		t.Fatalf("Failed to run again. Error: %s", err)
+	}
+
+	entries, _ = historyStore.List()
+	if len(entries) != 2 || !reflect.DeepEqual(entries[1].Args, entries[0].Args) || entries[1].ConfigFile != cfgFile {
+		t.Errorf("The run should have been recorded with the arguments of the original run. Received %+v", entries)
+	}
+}
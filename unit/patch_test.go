@@ -0,0 +1,171 @@
+package unit_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"wildfire/pkg"
+)
+
+func gitCommand(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(
+		os.Environ(),
+		"GIT_AUTHOR_NAME=wildfire",
+		"GIT_AUTHOR_EMAIL=wildfire@example.com",
+		"GIT_COMMITTER_NAME=wildfire",
+		"GIT_COMMITTER_EMAIL=wildfire@example.com",
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed. Error: %s %s", strings.Join(args, " "), err, output)
+	}
+}
+
+func initTestRepository(t *testing.T, dir string, content string) {
+	writeTestFile(t, filepath.Join(dir, "config.txt"), content)
+	gitCommand(t, dir, "init", "-q")
+	gitCommand(t, dir, "add", "-A")
+	gitCommand(t, dir, "commit", "-q", "-m", "init")
+}
+
+func TestPatch(t *testing.T) {
+	for _, variable := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+		previous, set := os.LookupEnv(variable)
+		_ = os.Setenv(variable, "wildfire")
+		defer func(variable string) {
+			if set {
+				_ = os.Setenv(variable, previous)
+			} else {
+				_ = os.Unsetenv(variable)
+			}
+		}(variable)
+	}
+
+	dir := t.TempDir()
+	template := filepath.Join(dir, "template")
+	initTestRepository(t, template, "name: foo\nversion: 1\nteam: api\nowner: bar\nlicense: MIT\n")
+	gitCommand(t, template, "tag", "base")
+
+	// The clones share the history of the template, so the blobs needed by a 3-way merge are available.
+	newClones := func(t *testing.T) []pkg.Clone {
+		clonesDir := t.TempDir()
+
+		var clones []pkg.Clone
+		for _, project := range []string{"foo", "bar", "zaz"} {
+			gitCommand(t, clonesDir, "clone", "-q", template, project)
+			gitCommand(t, filepath.Join(clonesDir, project), "reset", "-q", "--hard", "base")
+			clones = append(clones, pkg.Clone{Project: project, Path: filepath.Join(clonesDir, project)})
+		}
+
+		writeTestFile(t, filepath.Join(clones[1].Path, "config.txt"), "name: foo\nversion: 1\nteam: api\nowner: zaz\nlicense: MIT\n")
+		gitCommand(t, clones[1].Path, "commit", "-q", "-a", "-m", "Change owner")
+		writeTestFile(t, filepath.Join(clones[2].Path, "config.txt"), "name: foo\nversion: 3\nteam: api\nowner: bar\nlicense: MIT\n")
+		gitCommand(t, clones[2].Path, "commit", "-q", "-a", "-m", "Release version 3")
+
+		return clones
+	}
+
+	writeTestFile(t, filepath.Join(template, "config.txt"), "name: foo\nversion: 2\nteam: api\nowner: bar\nlicense: MIT\n")
+	writeTestFile(t, filepath.Join(template, "CHANGELOG.md"), "version 2\n")
+
+	patchFile := filepath.Join(dir, "update.patch")
+
+	t.Run("CapturePatch should include the untracked files", func(t *testing.T) {
+		patch, err := pkg.CapturePatch(template, "")
+		if err != nil {
+			t.Fatalf("CapturePatch should not have returned an error. Error: %s", err)
+		}
+
+		for _, expected := range []string{"+version: 2", "+++ b/CHANGELOG.md"} {
+			if strings.Contains(patch, expected) == false {
+				t.Errorf("Patch should contain '%s'. Received '%s'", expected, patch)
+			}
+		}
+
+		if err := ioutil.WriteFile(patchFile, []byte(patch), 0644); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("should only check whether the patch applies", func(t *testing.T) {
+		clones := newClones(t)
+
+		results, err := pkg.ApplyPatchToClones(clones, nil, patchFile, true)
+		if err != nil {
+			t.Fatalf("ApplyPatchToClones should not have returned an error. Error: %s", err)
+		}
+
+		expected := []pkg.PatchStatus{pkg.PatchStatusApplicable, pkg.PatchStatusApplicable, pkg.PatchStatusConflict}
+		for i, result := range results {
+			if result.Status != expected[i] {
+				t.Errorf("Invalid status for '%s'. Expected '%s' received '%s'", result.Project, expected[i], result.Status)
+			}
+		}
+
+		if _, err := os.Stat(filepath.Join(clones[0].Path, "CHANGELOG.md")); err == nil {
+			t.Error("Checking the patch should not have changed the clone")
+		}
+	})
+
+	t.Run("should apply the patch with a 3-way fallback and report conflicts", func(t *testing.T) {
+		clones := newClones(t)
+
+		results, _ := pkg.ApplyPatchToClones(clones, nil, patchFile, false)
+
+		if results[0].Status != pkg.PatchStatusApplied || results[0].ThreeWay || results[0].Err != nil {
+			t.Errorf("Invalid result for 'foo'. Received %+v", results[0])
+		}
+
+		if results[1].Status != pkg.PatchStatusApplied || results[1].ThreeWay == false || results[1].Err != nil {
+			t.Errorf("Invalid result for 'bar'. Received %+v", results[1])
+		}
+
+		if results[2].Status != pkg.PatchStatusConflict || results[2].ThreeWay == false || results[2].Err == nil {
+			t.Errorf("Invalid result for 'zaz'. Received %+v", results[2])
+		}
+
+		if content := readTestFile(t, filepath.Join(clones[1].Path, "config.txt")); content != "name: foo\nversion: 2\nteam: api\nowner: zaz\nlicense: MIT\n" {
+			t.Errorf("Invalid patched file. Received '%s'", content)
+		}
+
+		results, _ = pkg.ApplyPatchToClones(clones[:1], nil, patchFile, false)
+		if results[0].Status != pkg.PatchStatusAlreadyApplied {
+			t.Errorf("Invalid status. Expected '%s' received '%s'", pkg.PatchStatusAlreadyApplied, results[0].Status)
+		}
+	})
+
+	t.Run("should commit patch series", func(t *testing.T) {
+		gitCommand(t, template, "add", "-A")
+		gitCommand(t, template, "commit", "-q", "-m", "Release version 2")
+
+		series, err := pkg.CapturePatch(template, "base")
+		if err != nil {
+			t.Fatalf("CapturePatch should not have returned an error. Error: %s", err)
+		}
+
+		if pkg.IsPatchSeries([]byte(series)) == false {
+			t.Fatalf("Patch should have been a series. Received '%s'", series)
+		}
+
+		seriesFile := filepath.Join(dir, "release.patch")
+		if err := ioutil.WriteFile(seriesFile, []byte(series), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		clones := newClones(t)
+		results, _ := pkg.ApplyPatchToClones(clones[:1], nil, seriesFile, false)
+		if results[0].Status != pkg.PatchStatusApplied {
+			t.Errorf("Invalid status. Expected '%s' received '%s'", pkg.PatchStatusApplied, results[0].Status)
+		}
+
+		output, _ := exec.Command("git", "-C", clones[0].Path, "log", "-1", "--format=%s").Output()
+		if strings.TrimSpace(string(output)) != "Release version 2" {
+			t.Errorf("Patch should have been committed. Received '%s'", output)
+		}
+	})
+}
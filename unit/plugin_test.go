@@ -0,0 +1,209 @@
+package unit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"wildfire/pkg"
+)
+
+func writeTestPlugin(t *testing.T, dir string, name string, script string) string {
+	path := filepath.Join(dir, pkg.PluginPrefix+name)
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("Failed to write plugin '%s'. Error: %s", path, err)
+	}
+
+	return path
+}
+
+func newActionTestConfig() *pkg.WildFireConfig {
+	return &pkg.WildFireConfig{
+		Projects: map[string]*pkg.ProjectConfig{
+			"foo": {URL: "git@github.com:org/foo.git", Labels: []string{"go"}},
+			"bar": {URL: "git@github.com:org/bar.git"},
+			"zaz": {URL: "git@github.com:org/zaz.git"},
+		},
+		Groups: map[string]*pkg.GroupConfig{
+			"backend":  {Projects: []string{"foo", "bar"}},
+			"frontend": {Projects: []string{"zaz"}},
+		},
+		Workspaces: map[string]*pkg.WorkspaceConfig{
+			"api": {Path: "/tmp/api", Group: "backend", Projects: []string{"foo", "bar"}},
+		},
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Plugin scripts require a POSIX shell")
+	}
+
+	t.Run("DiscoverPlugins", func(t *testing.T) {
+		t.Run("should return the executables with the plugin prefix", func(t *testing.T) {
+			first, second := t.TempDir(), t.TempDir()
+			path := writeTestPlugin(t, first, "hello", "exit 0\n")
+			writeTestPlugin(t, second, "hello", "exit 0\n")
+			writeTestPlugin(t, second, "world", "exit 0\n")
+			writeTestPlugin(t, second, "world.v2", "exit 0\n")
+			writeTestFile(t, filepath.Join(second, pkg.PluginPrefix+"data"), "not executable")
+			writeTestFile(t, filepath.Join(second, "other"), "not a plugin")
+
+			plugins := pkg.DiscoverPlugins(first + string(os.PathListSeparator) + second)
+
+			var names []string
+			for _, plugin := range plugins {
+				names = append(names, plugin.Name())
+			}
+
+			expected := []string{"hello", "world", "world.v2"}
+			if !reflect.DeepEqual(names, expected) {
+				t.Errorf("Invalid plugins. Expected %v received %v", expected, names)
+			}
+			if plugins[0].Path != path {
+				t.Errorf("The plugin found first on the path should be used. Expected '%s' received '%s'", path, plugins[0].Path)
+			}
+		})
+
+		t.Run("should ignore directories which do not exist", func(t *testing.T) {
+			plugins := pkg.DiscoverPlugins(filepath.Join(t.TempDir(), "missing"))
+
+			if len(plugins) != 0 {
+				t.Errorf("No plugin should have been found. Received %d", len(plugins))
+			}
+		})
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		t.Run("should pass the context and return the results of the plugin", func(t *testing.T) {
+			dir := t.TempDir()
+			input := filepath.Join(dir, "input.json")
+			writeTestPlugin(t, dir, "hello", `cat > "`+input+`"
+echo "$WILDFIRE_PLUGIN $WILDFIRE_CONFIG $*" >&2
+echo '[{"project": "foo", "status": "changed", "changed": ["go.mod"]}]'
+`)
+			plugin := pkg.DiscoverPlugins(dir)[0]
+			var stderr bytes.Buffer
+			plugin.Stderr = &stderr
+
+			context, err := pkg.NewActionContext(newActionTestConfig(), "frontend", []string{"--force"})
+			if err != nil {
+				t.Fatalf("Failed to create context. Error: %s", err)
+			}
+			context.ConfigFile = "/tmp/.wildfire.yaml"
+
+			results, err := plugin.Run(context)
+			if err != nil {
+				t.Fatalf("Plugin should not have returned an error. Error: %s", err)
+			}
+
+			expected := []pkg.ActionResult{{Project: "foo", Status: pkg.ActionStatusChanged, Changed: []string{"go.mod"}}}
+			if !reflect.DeepEqual(results, expected) {
+				t.Errorf("Invalid results. Expected %+v received %+v", expected, results)
+			}
+
+			if stderr.String() != "hello /tmp/.wildfire.yaml --force\n" {
+				t.Errorf("Invalid plugin environment. Received '%s'", stderr.String())
+			}
+
+			var received pkg.ActionContext
+			if err := json.Unmarshal([]byte(readTestFile(t, input)), &received); err != nil {
+				t.Fatalf("Plugin received invalid context. Error: %s", err)
+			}
+			if !reflect.DeepEqual(received, context) {
+				t.Errorf("Invalid context. Expected %+v received %+v", context, received)
+			}
+		})
+
+		t.Run("should return an error when the plugin fails", func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestPlugin(t, dir, "broken", "echo '[{\"project\": \"zaz\", \"status\": \"failed\"}]'\nexit 3\n")
+			plugin := pkg.DiscoverPlugins(dir)[0]
+
+			results, err := plugin.Run(pkg.ActionContext{Target: "zaz"})
+			if err == nil {
+				t.Error("Plugin should have returned an error")
+			}
+			if len(results) != 1 || results[0].Status != pkg.ActionStatusFailed {
+				t.Errorf("The results written before failing should be returned. Received %+v", results)
+			}
+		})
+
+		t.Run("should return an error when the results are invalid", func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestPlugin(t, dir, "invalid", "echo 'done'\n")
+			plugin := pkg.DiscoverPlugins(dir)[0]
+
+			if _, err := plugin.Run(pkg.ActionContext{Target: "zaz"}); err == nil {
+				t.Error("Plugin should have returned an error")
+			}
+		})
+	})
+}
+
+func TestActionContext(t *testing.T) {
+	t.Run("should resolve a workspace with the paths of the clones", func(t *testing.T) {
+		context, err := pkg.NewActionContext(newActionTestConfig(), "api", nil)
+		if err != nil {
+			t.Fatalf("Failed to create context. Error: %s", err)
+		}
+
+		if context.Workspace == nil || context.GroupName != "backend" || context.Group == nil {
+			t.Errorf("The workspace and its group should be set. Received %+v", context)
+		}
+
+		expected := []pkg.ActionProject{
+			{Name: "foo", URL: "git@github.com:org/foo.git", Labels: []string{"go"}, Path: filepath.Join("/tmp/api", "foo")},
+			{Name: "bar", URL: "git@github.com:org/bar.git", Path: filepath.Join("/tmp/api", "bar")},
+		}
+		if !reflect.DeepEqual(context.Projects, expected) {
+			t.Errorf("Invalid projects. Expected %+v received %+v", expected, context.Projects)
+		}
+	})
+
+	t.Run("should resolve a group to the workspace cloned from it", func(t *testing.T) {
+		context, err := pkg.NewActionContext(newActionTestConfig(), "backend", nil)
+		if err != nil {
+			t.Fatalf("Failed to create context. Error: %s", err)
+		}
+
+		if context.Workspace == nil {
+			t.Error("The workspace cloned from the group should be set")
+		}
+	})
+
+	t.Run("should resolve a group which has not been cloned", func(t *testing.T) {
+		context, err := pkg.NewActionContext(newActionTestConfig(), "frontend", nil)
+		if err != nil {
+			t.Fatalf("Failed to create context. Error: %s", err)
+		}
+
+		if context.Workspace != nil || context.GroupName != "frontend" {
+			t.Errorf("Only the group should be set. Received %+v", context)
+		}
+		if len(context.Projects) != 1 || context.Projects[0].Name != "zaz" || context.Projects[0].Path != "" {
+			t.Errorf("Invalid projects. Received %+v", context.Projects)
+		}
+	})
+
+	t.Run("should resolve a project", func(t *testing.T) {
+		context, err := pkg.NewActionContext(newActionTestConfig(), "foo", nil)
+		if err != nil {
+			t.Fatalf("Failed to create context. Error: %s", err)
+		}
+
+		if len(context.Projects) != 1 || context.Projects[0].Path != filepath.Join("/tmp/api", "foo") {
+			t.Errorf("Invalid projects. Received %+v", context.Projects)
+		}
+	})
+
+	t.Run("should return an error for an unknown target", func(t *testing.T) {
+		if _, err := pkg.NewActionContext(newActionTestConfig(), "unknown", nil); err == nil {
+			t.Error("An error should have been returned")
+		}
+	})
+}
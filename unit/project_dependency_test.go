@@ -0,0 +1,289 @@
+package unit_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"wildfire/cmd/group"
+	"wildfire/pkg"
+	"wildfire/wildfire"
+)
+
+func newDependencyTestConfig(dependencies map[string][]string, projects ...string) *pkg.WildFireConfig {
+	config := &pkg.WildFireConfig{Projects: map[string]*pkg.ProjectConfig{}, Groups: map[string]*pkg.GroupConfig{}}
+	for _, name := range projects {
+		config.Projects[name] = &pkg.ProjectConfig{
+			Name:      name,
+			Type:      pkg.ProjectTypeGit,
+			URL:       pkg.ProjectPath("github.com/org/" + name),
+			DependsOn: dependencies[name],
+		}
+	}
+
+	return config
+}
+
+func TestProjectDependencies(t *testing.T) {
+	t.Run("should order projects after their dependencies", func(t *testing.T) {
+		config := newDependencyTestConfig(map[string][]string{
+			"app": {"lib", "api"},
+			"api": {"lib"},
+			"lib": {"tools"},
+		}, "app", "api", "lib", "tools", "docs")
+
+		projects := []string{"app", "docs", "api", "lib"}
+		sorted, err := pkg.GetProjectDependencies(config, projects).Sort(projects)
+		if err != nil {
+			t.Fatalf("Failed to sort projects. Error: %s", err)
+		}
+
+		expected := []string{"lib", "api", "app", "docs"}
+		if reflect.DeepEqual(sorted, expected) == false {
+			t.Errorf("Invalid order. Expected %v received %v", expected, sorted)
+		}
+	})
+
+	t.Run("should keep the dependencies through projects which are not part of the projects", func(t *testing.T) {
+		config := newDependencyTestConfig(map[string][]string{
+			"app": {"api"},
+			"api": {"lib"},
+		}, "app", "api", "lib")
+
+		dependencies := pkg.GetProjectDependencies(config, []string{"app", "lib"})
+		if expected := (pkg.ProjectDependencies{"app": {"lib"}}); reflect.DeepEqual(dependencies, expected) == false {
+			t.Errorf("Invalid dependencies. Expected %v received %v", expected, dependencies)
+		}
+	})
+
+	t.Run("should detect projects depending on each other", func(t *testing.T) {
+		config := newDependencyTestConfig(map[string][]string{
+			"app": {"api"},
+			"api": {"lib"},
+			"lib": {"app"},
+		}, "app", "api", "lib")
+
+		projects := []string{"app", "api", "lib"}
+		_, err := pkg.GetProjectDependencies(config, projects).Sort(projects)
+
+		var cycleErr *pkg.DependencyCycleError
+		if errors.As(err, &cycleErr) == false {
+			t.Fatalf("A cycle error should have been returned. Received %v", err)
+		}
+		if expected := "projects depend on each other: api -> lib -> app -> api"; err.Error() != expected {
+			t.Errorf("Invalid error. Expected '%s' received '%s'", expected, err)
+		}
+
+		cycle := pkg.GetProjectDependencies(config, []string{"app", "api"}).Cycle()
+		if expected := []string{"api", "app", "api"}; reflect.DeepEqual(cycle, expected) == false {
+			t.Errorf("Projects depending on each other through other projects should be detected. Expected %v received %v", expected, cycle)
+		}
+	})
+
+	t.Run("should report invalid dependencies", func(t *testing.T) {
+		config := newDependencyTestConfig(map[string][]string{
+			"app":   {"app", "unknown", "lib"},
+			"lib":   {"tools"},
+			"tools": {"lib"},
+		}, "app", "lib", "tools")
+
+		issues := config.Validate()
+		var messages []string
+		for _, issue := range issues {
+			messages = append(messages, issue.String())
+		}
+
+		for _, expected := range []string{
+			"project 'app' depends on itself",
+			"project 'app' depends on project 'unknown' which does not exist",
+			"projects depend on each other: lib -> tools -> lib",
+		} {
+			if strings.Contains(strings.Join(messages, "\n"), expected) == false {
+				t.Errorf("Expected issue '%s'. Found %v", expected, messages)
+			}
+		}
+
+		for _, issue := range issues {
+			issue.Fix()
+		}
+		if reflect.DeepEqual(config.Projects["app"].DependsOn, []string{"lib"}) == false {
+			t.Errorf("The invalid dependencies should have been removed. Received %v", config.Projects["app"].DependsOn)
+		}
+	})
+
+	t.Run("should update dependencies when a project is renamed or removed", func(t *testing.T) {
+		config := newDependencyTestConfig(map[string][]string{"app": {"lib", "api"}}, "app", "api", "lib")
+		client := wildfire.NewClientFromConfig(config)
+
+		if err := client.RenameProject(context.Background(), "lib", "core"); err != nil {
+			t.Fatalf("Failed to rename project. Error: %s", err)
+		}
+		if _, err := client.RemoveProject(context.Background(), "api"); err != nil {
+			t.Fatalf("Failed to remove project. Error: %s", err)
+		}
+
+		project, _ := client.GetProject(context.Background(), "app")
+		if reflect.DeepEqual(project.DependsOn, []string{"core"}) == false {
+			t.Errorf("Invalid dependencies. Received %v", project.DependsOn)
+		}
+	})
+
+	t.Run("should not save projects depending on each other", func(t *testing.T) {
+		config := newDependencyTestConfig(map[string][]string{"app": {"lib"}}, "app", "lib")
+		client := wildfire.NewClientFromConfig(config)
+
+		err := client.SetProject(context.Background(), pkg.ProjectConfig{
+			Name:      "lib",
+			Type:      pkg.ProjectTypeGit,
+			URL:       "github.com/org/lib",
+			DependsOn: []string{"app"},
+		})
+
+		var cycleErr *pkg.DependencyCycleError
+		if errors.As(err, &cycleErr) == false {
+			t.Errorf("A cycle error should have been returned. Received %v", err)
+		}
+	})
+
+	t.Run("should load dependencies from the configuration file", func(t *testing.T) {
+		store := pkg.NewFileConfigStore(filepath.Join(t.TempDir(), "config.yaml"))
+		if err := store.Save(newDependencyTestConfig(map[string][]string{"app": {"lib"}}, "app", "lib")); err != nil {
+			t.Fatalf("Failed to save configuration. Error: %s", err)
+		}
+
+		loaded, err := store.Load()
+		if err != nil {
+			t.Fatalf("Failed to load configuration. Error: %s", err)
+		}
+		if reflect.DeepEqual(loaded.Projects["app"].DependsOn, []string{"lib"}) == false {
+			t.Errorf("The dependencies should have been loaded. Received %+v", loaded.Projects["app"])
+		}
+	})
+}
+
+func TestExecDependencies(t *testing.T) {
+	workspace := t.TempDir()
+	config := newDependencyTestConfig(map[string][]string{
+		"app":  {"lib"},
+		"docs": {"broken"},
+	}, "app", "lib", "docs", "broken")
+	config.Workspaces = map[string]*pkg.WorkspaceConfig{
+		"backend": {Path: workspace, Projects: []string{"app", "docs", "broken", "lib"}},
+	}
+	for _, name := range []string{"app", "lib", "docs"} {
+		_ = os.MkdirAll(filepath.Join(workspace, name), 0755)
+	}
+
+	script := `if [ -f ../lib/built ] || [ "$(basename "$PWD")" = lib ]; then sleep 0.2; touch built; else exit 1; fi`
+	results, err := wildfire.NewClientFromConfig(config).Exec(context.Background(), "backend", nil, "sh", "-c", script)
+	if err != nil {
+		t.Fatalf("Failed to run command. Error: %s", err)
+	}
+
+	for _, result := range results {
+		switch result.Project {
+		case "app", "lib":
+			if result.Err != nil {
+				t.Errorf("The command should have succeeded in '%s' after its dependencies. Error: %s", result.Project, result.Err)
+			}
+		case "docs":
+			if result.Err == nil || strings.Contains(result.Err.Error(), "dependency 'broken'") == false {
+				t.Errorf("The command should not have run in 'docs'. Received %+v", result)
+			}
+			if _, err := os.Stat(filepath.Join(workspace, "docs", "built")); err == nil {
+				t.Error("The command should not have run in 'docs'")
+			}
+		}
+	}
+
+	t.Run("should run after dependencies which did not match the predicates", func(t *testing.T) {
+		predicates, _ := pkg.ParseClonePredicates([]string{"file-exists:built"})
+		_ = os.Remove(filepath.Join(workspace, "lib", "built"))
+
+		results, err := wildfire.NewClientFromConfig(config).Exec(context.Background(), "backend", predicates, "true")
+		if err != nil {
+			t.Fatalf("Failed to run command. Error: %s", err)
+		}
+
+		for _, result := range results {
+			if result.Project == "lib" && result.Skipped == false {
+				t.Errorf("The command should not have run in 'lib'. Received %+v", result)
+			}
+			if result.Project == "app" && (result.Skipped || result.Err != nil) {
+				t.Errorf("The command should have run in 'app' after its skipped dependency. Received %+v", result)
+			}
+		}
+	})
+
+	t.Run("should fail projects whose dependencies failed in the results of the context", func(t *testing.T) {
+		ctx := wildfire.WithDependencyResults(
+			pkg.WithProjectSelection(context.Background(), []string{"app"}),
+			[]pkg.ExecResult{{Clone: pkg.Clone{Project: "lib"}, Err: errors.New("failed")}},
+		)
+
+		results, err := wildfire.NewClientFromConfig(config).Exec(ctx, "backend", nil, "touch", "ran")
+		if err != nil {
+			t.Fatalf("Failed to run command. Error: %s", err)
+		}
+
+		if len(results) != 1 || results[0].Err == nil || strings.Contains(results[0].Err.Error(), "dependency 'lib'") == false {
+			t.Errorf("The command should not have run in 'app'. Received %+v", results)
+		}
+		if _, err := os.Stat(filepath.Join(workspace, "app", "ran")); err == nil {
+			t.Error("The command should not have run in 'app'")
+		}
+	})
+
+	config.Projects["lib"].DependsOn = []string{"app"}
+	_, err = wildfire.NewClientFromConfig(config).Exec(context.Background(), "backend", nil, "true")
+
+	var cycleErr *pkg.DependencyCycleError
+	if errors.As(err, &cycleErr) == false {
+		t.Errorf("A cycle error should have been returned. Received %v", err)
+	}
+}
+
+func TestShowGroupGraph(t *testing.T) {
+	config := newDependencyTestConfig(map[string][]string{
+		"app": {"lib", "tools"},
+		"api": {"lib"},
+	}, "app", "api", "lib", "tools")
+	config.Groups["backend"] = &pkg.GroupConfig{Projects: []string{"app", "api", "lib", "gone"}}
+
+	var stdout bytes.Buffer
+	cmd := group.NewShowGroupCmd()
+	cmd.SetArgs([]string{"backend", "--graph"})
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&bytes.Buffer{})
+
+	if err := cmd.ExecuteContext(pkg.WithConfigStore(context.Background(), pkg.NewMemoryConfigStore(config))); err != nil {
+		t.Fatalf("Command should not have returned an error. Error: %s", err)
+	}
+
+	expected := `digraph "backend" {
+  "app";
+  "api";
+  "lib";
+  "gone" [style=dashed, label="gone (missing)"];
+  "tools" [style=dashed];
+  "app" -> "lib";
+  "app" -> "tools";
+  "api" -> "lib";
+}
+`
+	if stdout.String() != expected {
+		t.Errorf("Invalid graph. Expected:\n%s\nReceived:\n%s", expected, stdout.String())
+	}
+
+	cmd = group.NewShowGroupCmd()
+	cmd.SetArgs([]string{"backend", "--graph", "--tree"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.ExecuteContext(pkg.WithConfigStore(context.Background(), pkg.NewMemoryConfigStore(config))); err == nil {
+		t.Error("--graph and --tree should not be allowed together")
+	}
+}
@@ -19,7 +19,7 @@ func (c *ClonerMock) CloneProject(path string, project *pkg.ProjectConfig) error
 
 func TestProjectRepository(t *testing.T) {
 	t.Run("PullProject", func(t *testing.T) {
-		config := pkg.GetConfig()
+		config := loadConfigFile(t, getConfigFilePath("missing.wildfire.yaml"))
 		ps := pkg.NewProjectService(config)
 		gs := pkg.NewGroupService(config)
 		project := &pkg.ProjectConfig{
@@ -77,12 +77,12 @@ func TestProjectRepository(t *testing.T) {
 
 	t.Run("PullGroup", func(t *testing.T) {
 		t.Run("should return nil if no issues occurred which cloning group", func(t *testing.T) {
-			group := &pkg.GroupConfig{"foo", "bar", "zaz"}
+			group := &pkg.GroupConfig{Projects: []string{"foo", "bar", "zaz"}}
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"foo", pkg.ProjectTypeGit, "github.com/foo/bar"},
-					"bar": {"bar", pkg.ProjectTypeGit, "github.com/foo/bar"},
-					"zaz": {"zaz", pkg.ProjectTypeGit, "github.com/foo/bar"},
+					"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+					"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+					"zaz": {Name: "zaz", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
 				},
 				Groups: map[string]*pkg.GroupConfig{
 					"foo": group,
@@ -106,12 +106,12 @@ func TestProjectRepository(t *testing.T) {
 		})
 
 		t.Run("should return an error if group contains project which does not exist in configuration", func(t *testing.T) {
-			group := &pkg.GroupConfig{"foo", "bar", "zaz"}
+			group := &pkg.GroupConfig{Projects: []string{"foo", "bar", "zaz"}}
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"foo", pkg.ProjectTypeGit, "github.com/foo/bar"},
-					"bar": {"bar", pkg.ProjectTypeGit, "github.com/foo/bar"},
-					"zaz": {"zaz", pkg.ProjectTypeGit, "github.com/foo/bar"},
+					"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+					"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+					"zaz": {Name: "zaz", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
 				},
 				Groups: map[string]*pkg.GroupConfig{
 					"foo": group,
@@ -134,18 +134,18 @@ func TestProjectRepository(t *testing.T) {
 				ExpectedInString []string
 			}{
 				{
-					&pkg.GroupConfig{"foo", "bar", "missing_project"},
+					&pkg.GroupConfig{Projects: []string{"foo", "bar", "missing_project"}},
 					[]string{"project 'missing_project' does not exist in configuration"},
 				},
 				{
-					&pkg.GroupConfig{"foo", "missing_project", "missing_project_1"},
+					&pkg.GroupConfig{Projects: []string{"foo", "missing_project", "missing_project_1"}},
 					[]string{
 						"project 'missing_project' does not exist in configuration",
 						"project 'missing_project_1' does not exist in configuration",
 					},
 				},
 				{
-					&pkg.GroupConfig{"foo", "bar", "missing_project", "taz", "paz"},
+					&pkg.GroupConfig{Projects: []string{"foo", "bar", "missing_project", "taz", "paz"}},
 					[]string{
 						"project 'missing_project' does not exist in configuration",
 						"project 'taz' does not exist in configuration",
@@ -169,12 +169,12 @@ func TestProjectRepository(t *testing.T) {
 		})
 
 		t.Run("should return error if the cloner returns an error while cloning the group project", func(t *testing.T) {
-			group := &pkg.GroupConfig{"foo", "bar", "zaz"}
+			group := &pkg.GroupConfig{Projects: []string{"foo", "bar", "zaz"}}
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"foo", pkg.ProjectTypeGit, "github.com/foo/bar"},
-					"bar": {"bar", pkg.ProjectTypeGit, "github.com/foo/bar"},
-					"zaz": {"zaz", pkg.ProjectTypeGit, "github.com/foo/bar"},
+					"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+					"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+					"zaz": {Name: "zaz", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
 				},
 				Groups: map[string]*pkg.GroupConfig{},
 			}
@@ -194,20 +194,20 @@ func TestProjectRepository(t *testing.T) {
 				ExpectedInString []string
 			}{
 				{
-					&pkg.GroupConfig{"foo", "bar"},
+					&pkg.GroupConfig{Projects: []string{"foo", "bar"}},
 					[]string{
 						"failed to clone project 'foo'",
 						"failed to clone project 'bar'",
 					},
 				},
 				{
-					&pkg.GroupConfig{"foo"},
+					&pkg.GroupConfig{Projects: []string{"foo"}},
 					[]string{
 						"failed to clone project 'foo'",
 					},
 				},
 				{
-					&pkg.GroupConfig{"foo", "bar", "zaz"},
+					&pkg.GroupConfig{Projects: []string{"foo", "bar", "zaz"}},
 					[]string{
 						"failed to clone project 'foo'",
 						"failed to clone project 'bar'",
@@ -233,12 +233,12 @@ func TestProjectRepository(t *testing.T) {
 
 	t.Run("PullProjectsFromGroup", func(t *testing.T) {
 		t.Run("Should return nil if to issues have occurred", func(t *testing.T) {
-			group := &pkg.GroupConfig{"foo", "bar", "zaz"}
+			group := &pkg.GroupConfig{Projects: []string{"foo", "bar", "zaz"}}
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"foo", pkg.ProjectTypeGit, "github.com/foo/bar"},
-					"bar": {"bar", pkg.ProjectTypeGit, "github.com/foo/bar"},
-					"zaz": {"zaz", pkg.ProjectTypeGit, "github.com/foo/bar"},
+					"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+					"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+					"zaz": {Name: "zaz", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
 				},
 				Groups: map[string]*pkg.GroupConfig{},
 			}
@@ -261,10 +261,10 @@ func TestProjectRepository(t *testing.T) {
 		})
 
 		t.Run("should return an error if we've passed a project which does not exist in group or config", func(t *testing.T) {
-			group := &pkg.GroupConfig{"foo", "taz"}
+			group := &pkg.GroupConfig{Projects: []string{"foo", "taz"}}
 			config := &pkg.WildFireConfig{
 				Projects: map[string]*pkg.ProjectConfig{
-					"foo": {"foo", pkg.ProjectTypeGit, "github.com/foo/bar"},
+					"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
 				},
 				Groups: map[string]*pkg.GroupConfig{},
 			}
@@ -319,12 +319,12 @@ func TestProjectRepository(t *testing.T) {
 	})
 
 	t.Run("should return an error if the cloner fails to clone the projects", func(t *testing.T) {
-		group := &pkg.GroupConfig{"foo", "bar", "zaz"}
+		group := &pkg.GroupConfig{Projects: []string{"foo", "bar", "zaz"}}
 		config := &pkg.WildFireConfig{
 			Projects: map[string]*pkg.ProjectConfig{
-				"foo": {"foo", pkg.ProjectTypeGit, "github.com/foo/bar"},
-				"bar": {"bar", pkg.ProjectTypeGit, "github.com/foo/bar"},
-				"zaz": {"zaz", pkg.ProjectTypeGit, "github.com/foo/bar"},
+				"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+				"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+				"zaz": {Name: "zaz", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
 			},
 			Groups: map[string]*pkg.GroupConfig{},
 		}
@@ -1,9 +1,17 @@
 package unit_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"wildfire/cmd/project"
 	"wildfire/pkg"
+	"wildfire/wildfire"
 )
 
 func TestProjectType_ValidType(t *testing.T) {
@@ -12,10 +20,10 @@ func TestProjectType_ValidType(t *testing.T) {
 			Project pkg.ProjectConfig
 			Expected bool
 		}{
-			{ pkg.ProjectConfig{"git", pkg.ProjectTypeGit, "git.com/foo"}, true },
-			{ pkg.ProjectConfig{"gitlab", pkg.ProjectTypeGitLab, "git.com/foo"}, true },
-			{ pkg.ProjectConfig{"bitbucket", pkg.ProjectTypeBitBucket, "git.com/foo"}, true },
-			{ pkg.ProjectConfig{"fake", pkg.ProjectType("fake"), "git.com/foo"}, false },
+			{ pkg.ProjectConfig{Name: "git", Type: pkg.ProjectTypeGit, URL: "git.com/foo"}, true },
+			{ pkg.ProjectConfig{Name: "gitlab", Type: pkg.ProjectTypeGitLab, URL: "git.com/foo"}, true },
+			{ pkg.ProjectConfig{Name: "bitbucket", Type: pkg.ProjectTypeBitBucket, URL: "git.com/foo"}, true },
+			{ pkg.ProjectConfig{Name: "fake", Type: pkg.ProjectType("fake"), URL: "git.com/foo"}, false },
 		}
 
 		for _, test := range tests {
@@ -85,8 +93,8 @@ func TestProject(t *testing.T) {
 	t.Run("RemoveProject", func(t *testing.T) {
 		t.Run("should remove the project from the configuration", func(t *testing.T) {
 			config := &pkg.WildFireConfig{Projects: map[string]*pkg.ProjectConfig{
-				"foo": {"foo", pkg.ProjectTypeGit, "github.com/foo/bar"},
-				"bar": {"bar", pkg.ProjectTypeGit, "github.com/bar/bar"},
+				"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+				"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/bar/bar"},
 			}}
 
 			projectService := pkg.NewProjectService(config)
@@ -109,7 +117,7 @@ func TestProject(t *testing.T) {
 			config := &pkg.WildFireConfig{Projects: map[string]*pkg.ProjectConfig{}}
 			projectService := pkg.NewProjectService(config)
 
-			projectService.UpdateOrCreate(&pkg.ProjectConfig{"foo", pkg.ProjectTypeGit, "github.com/foo/bar"})
+			projectService.UpdateOrCreate(&pkg.ProjectConfig{Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"})
 			fooProject := projectService.GetProject("foo")
 
 			if fooProject == nil {
@@ -121,8 +129,8 @@ func TestProject(t *testing.T) {
 			config := &pkg.WildFireConfig{Projects: map[string]*pkg.ProjectConfig{}}
 			projectService := pkg.NewProjectService(config)
 
-			projectService.UpdateOrCreate(&pkg.ProjectConfig{"foo", pkg.ProjectTypeGit, "github.com/foo/bar"})
-			projectService.UpdateOrCreate(&pkg.ProjectConfig{"foo", pkg.ProjectTypeGitLab, "github.com/foo/bar/zaz"})
+			projectService.UpdateOrCreate(&pkg.ProjectConfig{Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"})
+			projectService.UpdateOrCreate(&pkg.ProjectConfig{Name: "foo", Type: pkg.ProjectTypeGitLab, URL: "github.com/foo/bar/zaz"})
 			fooProject := projectService.GetProject("foo")
 
 			if fooProject.Type != pkg.ProjectTypeGitLab ||
@@ -134,8 +142,8 @@ func TestProject(t *testing.T) {
 
 	t.Run("HasProject", func(t *testing.T) {
 		config := &pkg.WildFireConfig{Projects: map[string]*pkg.ProjectConfig{
-			"foo": {"foo", pkg.ProjectTypeGit, "github.com/foo/bar"},
-			"bar": {"bar", pkg.ProjectTypeGit, "github.com/bar/bar"},
+			"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo/bar"},
+			"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/bar/bar"},
 		}}
 
 		projectService := pkg.NewProjectService(config)
@@ -156,4 +164,243 @@ func TestProject(t *testing.T) {
 			}
 		})
 	})
+	t.Run("FilterProjects", func(t *testing.T) {
+		config := &pkg.WildFireConfig{
+			Projects: map[string]*pkg.ProjectConfig{
+				"foo-api": {Name: "foo-api", Type: pkg.ProjectTypeGit, URL: "github.com/foo/api", Labels: []string{"go", "http"}},
+				"foo-web": {Name: "foo-web", Type: pkg.ProjectTypeGitLab, URL: "gitlab.com/foo/web", Labels: []string{"node", "http"}},
+				"bar":     {Name: "bar", Type: pkg.ProjectTypeBitBucket, URL: "bitbucket.org/bar", Labels: []string{"go"}},
+			},
+			Groups: map[string]*pkg.GroupConfig{
+				"backend": {Projects: []string{"foo-api", "bar"}},
+			},
+		}
+		projectService := pkg.NewProjectService(config)
+
+		tests := []struct {
+			Name     string
+			Filter   pkg.ProjectFilter
+			Expected []string
+		}{
+			{"empty filter", pkg.ProjectFilter{}, []string{"bar", "foo-api", "foo-web"}},
+			{"type", pkg.ProjectFilter{Types: []pkg.ProjectType{pkg.ProjectTypeGit, pkg.ProjectTypeGitLab}}, []string{"foo-api", "foo-web"}},
+			{"label", pkg.ProjectFilter{Labels: []string{"go"}}, []string{"bar", "foo-api"}},
+			{"multiple labels", pkg.ProjectFilter{Labels: []string{"go", "http"}}, []string{"foo-api"}},
+			{"name pattern", pkg.ProjectFilter{NamePattern: "foo-*"}, []string{"foo-api", "foo-web"}},
+			{"group", pkg.ProjectFilter{Groups: []string{"backend"}}, []string{"bar", "foo-api"}},
+			{"combined", pkg.ProjectFilter{NamePattern: "foo-*", Groups: []string{"backend"}}, []string{"foo-api"}},
+		}
+
+		for _, test := range tests {
+			t.Run(fmt.Sprintf("should filter projects by %s", test.Name), func(t *testing.T) {
+				projects, err := projectService.FilterProjects(test.Filter)
+				if err != nil {
+					t.Errorf("FilterProjects should not have returned an error. Error: %s", err)
+				}
+
+				var names []string
+				for _, project := range projects {
+					names = append(names, project.Name)
+				}
+
+				if !reflect.DeepEqual(names, test.Expected) {
+					t.Errorf("Invalid projects returned. Expected '%v' received '%v'", test.Expected, names)
+				}
+			})
+		}
+
+		t.Run("should return an error if the group does not exist", func(t *testing.T) {
+			_, err := projectService.FilterProjects(pkg.ProjectFilter{Groups: []string{"missing"}})
+			if err == nil {
+				t.Error("FilterProjects should have returned an error instead it resolved")
+			}
+		})
+
+		t.Run("should return an error if the name pattern is invalid", func(t *testing.T) {
+			_, err := projectService.FilterProjects(pkg.ProjectFilter{NamePattern: "foo["})
+			if err == nil {
+				t.Error("FilterProjects should have returned an error instead it resolved")
+			}
+		})
+	})
+
+	t.Run("RenameProject", func(t *testing.T) {
+		newConfig := func(workspacePath string) *pkg.WildFireConfig {
+			return &pkg.WildFireConfig{
+				Projects: map[string]*pkg.ProjectConfig{
+					"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo"},
+					"bar": {Name: "bar", Type: pkg.ProjectTypeGit, URL: "github.com/bar"},
+				},
+				Groups: map[string]*pkg.GroupConfig{
+					"api": {Projects: []string{"foo", "bar"}},
+					"web": {Projects: []string{"bar"}},
+				},
+				Workspaces: map[string]*pkg.WorkspaceConfig{
+					"api": {Path: workspacePath, Group: "api", Projects: []string{"foo", "bar"}},
+				},
+			}
+		}
+
+		t.Run("should rename the project and update its references", func(t *testing.T) {
+			workspacePath := t.TempDir()
+			_ = os.Mkdir(filepath.Join(workspacePath, "foo"), 0755)
+			config := newConfig(workspacePath)
+
+			err := pkg.NewProjectService(config).RenameProject("foo", "baz", false)
+			if err != nil {
+				t.Fatalf("RenameProject should not have returned an error. Error: %s", err)
+			}
+
+			if _, ok := config.Projects["foo"]; ok || config.Projects["baz"] == nil || config.Projects["baz"].Name != "baz" {
+				t.Errorf("Project was not renamed. Projects %+v", config.Projects)
+			}
+
+			if expected := []string{"baz", "bar"}; !reflect.DeepEqual(expected, config.Groups["api"].Projects) {
+				t.Errorf("Invalid group projects. Expected %+v received %+v", expected, config.Groups["api"].Projects)
+			}
+
+			if expected := []string{"baz", "bar"}; !reflect.DeepEqual(expected, config.Workspaces["api"].Projects) {
+				t.Errorf("Invalid workspace projects. Expected %+v received %+v", expected, config.Workspaces["api"].Projects)
+			}
+
+			if _, err := os.Stat(filepath.Join(workspacePath, "baz")); err != nil {
+				t.Errorf("Clone was not moved to the new clone path. Error: %s", err)
+			}
+		})
+
+		t.Run("should rename the workspace of the project cloned on its own", func(t *testing.T) {
+			config := newConfig(t.TempDir())
+			config.Workspaces[pkg.ProjectWorkspaceName("foo")] = &pkg.WorkspaceConfig{Path: t.TempDir(), Projects: []string{"foo"}}
+
+			if err := pkg.NewProjectService(config).RenameProject("foo", "baz", false); err != nil {
+				t.Fatalf("RenameProject should not have returned an error. Error: %s", err)
+			}
+
+			workspace := config.Workspaces[pkg.ProjectWorkspaceName("baz")]
+			if workspace == nil || config.Workspaces[pkg.ProjectWorkspaceName("foo")] != nil {
+				t.Fatalf("The workspace of the project should have been renamed. Workspaces %+v", config.Workspaces)
+			}
+			if expected := []string{"baz"}; !reflect.DeepEqual(expected, workspace.Projects) {
+				t.Errorf("Invalid workspace projects. Expected %+v received %+v", expected, workspace.Projects)
+			}
+		})
+
+		t.Run("should return an error if the project does not exist", func(t *testing.T) {
+			err := pkg.NewProjectService(newConfig(t.TempDir())).RenameProject("zaz", "baz", false)
+			if err == nil {
+				t.Error("RenameProject should have returned an error instead it resolved")
+			}
+		})
+
+		t.Run("should not change the configuration if the new name is taken", func(t *testing.T) {
+			config := newConfig(t.TempDir())
+
+			err := pkg.NewProjectService(config).RenameProject("foo", "bar", false)
+			if err == nil {
+				t.Error("RenameProject should have returned an error instead it resolved")
+			}
+
+			if config.Projects["foo"] == nil || !reflect.DeepEqual([]string{"foo", "bar"}, config.Groups["api"].Projects) {
+				t.Errorf("Configuration should not have been changed. Projects %+v", config.Projects)
+			}
+		})
+
+		t.Run("should not change the configuration if a clone can not be moved", func(t *testing.T) {
+			workspacePath := t.TempDir()
+			_ = os.Mkdir(filepath.Join(workspacePath, "foo"), 0755)
+			_ = os.Mkdir(filepath.Join(workspacePath, "baz"), 0755)
+			config := newConfig(workspacePath)
+
+			err := pkg.NewProjectService(config).RenameProject("foo", "baz", false)
+			if err == nil {
+				t.Error("RenameProject should have returned an error instead it resolved")
+			}
+
+			if config.Projects["foo"] == nil || config.Workspaces["api"].Projects[0] != "foo" {
+				t.Errorf("Configuration should not have been changed. Projects %+v", config.Projects)
+			}
+		})
+	})
+}
+
+func TestSortProjects(t *testing.T) {
+	projects := []*pkg.ProjectConfig{
+		{Name: "foo", Type: pkg.ProjectTypeGitLab, URL: "b.com/foo"},
+		{Name: "bar", Type: pkg.ProjectTypeGit, URL: "c.com/bar"},
+		{Name: "zaz", Type: pkg.ProjectTypeGit, URL: "a.com/zaz"},
+	}
+
+	tests := []struct {
+		Field    pkg.ProjectSortField
+		Expected []string
+	}{
+		{pkg.ProjectSortByName, []string{"bar", "foo", "zaz"}},
+		{pkg.ProjectSortByType, []string{"bar", "zaz", "foo"}},
+		{pkg.ProjectSortByURL, []string{"zaz", "foo", "bar"}},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("should sort projects by %s", test.Field), func(t *testing.T) {
+			pkg.SortProjects(projects, test.Field)
+
+			var names []string
+			for _, project := range projects {
+				names = append(names, project.Name)
+			}
+
+			if !reflect.DeepEqual(names, test.Expected) {
+				t.Errorf("Invalid project order. Expected '%v' received '%v'", test.Expected, names)
+			}
+		})
+	}
+}
+
+// failingSaveStore is a configuration store in memory which fails to save the configuration.
+type failingSaveStore struct {
+	*pkg.MemoryConfigStore
+}
+
+func (s failingSaveStore) Save(*pkg.WildFireConfig) error {
+	return errors.New("the configuration can not be saved")
+}
+
+func TestRenameProjectSaveFailure(t *testing.T) {
+	newStore := func(workspacePath string) failingSaveStore {
+		_ = os.Mkdir(filepath.Join(workspacePath, "foo"), 0755)
+
+		return failingSaveStore{pkg.NewMemoryConfigStore(&pkg.WildFireConfig{
+			Projects: map[string]*pkg.ProjectConfig{"foo": {Name: "foo", Type: pkg.ProjectTypeGit, URL: "github.com/foo"}},
+			Workspaces: map[string]*pkg.WorkspaceConfig{
+				"api": {Path: workspacePath, Projects: []string{"foo"}},
+			},
+		})}
+	}
+
+	t.Run("should move the clones back when the client can not save the configuration", func(t *testing.T) {
+		workspacePath := t.TempDir()
+		client := wildfire.NewClientFromStore(newStore(workspacePath))
+
+		if err := client.RenameProject(context.Background(), "foo", "baz"); err == nil {
+			t.Fatal("RenameProject should have returned an error instead it resolved")
+		}
+
+		if _, err := os.Stat(filepath.Join(workspacePath, "foo")); err != nil {
+			t.Errorf("The clone should have been moved back. Error: %s", err)
+		}
+	})
+
+	t.Run("should move the clones back when the command can not save the configuration", func(t *testing.T) {
+		workspacePath := t.TempDir()
+
+		cmd := project.NewRenameProjectCmd()
+		cmd.SetArgs([]string{"foo", "baz"})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.ExecuteContext(pkg.WithConfigStore(context.Background(), newStore(workspacePath))); err == nil {
+			t.Fatal("RenameProjectCmd should have returned an error instead it resolved")
+		}
+
+		if _, err := os.Stat(filepath.Join(workspacePath, "foo")); err != nil {
+			t.Errorf("The clone should have been moved back. Error: %s", err)
+		}
+	})
 }
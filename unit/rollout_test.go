@@ -0,0 +1,180 @@
+package unit_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"wildfire/cmd/exec"
+	"wildfire/pkg"
+)
+
+func TestRolloutWaves(t *testing.T) {
+	projects := []string{"a", "b", "c", "d", "e"}
+
+	for _, test := range []struct {
+		canary   string
+		waveSize string
+		expected [][]string
+	}{
+		{"1", "", [][]string{{"a"}, {"b", "c", "d", "e"}}},
+		{"1", "40%", [][]string{{"a"}, {"b", "c"}, {"d", "e"}}},
+		{"", "3", [][]string{{"a", "b", "c"}, {"d", "e"}}},
+		{"10%", "10", [][]string{{"a"}, {"b", "c", "d", "e"}}},
+	} {
+		rollout := pkg.Rollout{}
+		if test.canary != "" {
+			rollout.Canary, _ = pkg.ParseRolloutSize(test.canary)
+		}
+		if test.waveSize != "" {
+			rollout.WaveSize, _ = pkg.ParseRolloutSize(test.waveSize)
+		}
+
+		var waves [][]string
+		for _, wave := range rollout.Waves(projects) {
+			waves = append(waves, wave.Projects)
+		}
+
+		if reflect.DeepEqual(waves, test.expected) == false {
+			t.Errorf("Invalid waves for canary '%s' and wave size '%s'. Expected %v received %v", test.canary, test.waveSize, test.expected, waves)
+		}
+	}
+
+	for _, value := range []string{"0", "-1", "0%", "150%", "abc"} {
+		if _, err := pkg.ParseRolloutSize(value); err == nil {
+			t.Errorf("An error should have been returned for '%s'", value)
+		}
+	}
+}
+
+// newRolloutConfig returns a configuration with a backend workspace cloning the projects, in which the projects with
+// a config.txt file succeed to run cat config.txt.
+func newRolloutConfig(t *testing.T, projects []string, withFile []string) (*pkg.WildFireConfig, string) {
+	workspace := t.TempDir()
+	config := &pkg.WildFireConfig{
+		Projects:   map[string]*pkg.ProjectConfig{},
+		Workspaces: map[string]*pkg.WorkspaceConfig{"backend": {Path: workspace, Projects: projects}},
+	}
+	for _, name := range projects {
+		config.Projects[name] = &pkg.ProjectConfig{Name: name, Type: pkg.ProjectTypeGit, URL: pkg.ProjectPath("github.com/org/" + name)}
+		_ = os.MkdirAll(filepath.Join(workspace, name), 0755)
+	}
+	for _, name := range withFile {
+		writeTestFile(t, filepath.Join(workspace, name, "config.txt"), name)
+	}
+
+	return config, workspace
+}
+
+func runRolloutCmd(config *pkg.WildFireConfig, historyStore *pkg.HistoryStore, args []string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.NewExecCmd()
+	cmd.SetArgs(args)
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	ctx := pkg.WithHistory(pkg.WithConfigStore(context.Background(), pkg.NewMemoryConfigStore(config)), historyStore, args)
+	err := cmd.ExecuteContext(ctx)
+
+	return stdout.String(), stderr.String(), err
+}
+
+func TestExecRollout(t *testing.T) {
+	t.Run("should stop the rollout when the failure rate of a wave is exceeded", func(t *testing.T) {
+		config, workspace := newRolloutConfig(t, []string{"a", "b", "c", "d"}, []string{"a", "b"})
+		args := []string{"backend", "--canary", "1", "--wave-size", "50%", "--auto", "--", "cat", "config.txt"}
+		historyStore := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+
+		stdout, stderr, err := runRolloutCmd(config, historyStore, args)
+		if err == nil || strings.Contains(err.Error(), "Rollout stopped after wave 2 of 3") == false {
+			t.Fatalf("The rollout should have stopped after the second wave. Received %v", err)
+		}
+
+		if strings.Contains(stdout, "d |") || strings.Contains(stderr, "'d'") {
+			t.Errorf("The command should not have run in the last wave. Received:\n%s%s", stdout, stderr)
+		}
+		for _, expected := range []string{"1 (canary)", "failure rate exceeded", "not run"} {
+			if strings.Contains(stderr, expected) == false {
+				t.Errorf("The report should contain '%s'. Received:\n%s", expected, stderr)
+			}
+		}
+
+		checkpoints := pkg.FindCheckpoints(workspace)
+		if len(checkpoints) != 1 {
+			t.Fatalf("The checkpoint of the stopped rollout should have been kept. Received %+v", checkpoints)
+		}
+		if incomplete := checkpoints[0].Incomplete(checkpoints[0].Steps[0]); reflect.DeepEqual(incomplete, []string{"c", "d"}) == false {
+			t.Errorf("The failed project and the last wave should remain. Received %v", incomplete)
+		}
+	})
+
+	t.Run("should fail when the failure rate of the last wave is exceeded", func(t *testing.T) {
+		config, workspace := newRolloutConfig(t, []string{"a", "b"}, []string{"a"})
+		args := []string{"backend", "--canary", "1", "--auto", "--", "cat", "config.txt"}
+		historyStore := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+
+		_, stderr, err := runRolloutCmd(config, historyStore, args)
+		if err == nil || strings.Contains(err.Error(), "Rollout stopped after wave 2 of 2") == false {
+			t.Fatalf("The failure rate of the last wave should have failed the rollout. Received %v", err)
+		}
+		if strings.Contains(stderr, "failure rate exceeded") == false {
+			t.Errorf("The last wave should have been reported as exceeding the failure rate. Received:\n%s", stderr)
+		}
+		if checkpoints := pkg.FindCheckpoints(workspace); len(checkpoints) != 0 {
+			t.Errorf("The checkpoint of the completed rollout should have been removed. Received %+v", checkpoints)
+		}
+	})
+
+	t.Run("should only split the projects matching the predicates into waves", func(t *testing.T) {
+		config, _ := newRolloutConfig(t, []string{"a", "b", "c", "d"}, []string{"c", "d"})
+		args := []string{"backend", "--where", "file-exists:config.txt", "--canary", "1", "--wave-size", "50%", "--auto", "--", "cat", "config.txt"}
+		historyStore := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+
+		_, stderr, err := runRolloutCmd(config, historyStore, args)
+		if err != nil {
+			t.Fatalf("The rollout should not have returned an error. Error: %s", err)
+		}
+
+		for _, expected := range []string{"Wave 1 of 2 (canary): c\n", "Wave 2 of 2: d\n"} {
+			if strings.Contains(stderr, expected) == false {
+				t.Errorf("The report should contain '%s'. Received:\n%s", expected, stderr)
+			}
+		}
+
+		entries, _ := historyStore.List()
+		if len(entries) != 1 {
+			t.Fatalf("The rollout should have been recorded. Received %+v", entries)
+		}
+		statuses := map[string]pkg.HistoryStatus{}
+		for _, project := range entries[0].Projects {
+			statuses[project.Project] = project.Status
+		}
+		expected := map[string]pkg.HistoryStatus{
+			"a": pkg.HistoryStatusSkipped,
+			"b": pkg.HistoryStatusSkipped,
+			"c": pkg.HistoryStatusSucceeded,
+			"d": pkg.HistoryStatusSucceeded,
+		}
+		if reflect.DeepEqual(statuses, expected) == false {
+			t.Errorf("Invalid recorded statuses. Expected %v received %v", expected, statuses)
+		}
+	})
+	t.Run("should fail the projects whose dependencies failed in an earlier wave", func(t *testing.T) {
+		config, _ := newRolloutConfig(t, []string{"lib", "app"}, []string{"app"})
+		config.Projects["app"].DependsOn = []string{"lib"}
+		args := []string{"backend", "--canary", "1", "--max-failure-rate", "100%", "--auto", "--", "cat", "config.txt"}
+		historyStore := pkg.NewHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+
+		stdout, stderr, err := runRolloutCmd(config, historyStore, args)
+		if err == nil {
+			t.Fatal("The rollout should have failed")
+		}
+
+		if strings.Contains(stdout, "app |") || strings.Contains(stderr, "dependency 'lib'") == false {
+			t.Errorf("The command should not have run in 'app' after failing in 'lib'. Received:\n%s%s", stdout, stderr)
+		}
+	})
+}
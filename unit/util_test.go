@@ -2,9 +2,10 @@ package unit_test
 
 import (
 	"fmt"
-	"github.com/spf13/viper"
 	"os"
 	"path/filepath"
+	"testing"
+	"wildfire/pkg"
 )
 
 func getConfigFilePath(fileName string) string {
@@ -12,18 +13,21 @@ func getConfigFilePath(fileName string) string {
 	return filepath.FromSlash(fmt.Sprintf("%s/testdata/%s", dir, fileName))
 }
 
-func setConfig(cfgFile string) error {
-	viper.Reset()
-	viper.SetConfigFile(cfgFile)
-	err := viper.ReadInConfig()
-
+// loadConfigFile returns the configuration of the file, see pkg.LoadConfigFile.
+func loadConfigFile(t *testing.T, cfgFile string) *pkg.WildFireConfig {
+	config, err := pkg.LoadConfigFile(cfgFile)
 	if err != nil {
-		return fmt.Errorf("failed to load '%s' configuration file. Error %s", cfgFile, err)
+		t.Fatalf("Failed to load configuration '%s'. Error: %s", cfgFile, err)
 	}
 
-	return nil
+	return config
 }
 
 func deleteConfig(cfgFile string) error {
+	backups, _ := filepath.Glob(cfgFile + ".bak.*")
+	for _, backup := range backups {
+		_ = os.Remove(backup)
+	}
+
 	return os.Remove(cfgFile)
 }
@@ -0,0 +1,111 @@
+package unit_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"wildfire/pkg"
+)
+
+func TestWorkspace(t *testing.T) {
+	t.Run("SetWorkspace", func(t *testing.T) {
+		t.Run("should add the workspace to the configuration", func(t *testing.T) {
+			config := &pkg.WildFireConfig{}
+			workspaceService := pkg.NewWorkspaceService(config)
+
+			workspaceService.SetWorkspace("foo", &pkg.WorkspaceConfig{Path: "/tmp/foo", Projects: []string{"bar"}})
+
+			if workspaceService.GetWorkspace("foo") == nil {
+				t.Error("Failed to find workspace in configuration")
+			}
+		})
+	})
+
+	t.Run("DeleteWorkspace", func(t *testing.T) {
+		t.Run("should remove the workspace from the configuration", func(t *testing.T) {
+			config := &pkg.WildFireConfig{Workspaces: map[string]*pkg.WorkspaceConfig{
+				"foo": {Path: "/tmp/foo"},
+			}}
+			workspaceService := pkg.NewWorkspaceService(config)
+
+			workspaceService.DeleteWorkspace("foo")
+
+			if len(config.Workspaces) != 0 {
+				t.Error("Workspace was not removed from configuration")
+			}
+		})
+	})
+
+	t.Run("GetProjectWorkspaces", func(t *testing.T) {
+		t.Run("should return the workspaces which contain a clone of the project", func(t *testing.T) {
+			config := &pkg.WildFireConfig{Workspaces: map[string]*pkg.WorkspaceConfig{
+				"foo": {Path: "/tmp/foo", Projects: []string{"foo", "bar"}},
+				"bar": {Path: "/tmp/bar", Projects: []string{"bar"}},
+				"zaz": {Path: "/tmp/zaz", Projects: []string{"zaz"}},
+			}}
+			workspaceService := pkg.NewWorkspaceService(config)
+
+			result := workspaceService.GetProjectWorkspaces("bar")
+
+			expected := []string{"bar", "foo"}
+			if !reflect.DeepEqual(expected, result) {
+				t.Errorf("Invalid workspaces returned. Expected %+v received %+v", expected, result)
+			}
+		})
+	})
+
+	t.Run("GetClonePath", func(t *testing.T) {
+		t.Run("should return the path of the project inside the workspace", func(t *testing.T) {
+			workspaceService := pkg.NewWorkspaceService(&pkg.WildFireConfig{})
+
+			result := workspaceService.GetClonePath(&pkg.WorkspaceConfig{Path: "/tmp/foo"}, "bar")
+
+			if result != filepath.FromSlash("/tmp/foo/bar") {
+				t.Errorf("Invalid clone path returned. Expected '%s' received '%s'", filepath.FromSlash("/tmp/foo/bar"), result)
+			}
+		})
+	})
+
+	t.Run("GetClones", func(t *testing.T) {
+		t.Run("should return the clones of the workspace projects", func(t *testing.T) {
+			workspaceService := pkg.NewWorkspaceService(&pkg.WildFireConfig{})
+
+			result := workspaceService.GetClones(&pkg.WorkspaceConfig{Path: "/tmp/foo", Projects: []string{"bar", "zaz"}})
+
+			expected := []pkg.Clone{
+				{Project: "bar", Path: filepath.FromSlash("/tmp/foo/bar")},
+				{Project: "zaz", Path: filepath.FromSlash("/tmp/foo/zaz")},
+			}
+			if !reflect.DeepEqual(expected, result) {
+				t.Errorf("Invalid clones returned. Expected %+v received %+v", expected, result)
+			}
+		})
+	})
+
+	t.Run("FindWorkspace", func(t *testing.T) {
+		workspaceService := pkg.NewWorkspaceService(&pkg.WildFireConfig{
+			Workspaces: map[string]*pkg.WorkspaceConfig{
+				"foo":     {Path: "/tmp/foo", Group: "foo"},
+				"release": {Path: "/tmp/release", Group: "bar"},
+			},
+		})
+
+		t.Run("should return the workspace with the name", func(t *testing.T) {
+			if result := workspaceService.FindWorkspace("foo"); result == nil || result.Path != "/tmp/foo" {
+				t.Errorf("Invalid workspace returned. Received %+v", result)
+			}
+		})
+
+		t.Run("should return the workspace cloned from the group", func(t *testing.T) {
+			if result := workspaceService.FindWorkspace("bar"); result == nil || result.Path != "/tmp/release" {
+				t.Errorf("Invalid workspace returned. Received %+v", result)
+			}
+		})
+
+		t.Run("should return nil if there is no workspace", func(t *testing.T) {
+			if result := workspaceService.FindWorkspace("zaz"); result != nil {
+				t.Errorf("No workspace should have been returned. Received %+v", result)
+			}
+		})
+	})
+}
@@ -0,0 +1,152 @@
+// Package wildfire exposes the operations of the wildfire CLI to Go programs. The methods of Client return
+// structured results and never print.
+package wildfire
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"wildfire/pkg"
+	"wildfire/pkg/project_repository"
+)
+
+// ErrNotFound is returned, wrapped, when a project, group or workspace does not exist in the configuration.
+var ErrNotFound = errors.New("does not exist in configuration")
+
+// ErrDryRun is returned by the operations which change clones, e.g. Clone and Exec, when they are called with the
+// context of a dry run, see pkg.RunOptions. Use pkg.PlanClone and pkg.PlanCommand to plan them instead.
+var ErrDryRun = errors.New("the operation can not run in a dry run")
+
+// Client runs operations on a wildfire configuration. It is safe for concurrent use.
+type Client struct {
+	// NewCloner creates the cloner used to clone projects. Branch is the branch to check out, empty for the default
+	// branch of the repository. Projects are cloned with git when it is not set.
+	NewCloner func(branch string) project_repository.Cloner
+
+	store  pkg.ConfigStore
+	config *pkg.WildFireConfig
+	mutex  sync.Mutex
+}
+
+// NewClient creates a client for the configuration file. The file is loaded for every operation and locked while it
+// is updated so the CLI can be used at the same time. The file is created when the configuration is first updated.
+// Use NewClientFromStore with a pkg.FileConfigStore to merge other configuration layers beneath the file.
+func NewClient(configPath string) (*Client, error) {
+	if configPath == "" {
+		return nil, errors.New("a configuration file is required")
+	}
+
+	path, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientFromStore(pkg.NewFileConfigStore(path)), nil
+}
+
+// NewClientFromStore creates a client for the configuration of the store. The configuration is loaded for every
+// operation and the store is locked while it is updated.
+func NewClientFromStore(store pkg.ConfigStore) *Client {
+	return &Client{store: store}
+}
+
+// NewClientFromConfig creates a client operating on the configuration in memory. Changes are made to the
+// configuration and are not saved.
+func NewClientFromConfig(config *pkg.WildFireConfig) *Client {
+	return &Client{config: config}
+}
+
+// ConfigPath returns the configuration file of the client, empty when it operates on a configuration in memory.
+func (c *Client) ConfigPath() string {
+	if c.store == nil {
+		return ""
+	}
+
+	return c.store.Path()
+}
+
+// Config returns the configuration. Unless the client operates on a configuration in memory the returned
+// configuration is a copy, changing it has no effect.
+func (c *Client) Config(ctx context.Context) (*pkg.WildFireConfig, error) {
+	var res *pkg.WildFireConfig
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		res = config
+		return nil
+	})
+
+	return res, err
+}
+
+// read runs fn with the current configuration.
+func (c *Client) read(ctx context.Context, fn func(config *pkg.WildFireConfig) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if c.config != nil {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		return fn(c.config)
+	}
+
+	config, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+
+	return fn(config)
+}
+
+// update runs fn with the current configuration and saves the changes it makes unless it returns an error. The
+// configuration file is locked until the changes are saved. In a dry run the changes are not saved, except to a
+// configuration in memory.
+func (c *Client) update(ctx context.Context, fn func(config *pkg.WildFireConfig) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if c.config == nil && pkg.IsDryRun(ctx) {
+		return c.read(ctx, fn)
+	}
+
+	return c.save(fn)
+}
+
+// save is update without checking the context, used to record the outcome of an operation which completed at least
+// in part. fn must not block, other commands wait for the configuration file while it runs.
+func (c *Client) save(fn func(config *pkg.WildFireConfig) error) error {
+	if c.config != nil {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		return fn(c.config)
+	}
+
+	lock, err := c.store.Lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	config, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(config); err != nil {
+		return err
+	}
+
+	return c.store.Save(config)
+}
+
+func (c *Client) cloner(branch string) project_repository.Cloner {
+	if c.NewCloner != nil {
+		return c.NewCloner(branch)
+	}
+
+	return &project_repository.GitCloner{Branch: branch}
+}
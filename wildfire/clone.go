@@ -0,0 +1,448 @@
+package wildfire
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"wildfire/pkg"
+	"wildfire/pkg/project_repository"
+)
+
+// CloneOptions configures Clone.
+type CloneOptions struct {
+	// Path is the directory of the workspace, projects are cloned to Path/<project>. It defaults to
+	// <group path>/<group>.
+	Path string
+	// Projects are the projects to clone, every project of the group when empty.
+	Projects []string
+}
+
+// CloneResult is the outcome of cloning or updating the clone of a project. Cloned is set when the project has been
+// cloned, Skipped when a clone already existed. Output is the output of git when an existing clone was updated.
+type CloneResult struct {
+	pkg.Clone
+	Cloned  bool
+	Skipped bool
+	Output  string
+	Err     error
+}
+
+// Clone clones the projects of the group in parallel, on the branch of the group, and saves the workspace named
+// after the group. Projects which have already been cloned to the workspace are skipped. The workspace contains the
+// projects which were cloned or skipped, failures are only reported in the results, which are in the order of the
+// projects. The configuration is only locked to save the workspace once the projects have been cloned.
+func (c *Client) Clone(ctx context.Context, groupName string, options CloneOptions) ([]CloneResult, error) {
+	if pkg.IsDryRun(ctx) {
+		return nil, ErrDryRun
+	}
+
+	var config *pkg.WildFireConfig
+	var path, branch string
+	var clones []pkg.Clone
+
+	err := c.read(ctx, func(loaded *pkg.WildFireConfig) error {
+		group, err := getGroup(loaded, groupName)
+		if err != nil {
+			return err
+		}
+		if group, err = loaded.ResolveGroup(groupName, group); err != nil {
+			return err
+		}
+
+		projects := options.Projects
+		if len(projects) == 0 {
+			if projects, err = pkg.NewGroupService(loaded).GetGroupProjects(groupName); err != nil {
+				return err
+			}
+		}
+
+		path = options.Path
+		if path == "" && group.Path == "" {
+			return fmt.Errorf("group '%s' has no default path, a path is required", groupName)
+		}
+		if path == "" {
+			path = filepath.Join(group.Path, groupName)
+		}
+
+		workspaceService := pkg.NewWorkspaceService(loaded)
+		workspace := &pkg.WorkspaceConfig{Path: path}
+		for _, project := range projects {
+			clones = append(clones, pkg.Clone{Project: project, Path: workspaceService.GetClonePath(workspace, project)})
+		}
+
+		config, branch = loaded, group.Branch
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := c.cloneProjects(ctx, config, branch, clones)
+
+	err = c.save(func(config *pkg.WildFireConfig) error {
+		workspaceService := pkg.NewWorkspaceService(config)
+		workspace := &pkg.WorkspaceConfig{Path: path, Group: groupName, Projects: []string{}}
+		if existing := workspaceService.GetWorkspace(groupName); existing != nil && existing.Path == path {
+			workspace.Projects = existing.Projects
+		}
+
+		addClonedProjects(workspace, res)
+		workspaceService.SetWorkspace(groupName, workspace)
+
+		return nil
+	})
+
+	return res, err
+}
+
+// CloneProject clones the project to path/<project> and saves the workspace named pkg.ProjectWorkspaceName. The
+// configuration is only locked to save the workspace once the project has been cloned.
+func (c *Client) CloneProject(ctx context.Context, projectName string, path string) (CloneResult, error) {
+	if pkg.IsDryRun(ctx) {
+		return CloneResult{}, ErrDryRun
+	}
+
+	var config *pkg.WildFireConfig
+
+	err := c.read(ctx, func(loaded *pkg.WildFireConfig) error {
+		if pkg.NewProjectService(loaded).GetProject(projectName) == nil {
+			return fmt.Errorf("project '%s' %w", projectName, ErrNotFound)
+		}
+
+		config = loaded
+
+		return nil
+	})
+	if err != nil {
+		return CloneResult{}, err
+	}
+
+	clone := pkg.Clone{Project: projectName, Path: filepath.Join(path, projectName)}
+	res := c.cloneProjects(ctx, config, "", []pkg.Clone{clone})
+
+	err = c.save(func(config *pkg.WildFireConfig) error {
+		workspace := &pkg.WorkspaceConfig{Path: path, Projects: []string{}}
+		addClonedProjects(workspace, res)
+		if len(workspace.Projects) != 0 {
+			pkg.NewWorkspaceService(config).SetWorkspace(pkg.ProjectWorkspaceName(projectName), workspace)
+		}
+
+		return nil
+	})
+
+	return res[0], err
+}
+
+// Sync updates the clones of the workspace, or of the workspace cloned from the group, with the name. Existing
+// clones are fast-forwarded with git pull, projects added to the group since it was cloned and missing clones are
+// cloned and added to the workspace. The results are in the order of the projects. The configuration is only
+// locked to save the workspace once the clones have been updated.
+func (c *Client) Sync(ctx context.Context, name string) ([]CloneResult, error) {
+	if pkg.IsDryRun(ctx) {
+		return nil, ErrDryRun
+	}
+
+	var config *pkg.WildFireConfig
+	var projects []string
+	var branch string
+	var missing, existing []pkg.Clone
+
+	err := c.read(ctx, func(loaded *pkg.WildFireConfig) error {
+		workspaceService := pkg.NewWorkspaceService(loaded)
+		groupService := pkg.NewGroupService(loaded)
+
+		workspace := workspaceService.FindWorkspace(name)
+		if workspace == nil {
+			return fmt.Errorf("workspace '%s' %w", name, ErrNotFound)
+		}
+
+		projects = append([]string{}, workspace.Projects...)
+		if group := groupService.GetGroup(workspace.Group); group != nil {
+			groupProjects, err := groupService.GetGroupProjects(workspace.Group)
+			if err != nil {
+				return err
+			}
+
+			if group, err = loaded.ResolveGroup(workspace.Group, group); err != nil {
+				return err
+			}
+
+			projects = appendMissing(projects, groupProjects...)
+			branch = group.Branch
+		}
+
+		for _, project := range projects {
+			clone := pkg.Clone{Project: project, Path: workspaceService.GetClonePath(workspace, project)}
+			if _, err := os.Stat(clone.Path); err != nil {
+				missing = append(missing, clone)
+			} else {
+				existing = append(existing, clone)
+			}
+		}
+
+		config = loaded
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cloned := c.cloneProjects(ctx, config, branch, missing)
+
+	results := map[string]CloneResult{}
+	for _, result := range cloned {
+		results[result.Project] = result
+	}
+	report := func(result pkg.ExecResult) {
+		reportCloneResult(ctx, CloneResult{Clone: result.Clone, Output: result.Output, Err: result.Err}, ProjectStatusUpdated)
+	}
+	for _, result := range execInClones(ctx, existing, nil, nil, nil, report, "git", "pull", "--ff-only") {
+		results[result.Project] = CloneResult{Clone: result.Clone, Output: result.Output, Err: result.Err}
+	}
+
+	var res []CloneResult
+	for _, project := range projects {
+		res = append(res, results[project])
+	}
+
+	err = c.save(func(config *pkg.WildFireConfig) error {
+		workspace := pkg.NewWorkspaceService(config).FindWorkspace(name)
+		if workspace == nil {
+			return fmt.Errorf("workspace '%s' %w", name, ErrNotFound)
+		}
+
+		addClonedProjects(workspace, cloned)
+
+		return nil
+	})
+
+	return res, err
+}
+
+type dependencyResultsKey struct{}
+
+// WithDependencyResults returns a context in which Exec checks the dependencies which are not part of the call
+// against the results, such as the results of the earlier waves of a rollout.
+func WithDependencyResults(ctx context.Context, results []pkg.ExecResult) context.Context {
+	return context.WithValue(ctx, dependencyResultsKey{}, results)
+}
+
+func getDependencyResults(ctx context.Context) map[string]pkg.ExecResult {
+	res := map[string]pkg.ExecResult{}
+	if results, ok := ctx.Value(dependencyResultsKey{}).([]pkg.ExecResult); ok {
+		for _, result := range results {
+			res[result.Project] = result
+		}
+	}
+
+	return res
+}
+
+// Exec runs the command in parallel in the clones of the workspace, or of the workspace cloned from the group, with
+// the name. Only the clones matching every predicate run the command. When command is empty the predicates are only
+// evaluated. The command only runs in a project once it succeeded in the projects it depends on, directly or through
+// other projects, see pkg.ProjectConfig.DependsOn, and fails without running when it did not succeed in one of them.
+// Dependencies which did not match the predicates do not prevent the command from running, and neither do
+// dependencies which are not part of the call unless they failed in the results of the context, see
+// WithDependencyResults. A pkg.DependencyCycleError is returned when projects depend on each other. The results are in
+// the order of the projects. In a dry run only the predicates can be evaluated.
+func (c *Client) Exec(
+	ctx context.Context,
+	name string,
+	predicates []pkg.ClonePredicate,
+	command string,
+	args ...string,
+) ([]pkg.ExecResult, error) {
+	if command != "" && pkg.IsDryRun(ctx) {
+		return nil, ErrDryRun
+	}
+
+	var clones []pkg.Clone
+	var dependencies pkg.ProjectDependencies
+	previous := getDependencyResults(ctx)
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		workspaceService := pkg.NewWorkspaceService(config)
+
+		workspace := workspaceService.FindWorkspace(name)
+		if workspace == nil {
+			return fmt.Errorf("workspace '%s' %w", name, ErrNotFound)
+		}
+
+		clones = pkg.SelectClones(ctx, workspaceService.GetClones(workspace))
+
+		if command != "" {
+			var projects []string
+			for _, clone := range clones {
+				projects = append(projects, clone.Project)
+			}
+			for project := range previous {
+				projects = append(projects, project)
+			}
+
+			dependencies = pkg.GetProjectDependencies(config, projects)
+			if cycle := dependencies.Cycle(); cycle != nil {
+				return &pkg.DependencyCycleError{Cycle: cycle}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := func(result pkg.ExecResult) {
+		reportExecResult(ctx, result, command != "")
+	}
+
+	return execInClones(ctx, clones, predicates, dependencies, previous, report, command, args...), nil
+}
+
+// execInClones runs the command in every clone in parallel and passes the result of each clone to report as soon
+// as it is known. When command is empty the predicates are only evaluated. A clone waits for the clones of the
+// projects it depends on and fails without running the command when it did not succeed in one of them. Dependencies
+// which are not part of clones are looked up in previous, and ignored when they are not found. Dependencies must not
+// contain cycles.
+func execInClones(
+	ctx context.Context,
+	clones []pkg.Clone,
+	predicates []pkg.ClonePredicate,
+	dependencies pkg.ProjectDependencies,
+	previous map[string]pkg.ExecResult,
+	report func(result pkg.ExecResult),
+	command string,
+	args ...string,
+) []pkg.ExecResult {
+	res := make([]pkg.ExecResult, len(clones))
+
+	indexes := map[string]int{}
+	done := make([]chan struct{}, len(clones))
+	for i, clone := range clones {
+		indexes[clone.Project] = i
+		done[i] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(clones))
+
+	for i, clone := range clones {
+		go func(i int, clone []pkg.Clone) {
+			defer wg.Done()
+			defer close(done[i])
+
+			result := &res[i]
+			for _, dependency := range dependencies[clone[0].Project] {
+				dependencyResult, ok := previous[dependency]
+				if index, found := indexes[dependency]; found {
+					<-done[index]
+					dependencyResult, ok = res[index], true
+				}
+
+				if ok && dependencyResult.Err != nil {
+					*result = pkg.ExecResult{
+						Clone: clone[0],
+						Err:   fmt.Errorf("the command did not succeed in dependency '%s'", dependency),
+					}
+					report(*result)
+
+					return
+				}
+			}
+
+			if command == "" {
+				*result = pkg.FilterClones(clone, predicates)[0]
+			} else {
+				*result = pkg.ExecInClonesContext(ctx, clone, predicates, command, args...)[0]
+			}
+
+			report(*result)
+		}(i, []pkg.Clone{clone})
+	}
+
+	wg.Wait()
+
+	return res
+}
+
+// cloneProjects clones the projects in parallel. Clones which already exist are skipped, failed clones are removed.
+func (c *Client) cloneProjects(
+	ctx context.Context,
+	config *pkg.WildFireConfig,
+	branch string,
+	clones []pkg.Clone,
+) []CloneResult {
+	projectService := pkg.NewProjectService(config)
+	cloner := project_repository.NewResolvingCloner(config, c.cloner(branch))
+
+	res := make([]CloneResult, len(clones))
+
+	var wg sync.WaitGroup
+	wg.Add(len(clones))
+
+	for i, clone := range clones {
+		res[i].Clone = clone
+
+		go func(result *CloneResult, project *pkg.ProjectConfig) {
+			defer wg.Done()
+			defer func() {
+				reportCloneResult(ctx, *result, ProjectStatusCloned)
+			}()
+
+			if result.Err = ctx.Err(); result.Err != nil {
+				return
+			}
+
+			if project == nil {
+				result.Err = fmt.Errorf("project '%s' %w", result.Project, ErrNotFound)
+				return
+			}
+
+			if _, err := os.Stat(result.Path); err == nil {
+				result.Skipped = true
+				return
+			}
+
+			if result.Err = project_repository.CloneProjectContext(ctx, cloner, result.Path, project); result.Err != nil {
+				_ = os.RemoveAll(result.Path)
+				return
+			}
+
+			result.Cloned = true
+		}(&res[i], projectService.GetProject(clone.Project))
+	}
+
+	wg.Wait()
+
+	return res
+}
+
+func addClonedProjects(workspace *pkg.WorkspaceConfig, results []CloneResult) {
+	for _, result := range results {
+		if result.Cloned || result.Skipped {
+			workspace.Projects = appendMissing(workspace.Projects, result.Project)
+		}
+	}
+}
+
+func appendMissing(values []string, additions ...string) []string {
+	for _, addition := range additions {
+		found := false
+		for _, value := range values {
+			if value == addition {
+				found = true
+				break
+			}
+		}
+
+		if found == false {
+			values = append(values, addition)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,314 @@
+package wildfire
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"wildfire/pkg"
+)
+
+// GroupMember is a project of a group. Missing is set when the project no longer exists in the configuration.
+type GroupMember struct {
+	Name      string          `json:"name"`
+	Type      pkg.ProjectType `json:"type,omitempty"`
+	URL       pkg.ProjectPath `json:"url,omitempty"`
+	DependsOn []string        `json:"depends_on,omitempty"`
+	Missing   bool            `json:"missing"`
+}
+
+// GroupTree is a group with its own projects and the trees of the groups it includes.
+type GroupTree struct {
+	Name     string        `json:"name"`
+	Projects []GroupMember `json:"projects"`
+	Groups   []GroupTree   `json:"groups,omitempty"`
+}
+
+// GroupRepair is a reference to a missing project, or to a missing included group, removed from a group by
+// RepairGroups.
+type GroupRepair struct {
+	Group         string
+	Project       string
+	IncludedGroup string
+}
+
+// ListGroups returns the sorted names of the groups.
+func (c *Client) ListGroups(ctx context.Context) ([]string, error) {
+	var res []string
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		res = pkg.NewGroupService(config).GetGroupNames()
+		sort.Strings(res)
+
+		return nil
+	})
+
+	return res, err
+}
+
+// GetGroup returns the group named name.
+func (c *Client) GetGroup(ctx context.Context, name string) (pkg.GroupConfig, error) {
+	var res pkg.GroupConfig
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		group, err := getGroup(config, name)
+		if err != nil {
+			return err
+		}
+
+		res = copyGroup(group)
+
+		return nil
+	})
+
+	return res, err
+}
+
+// GetGroupProjects returns the projects of the group followed by the projects of the groups it includes.
+func (c *Client) GetGroupProjects(ctx context.Context, name string) ([]string, error) {
+	var res []string
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		if _, err := getGroup(config, name); err != nil {
+			return err
+		}
+
+		projects, err := pkg.NewGroupService(config).GetGroupProjects(name)
+		res = projects
+
+		return err
+	})
+
+	return res, err
+}
+
+// GetGroupMembers returns the members of the projects of the group followed by the projects of the groups it includes.
+func (c *Client) GetGroupMembers(ctx context.Context, name string) ([]GroupMember, error) {
+	var res []GroupMember
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		if _, err := getGroup(config, name); err != nil {
+			return err
+		}
+
+		projects, err := pkg.NewGroupService(config).GetGroupProjects(name)
+		if err != nil {
+			return err
+		}
+
+		res = getGroupMembers(config, projects)
+
+		return nil
+	})
+
+	return res, err
+}
+
+// GetGroupTree returns the hierarchy of the groups included by the group. It fails if an included group does not
+// exist or a group includes itself.
+func (c *Client) GetGroupTree(ctx context.Context, name string) (GroupTree, error) {
+	var res GroupTree
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		if _, err := getGroup(config, name); err != nil {
+			return err
+		}
+
+		if err := pkg.NewGroupService(config).ValidateIncludes(name); err != nil {
+			return err
+		}
+
+		res = getGroupTree(config, name)
+
+		return nil
+	})
+
+	return res, err
+}
+
+// CreateGroup adds the group to the configuration. It fails if a group with the same name exists, or if a project
+// or an included group of the group does not exist.
+func (c *Client) CreateGroup(ctx context.Context, name string, group pkg.GroupConfig) error {
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		if _, err := pkg.NewGroupService(config).CreateGroup(name); err != nil {
+			return err
+		}
+
+		return setGroup(config, name, group)
+	})
+}
+
+// SetGroup adds the group to the configuration or replaces the group with the same name. It fails if a project or
+// an included group of the group does not exist.
+func (c *Client) SetGroup(ctx context.Context, name string, group pkg.GroupConfig) error {
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		return setGroup(config, name, group)
+	})
+}
+
+// DeleteGroup removes the group from the configuration and from the groups which include it.
+func (c *Client) DeleteGroup(ctx context.Context, name string) error {
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		if _, err := getGroup(config, name); err != nil {
+			return err
+		}
+
+		pkg.NewGroupService(config).DeleteGroup(name)
+
+		return nil
+	})
+}
+
+// RenameGroup renames the group, the references of the groups which include it and the workspace cloned from it.
+func (c *Client) RenameGroup(ctx context.Context, oldName string, newName string) error {
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		if _, err := getGroup(config, oldName); err != nil {
+			return err
+		}
+
+		return pkg.NewGroupService(config).RenameGroup(oldName, newName)
+	})
+}
+
+// RepairGroups removes the references to missing projects and to missing included groups from every group. The
+// removed references are returned sorted by group.
+func (c *Client) RepairGroups(ctx context.Context) ([]GroupRepair, error) {
+	var res []GroupRepair
+
+	err := c.update(ctx, func(config *pkg.WildFireConfig) error {
+		groupService := pkg.NewGroupService(config)
+
+		groupNames := groupService.GetGroupNames()
+		sort.Strings(groupNames)
+
+		for _, groupName := range groupNames {
+			group := groupService.GetGroup(groupName)
+			for _, projectName := range groupService.GetMissingProjects(group) {
+				group = groupService.RemoveProject(group, projectName)
+				res = append(res, GroupRepair{Group: groupName, Project: projectName})
+			}
+
+			for _, includedGroup := range append([]string{}, group.Groups...) {
+				if groupService.GetGroup(includedGroup) == nil {
+					group = groupService.ExcludeGroup(group, includedGroup)
+					res = append(res, GroupRepair{Group: groupName, IncludedGroup: includedGroup})
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// AddProjectsToGroup adds the projects to the group. No project is added if one of them does not exist.
+func (c *Client) AddProjectsToGroup(ctx context.Context, name string, projects ...string) error {
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		group, err := getGroup(config, name)
+		if err != nil {
+			return err
+		}
+
+		updated := copyGroup(group)
+		updated.Projects = append(updated.Projects, projects...)
+
+		return setGroup(config, name, updated)
+	})
+}
+
+// RemoveProjectsFromGroup removes the projects from the group. Projects which are not in the group are ignored.
+func (c *Client) RemoveProjectsFromGroup(ctx context.Context, name string, projects ...string) error {
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		group, err := getGroup(config, name)
+		if err != nil {
+			return err
+		}
+
+		groupService := pkg.NewGroupService(config)
+		for _, project := range projects {
+			groupService.RemoveProject(group, project)
+		}
+
+		return nil
+	})
+}
+
+// setGroup stores a copy of the group under the name. The previous group is restored if the group is invalid.
+func setGroup(config *pkg.WildFireConfig, name string, group pkg.GroupConfig) error {
+	groupService := pkg.NewGroupService(config)
+
+	stored := copyGroup(&group)
+	stored.Projects = nil
+	for _, project := range group.Projects {
+		if groupService.HasProject(&stored, project) == false {
+			stored.Projects = append(stored.Projects, project)
+		}
+	}
+
+	previous, exists := config.Groups[name]
+	config.Groups[name] = &stored
+
+	err := groupService.ValidateIncludes(name)
+	if missing := groupService.GetMissingProjects(&stored); err == nil && len(missing) != 0 {
+		err = fmt.Errorf("projects '%s' %w", strings.Join(missing, "', '"), ErrNotFound)
+	}
+
+	if err != nil {
+		if exists {
+			config.Groups[name] = previous
+		} else {
+			delete(config.Groups, name)
+		}
+	}
+
+	return err
+}
+
+func getGroupMembers(config *pkg.WildFireConfig, projects []string) []GroupMember {
+	projectService := pkg.NewProjectService(config)
+	res := []GroupMember{}
+
+	for _, projectName := range projects {
+		member := GroupMember{Name: projectName, Missing: true}
+		if project := projectService.GetProject(projectName); project != nil {
+			member.Type = project.Type
+			member.URL = project.URL
+			member.DependsOn = append([]string(nil), project.DependsOn...)
+			member.Missing = false
+		}
+
+		res = append(res, member)
+	}
+
+	return res
+}
+
+func getGroupTree(config *pkg.WildFireConfig, name string) GroupTree {
+	group := pkg.NewGroupService(config).GetGroup(name)
+	res := GroupTree{Name: name, Projects: getGroupMembers(config, group.Projects)}
+
+	for _, includedGroup := range group.Groups {
+		res.Groups = append(res.Groups, getGroupTree(config, includedGroup))
+	}
+
+	return res
+}
+
+func getGroup(config *pkg.WildFireConfig, name string) (*pkg.GroupConfig, error) {
+	group := pkg.NewGroupService(config).GetGroup(name)
+	if group == nil {
+		return nil, fmt.Errorf("group '%s' %w", name, ErrNotFound)
+	}
+
+	return group, nil
+}
+
+func copyGroup(group *pkg.GroupConfig) pkg.GroupConfig {
+	res := *group
+	res.Labels = append([]string(nil), group.Labels...)
+	res.Projects = append([]string{}, group.Projects...)
+	res.Groups = append([]string(nil), group.Groups...)
+
+	return res
+}
@@ -0,0 +1,84 @@
+package wildfire
+
+import (
+	"context"
+	"wildfire/pkg"
+)
+
+const (
+	ProjectStatusCloned    = "cloned"
+	ProjectStatusUpdated   = "updated"
+	ProjectStatusMatched   = "matched"
+	ProjectStatusSucceeded = "succeeded"
+	ProjectStatusSkipped   = "skipped"
+	ProjectStatusFailed    = "failed"
+)
+
+// ProjectEvent reports that an operation completed for a project. Status is one of the ProjectStatus constants.
+type ProjectEvent struct {
+	pkg.Clone
+	Status string
+	Output string
+	Err    error
+}
+
+// HistoryStatus returns the status recording the event in a pkg.HistoryEntry. Projects which have been cloned,
+// updated or matched succeeded.
+func (e ProjectEvent) HistoryStatus() pkg.HistoryStatus {
+	switch e.Status {
+	case ProjectStatusSkipped:
+		return pkg.HistoryStatusSkipped
+	case ProjectStatusFailed:
+		return pkg.HistoryStatusFailed
+	}
+
+	return pkg.HistoryStatusSucceeded
+}
+
+// ProgressFunc receives an event as soon as an operation completed for a project. It is called concurrently.
+type ProgressFunc func(event ProjectEvent)
+
+type progressKey struct{}
+
+// WithProgress returns a context with which Clone, Sync and Exec report the progress of every project to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func reportProgress(ctx context.Context, event ProjectEvent) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(event)
+	}
+}
+
+func reportCloneResult(ctx context.Context, result CloneResult, status string) {
+	switch {
+	case result.Err != nil:
+		status = ProjectStatusFailed
+	case result.Skipped:
+		status = ProjectStatusSkipped
+	}
+
+	reportProgress(ctx, ProjectEvent{Clone: result.Clone, Status: status, Output: result.Output, Err: result.Err})
+}
+
+func reportExecResult(ctx context.Context, result pkg.ExecResult, ran bool) {
+	status := ProjectStatusMatched
+	if ran {
+		status = ProjectStatusSucceeded
+	}
+
+	switch {
+	case result.Err != nil:
+		status = ProjectStatusFailed
+	case result.Skipped:
+		status = ProjectStatusSkipped
+	}
+
+	output := result.Output
+	if result.Skipped {
+		output = result.SkipReason
+	}
+
+	reportProgress(ctx, ProjectEvent{Clone: result.Clone, Status: status, Output: output, Err: result.Err})
+}
@@ -0,0 +1,244 @@
+package wildfire
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"wildfire/pkg"
+)
+
+// ProjectDetails is a project with the groups it belongs to and the paths of its clones by workspace name.
+type ProjectDetails struct {
+	pkg.ProjectConfig
+	Groups     []string          `json:"groups"`
+	Workspaces map[string]string `json:"workspaces"`
+}
+
+// ListProjects returns the projects matching the filter, sorted as requested by it.
+func (c *Client) ListProjects(ctx context.Context, filter pkg.ProjectFilter) ([]pkg.ProjectConfig, error) {
+	var res []pkg.ProjectConfig
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		projects, err := pkg.NewProjectService(config).FilterProjects(filter)
+		if err != nil {
+			return err
+		}
+
+		res = make([]pkg.ProjectConfig, 0, len(projects))
+		for _, project := range projects {
+			res = append(res, copyProject(project))
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// GetProject returns the project named name.
+func (c *Client) GetProject(ctx context.Context, name string) (pkg.ProjectConfig, error) {
+	var res pkg.ProjectConfig
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		project, err := getProject(config, name)
+		if err != nil {
+			return err
+		}
+
+		res = copyProject(project)
+
+		return nil
+	})
+
+	return res, err
+}
+
+// DescribeProject returns the project named name with the sorted names of its groups and its clones.
+func (c *Client) DescribeProject(ctx context.Context, name string) (ProjectDetails, error) {
+	var res ProjectDetails
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		project, err := getProject(config, name)
+		if err != nil {
+			return err
+		}
+
+		res = ProjectDetails{
+			ProjectConfig: copyProject(project),
+			Groups:        pkg.NewGroupService(config).GetProjectGroups(name),
+			Workspaces:    map[string]string{},
+		}
+		if res.Groups == nil {
+			res.Groups = []string{}
+		}
+
+		workspaceService := pkg.NewWorkspaceService(config)
+		for _, workspaceName := range workspaceService.GetProjectWorkspaces(name) {
+			workspace := workspaceService.GetWorkspace(workspaceName)
+			res.Workspaces[workspaceName] = workspaceService.GetClonePath(workspace, name)
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// AddProject adds the project to the configuration. It fails if a project with the same name exists.
+func (c *Client) AddProject(ctx context.Context, project pkg.ProjectConfig) error {
+	if err := validateProject(project); err != nil {
+		return err
+	}
+
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		added, err := pkg.NewProjectService(config).AddProject(project.Name, project.URL, project.Type)
+		if err != nil {
+			return err
+		}
+
+		added.Labels = append([]string(nil), project.Labels...)
+		added.DependsOn = append([]string(nil), project.DependsOn...)
+
+		return validateDependencies(config)
+	})
+}
+
+// SetProject adds the project to the configuration or replaces the project with the same name.
+func (c *Client) SetProject(ctx context.Context, project pkg.ProjectConfig) error {
+	if err := validateProject(project); err != nil {
+		return err
+	}
+
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		project := copyProject(&project)
+		pkg.NewProjectService(config).UpdateOrCreate(&project)
+
+		return validateDependencies(config)
+	})
+}
+
+// RenameProject renames the project in the configuration, its groups and the workspaces it has been cloned to. The
+// clones are moved to the new name, and moved back when the configuration can not be saved. In a dry run, see
+// pkg.RunOptions, the clones are left in place.
+func (c *Client) RenameProject(ctx context.Context, oldName string, newName string) error {
+	var moves []pkg.PlannedChange
+	dryRun := pkg.IsDryRun(ctx)
+
+	err := c.update(ctx, func(config *pkg.WildFireConfig) error {
+		if _, err := getProject(config, oldName); err != nil {
+			return err
+		}
+
+		projectService := pkg.NewProjectService(config)
+		planned, err := projectService.PlanRenameProject(oldName, newName)
+		if err != nil {
+			return err
+		}
+
+		if err := projectService.RenameProject(oldName, newName, dryRun); err != nil {
+			return err
+		}
+		moves = planned
+
+		return nil
+	})
+	if err != nil && dryRun == false {
+		pkg.RestoreClones(moves)
+	}
+
+	return err
+}
+
+// PlanRenameProject returns the moves of the clones renaming the project would make.
+func (c *Client) PlanRenameProject(ctx context.Context, oldName string, newName string) ([]pkg.PlannedChange, error) {
+	var res []pkg.PlannedChange
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		if _, err := getProject(config, oldName); err != nil {
+			return err
+		}
+
+		var err error
+		res, err = pkg.NewProjectService(config).PlanRenameProject(oldName, newName)
+
+		return err
+	})
+
+	return res, err
+}
+
+// RemoveProject removes the project from the configuration, from every group and from the dependencies of the other
+// projects. It returns the names of the groups the project has been removed from.
+func (c *Client) RemoveProject(ctx context.Context, name string) ([]string, error) {
+	var groups []string
+
+	err := c.update(ctx, func(config *pkg.WildFireConfig) error {
+		if _, err := getProject(config, name); err != nil {
+			return err
+		}
+
+		groupService := pkg.NewGroupService(config)
+		groups = groupService.GetProjectGroups(name)
+		for _, groupName := range groups {
+			groupService.RemoveProject(groupService.GetGroup(groupName), name)
+		}
+
+		pkg.NewProjectService(config).RemoveProject(name)
+		for _, project := range config.Projects {
+			var dependencies []string
+			for _, dependency := range project.DependsOn {
+				if dependency != name {
+					dependencies = append(dependencies, dependency)
+				}
+			}
+			project.DependsOn = dependencies
+		}
+
+		return nil
+	})
+
+	return groups, err
+}
+
+func getProject(config *pkg.WildFireConfig, name string) (*pkg.ProjectConfig, error) {
+	project := pkg.NewProjectService(config).GetProject(name)
+	if project == nil {
+		return nil, fmt.Errorf("project '%s' %w", name, ErrNotFound)
+	}
+
+	return project, nil
+}
+
+func validateProject(project pkg.ProjectConfig) error {
+	if project.Name == "" {
+		return errors.New("a project name is required")
+	}
+	if project.Type.ValidType() == false {
+		return fmt.Errorf("invalid project type '%s'", project.Type)
+	}
+	for _, dependency := range project.DependsOn {
+		if dependency == project.Name {
+			return fmt.Errorf("project '%s' can not depend on itself", project.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateDependencies returns a pkg.DependencyCycleError when projects of the configuration depend on each other.
+func validateDependencies(config *pkg.WildFireConfig) error {
+	projects := pkg.NewProjectService(config).GetProjectNames()
+	if cycle := pkg.GetProjectDependencies(config, projects).Cycle(); cycle != nil {
+		return &pkg.DependencyCycleError{Cycle: cycle}
+	}
+
+	return nil
+}
+
+func copyProject(project *pkg.ProjectConfig) pkg.ProjectConfig {
+	res := *project
+	res.Labels = append([]string(nil), project.Labels...)
+	res.DependsOn = append([]string(nil), project.DependsOn...)
+
+	return res
+}
@@ -0,0 +1,146 @@
+package wildfire
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"wildfire/pkg"
+)
+
+// GetWorkspace returns the workspace with the name, or the workspace cloned from the group with the name.
+func (c *Client) GetWorkspace(ctx context.Context, name string) (pkg.WorkspaceConfig, error) {
+	var res pkg.WorkspaceConfig
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		workspace, err := findWorkspace(config, name)
+		if err != nil {
+			return err
+		}
+
+		res = *workspace
+		res.Projects = append([]string{}, workspace.Projects...)
+
+		return nil
+	})
+
+	return res, err
+}
+
+// SetWorkspace adds the workspace to the configuration or replaces the workspace with the same name.
+func (c *Client) SetWorkspace(ctx context.Context, name string, workspace pkg.WorkspaceConfig) error {
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		workspace.Projects = append([]string{}, workspace.Projects...)
+		pkg.NewWorkspaceService(config).SetWorkspace(name, &workspace)
+
+		return nil
+	})
+}
+
+// DeleteWorkspace removes the workspace with the name from the configuration. The clones are left in place.
+func (c *Client) DeleteWorkspace(ctx context.Context, name string) error {
+	return c.update(ctx, func(config *pkg.WildFireConfig) error {
+		workspaceService := pkg.NewWorkspaceService(config)
+		if workspaceService.GetWorkspace(name) == nil {
+			return fmt.Errorf("workspace '%s' %w", name, ErrNotFound)
+		}
+
+		workspaceService.DeleteWorkspace(name)
+
+		return nil
+	})
+}
+
+// Search searches the clones of the workspace, or of the workspace cloned from the group, with the name in parallel
+// for the lines matching the pattern, see pkg.SearchClones. The results are in the order of the projects.
+func (c *Client) Search(ctx context.Context, name string, pattern *regexp.Regexp, ref string) ([]pkg.SearchResult, error) {
+	clones, err := c.getClones(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg.SearchClones(clones, pattern, ref), nil
+}
+
+// Edit runs the editor in parallel in the clones of the workspace, or of the workspace cloned from the group, with
+// the name which match every predicate. In a dry run the changed files are reported without being written. The
+// results are in the order of the projects.
+func (c *Client) Edit(
+	ctx context.Context,
+	name string,
+	predicates []pkg.ClonePredicate,
+	editor pkg.Editor,
+) ([]pkg.EditResult, error) {
+	clones, err := c.getClones(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg.EditClones(pkg.SelectClones(ctx, clones), predicates, editor, pkg.IsDryRun(ctx)), nil
+}
+
+// ApplyPatch applies the patch file in parallel to the clones of the workspace, or of the workspace cloned from the
+// group, with the name which match every predicate, see pkg.ApplyPatchToClones. With check, and in a dry run, the
+// clones are left unchanged and only whether the patch applies is reported.
+func (c *Client) ApplyPatch(
+	ctx context.Context,
+	name string,
+	predicates []pkg.ClonePredicate,
+	patchFile string,
+	check bool,
+) ([]pkg.PatchResult, error) {
+	clones, err := c.getClones(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg.ApplyPatchToClones(pkg.SelectClones(ctx, clones), predicates, patchFile, check || pkg.IsDryRun(ctx))
+}
+
+// CapturePatch returns the changes of the clone of the project in the workspace with the name as a patch, see
+// pkg.CapturePatch.
+func (c *Client) CapturePatch(ctx context.Context, name string, projectName string, since string) (string, error) {
+	var path string
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		workspace, err := findWorkspace(config, name)
+		if err != nil {
+			return err
+		}
+
+		path = pkg.NewWorkspaceService(config).GetClonePath(workspace, projectName)
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return pkg.CapturePatch(path, since)
+}
+
+// getClones returns the clones of the workspace, or of the workspace cloned from the group, with the name.
+func (c *Client) getClones(ctx context.Context, name string) ([]pkg.Clone, error) {
+	var res []pkg.Clone
+
+	err := c.read(ctx, func(config *pkg.WildFireConfig) error {
+		workspace, err := findWorkspace(config, name)
+		if err != nil {
+			return err
+		}
+
+		res = pkg.NewWorkspaceService(config).GetClones(workspace)
+
+		return nil
+	})
+
+	return res, err
+}
+
+func findWorkspace(config *pkg.WildFireConfig, name string) (*pkg.WorkspaceConfig, error) {
+	workspace := pkg.NewWorkspaceService(config).FindWorkspace(name)
+	if workspace == nil {
+		return nil, fmt.Errorf("workspace '%s' %w", name, ErrNotFound)
+	}
+
+	return workspace, nil
+}